@@ -0,0 +1,81 @@
+// Package apidoc meng-embed spesifikasi OpenAPI dan halaman Swagger UI ke
+// binary, supaya dokumentasi selalu ikut versi server yang berjalan.
+package apidoc
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed openapi.yaml
+var spec []byte
+
+//go:embed swagger.html
+var swaggerUI []byte
+
+//go:embed swagger-init.js
+var swaggerInit []byte
+
+// CSP untuk halaman Swagger UI: script dan stylesheet hanya dari server ini dan
+// unpkg, tanpa script inline. Swagger UI memakai atribut style sehingga
+// style-src tetap butuh 'unsafe-inline'.
+const CSP = "default-src 'none'; " +
+	"script-src 'self' https://unpkg.com; " +
+	"style-src 'self' 'unsafe-inline' https://unpkg.com; " +
+	"img-src 'self' data:; " +
+	"connect-src 'self'; " +
+	"frame-ancestors 'none'; base-uri 'none'; form-action 'none'"
+
+// YAML mengembalikan spesifikasi OpenAPI apa adanya.
+func YAML() []byte { return spec }
+
+// SwaggerUI mengembalikan halaman HTML Swagger UI yang membaca /docs/openapi.yaml.
+func SwaggerUI() []byte { return swaggerUI }
+
+// SwaggerInit mengembalikan script inisialisasi Swagger UI; dipisah dari HTML
+// supaya CSP tidak perlu mengizinkan script inline.
+func SwaggerInit() []byte { return swaggerInit }
+
+var (
+	jsonOnce sync.Once
+	jsonSpec []byte
+	jsonErr  error
+)
+
+// JSON mengembalikan spesifikasi yang sama dalam format JSON; dikonversi sekali
+// lalu di-cache.
+func JSON() ([]byte, error) {
+	jsonOnce.Do(func() {
+		var doc any
+		if jsonErr = yaml.Unmarshal(spec, &doc); jsonErr != nil {
+			return
+		}
+		jsonSpec, jsonErr = json.Marshal(doc)
+	})
+	return jsonSpec, jsonErr
+}
+
+// Paths mengembalikan method per path yang terdokumentasi, mis.
+// "/api/v1/attendance/check-in" → ["POST"]. Dipakai test untuk mendeteksi drift.
+func Paths() (map[string][]string, error) {
+	var doc struct {
+		Paths map[string]map[string]yaml.Node `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, err
+	}
+	out := make(map[string][]string, len(doc.Paths))
+	for path, ops := range doc.Paths {
+		for method := range ops {
+			switch method {
+			case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+				out[path] = append(out[path], strings.ToUpper(method))
+			}
+		}
+	}
+	return out, nil
+}
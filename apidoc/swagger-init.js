@@ -0,0 +1,8 @@
+window.onload = function () {
+  window.ui = SwaggerUIBundle({
+    url: "/docs/openapi.yaml",
+    dom_id: "#swagger-ui",
+    persistAuthorization: true,
+    validatorUrl: null
+  });
+};
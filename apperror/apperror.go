@@ -0,0 +1,114 @@
+// Package apperror berisi error domain bertipe. Service mengembalikan *Error
+// dengan Code; middleware.Errors menerjemahkan Code ke status HTTP, sehingga
+// handler tidak perlu mencocokkan teks pesan.
+package apperror
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+
+	"mojo-autotech/i18n"
+)
+
+type Code string
+
+const (
+	CodeValidation    Code = "VALIDATION"
+	CodeUnauthorized  Code = "UNAUTHORIZED"
+	CodeForbidden     Code = "FORBIDDEN"
+	CodeNotFound      Code = "NOT_FOUND"
+	CodeConflict      Code = "CONFLICT"
+	CodeUnprocessable Code = "UNPROCESSABLE"
+	CodeLocked        Code = "LOCKED"
+	CodeTooLarge      Code = "PAYLOAD_TOO_LARGE"
+	CodeRateLimited   Code = "RATE_LIMITED"
+	CodeInternal      Code = "INTERNAL"
+)
+
+var statusByCode = map[Code]int{
+	CodeValidation:    http.StatusBadRequest,
+	CodeUnauthorized:  http.StatusUnauthorized,
+	CodeForbidden:     http.StatusForbidden,
+	CodeNotFound:      http.StatusNotFound,
+	CodeConflict:      http.StatusConflict,
+	CodeUnprocessable: http.StatusUnprocessableEntity,
+	CodeLocked:        http.StatusLocked,
+	CodeTooLarge:      http.StatusRequestEntityTooLarge,
+	CodeRateLimited:   http.StatusTooManyRequests,
+	CodeInternal:      http.StatusInternalServerError,
+}
+
+type Error struct {
+	Code Code
+	Key  i18n.Key // pesan untuk client, diterjemahkan sesuai Accept-Language
+	Args []any    // argumen format untuk Key
+	Err  error    // penyebab asli, hanya untuk log
+}
+
+// Error memakai bahasa default; response HTTP menerjemahkan ulang lewat Key.
+func (e *Error) Error() string {
+	return i18n.T(i18n.Default, e.Key, e.Args...)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// With mengembalikan salinan dengan argumen format, aman dipakai pada error sentinel.
+func (e *Error) With(args ...any) *Error {
+	c := *e
+	c.Args = args
+	return &c
+}
+
+func New(code Code, key i18n.Key) *Error {
+	return &Error{Code: code, Key: key}
+}
+
+func Wrap(code Code, key i18n.Key, err error) *Error {
+	return &Error{Code: code, Key: key, Err: err}
+}
+
+func Validation(key i18n.Key) *Error   { return New(CodeValidation, key) }
+func Unauthorized(key i18n.Key) *Error { return New(CodeUnauthorized, key) }
+func Forbidden(key i18n.Key) *Error    { return New(CodeForbidden, key) }
+func NotFound(key i18n.Key) *Error     { return New(CodeNotFound, key) }
+func Conflict(key i18n.Key) *Error     { return New(CodeConflict, key) }
+func Locked(key i18n.Key) *Error       { return New(CodeLocked, key) }
+
+// Invalid membungkus error binding/parsing request sebagai VALIDATION, atau
+// PAYLOAD_TOO_LARGE kalau body terpotong http.MaxBytesReader. Detail per field
+// dari validator diterjemahkan oleh middleware.Errors.
+func Invalid(err error) *Error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return Wrap(CodeTooLarge, i18n.BodyTooLarge, err)
+	}
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		return Wrap(CodeValidation, i18n.ReqValidation, err)
+	}
+	return Wrap(CodeValidation, i18n.ReqMalformed, err)
+}
+
+// As mencari *Error di rantai err.
+func As(err error, target **Error) bool {
+	return errors.As(err, target)
+}
+
+// CodeOf mengambil Code dari rantai error; error yang tidak dikenal dianggap INTERNAL.
+func CodeOf(err error) Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return CodeInternal
+}
+
+// Status memetakan Code ke status HTTP.
+func Status(code Code) int {
+	if s, ok := statusByCode[code]; ok {
+		return s
+	}
+	return http.StatusInternalServerError
+}
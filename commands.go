@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"mojo-autotech/config"
+	"mojo-autotech/migration"
+	tenantRepo "mojo-autotech/model/tenant"
+	authRepo "mojo-autotech/model/user_authentication"
+	wlSvc "mojo-autotech/service/work_location"
+	"mojo-autotech/tenant"
+)
+
+// bootstrap membuka DB dan membangun service untuk subcommand CLI. Sama seperti
+// server, CLI menolak jalan kalau skema belum terbaru.
+func bootstrap() *services {
+	cfg := loadConfig(config.Flags{})
+	db, err := config.NewDB(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect database: %v", err)
+	}
+	m, err := migration.New(db)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	if err := m.EnsureCurrent(context.Background()); err != nil {
+		log.Fatalf("Refusing to run: %v", err)
+	}
+	return newServices(db, cfg)
+}
+
+// withTenant mengembalikan ctx untuk tenant dengan kode code (kosong = tenant
+// default dari config); CLI berhenti kalau tenant tidak ada.
+func withTenant(ctx context.Context, svc *services, code string) context.Context {
+	t, err := svc.tenants.Resolve(ctx, code)
+	if err != nil {
+		log.Fatalf("tenant %q: %v", code, err)
+	}
+	return tenant.WithID(ctx, t.ID)
+}
+
+const tenantUsage = `usage: mojo-autotech tenant <command> [flags]
+
+commands:
+  create  --code --name
+  list`
+
+func runTenant(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, tenantUsage)
+		os.Exit(2)
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("tenant create", flag.ExitOnError)
+		code := fs.String("code", "", "kode tenant yang dikirim client saat login, mis. mojo-cikarang (wajib)")
+		name := fs.String("name", "", "nama perusahaan (wajib)")
+		_ = fs.Parse(args[1:])
+		if *code == "" || *name == "" {
+			fs.Usage()
+			os.Exit(2)
+		}
+
+		t, err := bootstrap().tenants.Create(ctx, tenantRepo.CreateTenantReq{Code: *code, Name: *name})
+		if err != nil {
+			log.Fatalf("tenant create: %v", err)
+		}
+		fmt.Printf("tenant %q dibuat (id=%d)\n", t.Code, t.ID)
+		fmt.Printf("buat admin-nya dengan: mojo-autotech user create-admin --tenant %s --username ... --email ...\n", t.Code)
+
+	case "list":
+		list, err := bootstrap().tenants.List(ctx)
+		if err != nil {
+			log.Fatalf("tenant list: %v", err)
+		}
+		for _, t := range list {
+			status := "aktif"
+			if !t.IsActive {
+				status = "nonaktif"
+			}
+			fmt.Printf("%d\t%s\t%s\t%s\n", t.ID, t.Code, t.Name, status)
+		}
+
+	default:
+		fmt.Fprintln(os.Stderr, tenantUsage)
+		os.Exit(2)
+	}
+}
+
+const userUsage = `usage: mojo-autotech user <command> [flags]
+
+commands:
+  create-admin    [--tenant] --username --email [--full-name] [--employee-id] [--password]
+  reset-password  [--tenant] --username [--password]
+  deactivate      [--tenant] --username
+
+--tenant kosong = tenant default (config tenant.default).`
+
+func runUser(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, userUsage)
+		os.Exit(2)
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "create-admin":
+		fs := flag.NewFlagSet("user create-admin", flag.ExitOnError)
+		tenantCode := fs.String("tenant", "", "kode tenant")
+		username := fs.String("username", "", "username admin (wajib)")
+		email := fs.String("email", "", "email admin (wajib)")
+		fullName := fs.String("full-name", "Administrator", "nama lengkap")
+		employeeID := fs.Uint("employee-id", 0, "nomor karyawan (users.user_id)")
+		password := fs.String("password", "", "password; kosong = dibuat acak dan dicetak sekali")
+		_ = fs.Parse(args[1:])
+		if *username == "" || *email == "" {
+			fs.Usage()
+			os.Exit(2)
+		}
+
+		pass, generated := passwordOrRandom(*password)
+		svc := bootstrap()
+		// admin pertama sebuah tenant hanya bisa dibuat dari sini; /create
+		// butuh token ADMIN di tenant yang sama
+		u, err := svc.auth.CreateAccount(withTenant(ctx, svc, *tenantCode), authRepo.RegisterReq{
+			UserId:   *employeeID,
+			Username: *username,
+			Email:    *email,
+			FullName: *fullName,
+			Password: pass,
+			Role:     "ADMIN",
+		})
+		if err != nil {
+			log.Fatalf("user create-admin: %v", err)
+		}
+		fmt.Printf("admin %q dibuat (id=%d)\n", u.Username, u.ID)
+		if generated {
+			fmt.Printf("password: %s\n", pass)
+		}
+
+	case "reset-password":
+		fs := flag.NewFlagSet("user reset-password", flag.ExitOnError)
+		tenantCode := fs.String("tenant", "", "kode tenant")
+		username := fs.String("username", "", "username (wajib)")
+		password := fs.String("password", "", "password baru; kosong = dibuat acak dan dicetak sekali")
+		_ = fs.Parse(args[1:])
+		if *username == "" {
+			fs.Usage()
+			os.Exit(2)
+		}
+
+		pass, generated := passwordOrRandom(*password)
+		svc := bootstrap()
+		if err := svc.auth.ResetPassword(withTenant(ctx, svc, *tenantCode), *username, pass); err != nil {
+			log.Fatalf("user reset-password: %v", err)
+		}
+		fmt.Printf("password %q diganti\n", *username)
+		if generated {
+			fmt.Printf("password: %s\n", pass)
+		}
+
+	case "deactivate":
+		fs := flag.NewFlagSet("user deactivate", flag.ExitOnError)
+		tenantCode := fs.String("tenant", "", "kode tenant")
+		username := fs.String("username", "", "username (wajib)")
+		_ = fs.Parse(args[1:])
+		if *username == "" {
+			fs.Usage()
+			os.Exit(2)
+		}
+
+		svc := bootstrap()
+		if err := svc.auth.Deactivate(withTenant(ctx, svc, *tenantCode), *username); err != nil {
+			log.Fatalf("user deactivate: %v", err)
+		}
+		fmt.Printf("user %q dinonaktifkan\n", *username)
+
+	default:
+		fmt.Fprintln(os.Stderr, userUsage)
+		os.Exit(2)
+	}
+}
+
+const attendanceUsage = `usage: mojo-autotech attendance recompute [--tenant] --from YYYY-MM-DD --to YYYY-MM-DD`
+
+func runAttendance(args []string) {
+	if len(args) == 0 || args[0] != "recompute" {
+		fmt.Fprintln(os.Stderr, attendanceUsage)
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("attendance recompute", flag.ExitOnError)
+	tenantCode := fs.String("tenant", "", "kode tenant; kosong = tenant default")
+	fromStr := fs.String("from", "", "tanggal awal YYYY-MM-DD (wajib)")
+	toStr := fs.String("to", "", "tanggal akhir YYYY-MM-DD (wajib)")
+	_ = fs.Parse(args[1:])
+
+	from, err1 := time.Parse("2006-01-02", *fromStr)
+	to, err2 := time.Parse("2006-01-02", *toStr)
+	if err1 != nil || err2 != nil {
+		fmt.Fprintln(os.Stderr, attendanceUsage)
+		os.Exit(2)
+	}
+
+	svc := bootstrap()
+	n, err := svc.attendance.Recompute(withTenant(context.Background(), svc, *tenantCode), from, to)
+	if err != nil {
+		log.Fatalf("attendance recompute: %v", err)
+	}
+	fmt.Printf("%d baris absensi dihitung ulang (%s s/d %s)\n", n, *fromStr, *toStr)
+}
+
+// runSeed mengisi data awal untuk development di tenant default. Aman
+// dijalankan berulang: data yang sudah ada dilewati.
+func runSeed(args []string) {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	password := fs.String("password", "password123", "password untuk akun contoh")
+	_ = fs.Parse(args)
+
+	svc := bootstrap()
+	ctx := withTenant(context.Background(), svc, "")
+
+	users := []authRepo.RegisterReq{
+		{UserId: 1, Username: "admin", Email: "admin@mojo.local", FullName: "Administrator", Role: "ADMIN"},
+		{UserId: 1001, Username: "teknisi1", Email: "teknisi1@mojo.local", FullName: "Teknisi Satu", Role: "EMPLOYEE"},
+		{UserId: 1002, Username: "teknisi2", Email: "teknisi2@mojo.local", FullName: "Teknisi Dua", Role: "EMPLOYEE"},
+	}
+	for _, u := range users {
+		u.Password = *password
+		if _, err := svc.auth.CreateAccount(ctx, u); err != nil {
+			fmt.Printf("skip user %s: %v\n", u.Username, err)
+			continue
+		}
+		fmt.Printf("user %s dibuat\n", u.Username)
+	}
+
+	existing, err := svc.workLocation.List(ctx)
+	if err != nil {
+		log.Fatalf("seed: %v", err)
+	}
+	if len(existing) > 0 {
+		fmt.Println("skip lokasi kerja: sudah ada data")
+		return
+	}
+	lat, lng := -6.2607, 107.1520
+	loc, err := svc.workLocation.Create(ctx, wlSvc.WorkLocationReq{
+		Name:    "Workshop Utama",
+		Address: "Cikarang, Bekasi",
+		Lat:     &lat,
+		Lng:     &lng,
+		RadiusM: 200,
+	})
+	if err != nil {
+		log.Fatalf("seed: %v", err)
+	}
+	fmt.Printf("lokasi kerja %q dibuat (id=%d)\n", loc.Name, loc.ID)
+}
+
+func passwordOrRandom(p string) (string, bool) {
+	if p != "" {
+		if len(p) < 8 {
+			log.Fatal("password minimal 8 karakter")
+		}
+		return p, false
+	}
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatalf("generate password: %v", err)
+	}
+	return hex.EncodeToString(b), true
+}
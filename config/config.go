@@ -1,28 +1,632 @@
 package config
 
 import (
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // lihat Location
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
-func NewConfig() *Config {
-	err := godotenv.Load(".env")
-	if err != nil {
-		log.Fatal("Error loading .env file")
-	}
+// Flags adalah layer terakhir (prioritas tertinggi) dari command line.
+type Flags struct {
+	File string
+	Env  string
+	Host string
+	Port string
+}
+
+// Register mendaftarkan flag konfigurasi ke fs.
+func (f *Flags) Register(fs *flag.FlagSet) {
+	fs.StringVar(&f.File, "config", "", "file konfigurasi YAML (default: $CONFIG_FILE)")
+	fs.StringVar(&f.Env, "env", "", "environment: development, staging, production")
+	fs.StringVar(&f.Host, "host", "", "alamat listen server")
+	fs.StringVar(&f.Port, "port", "", "port server")
+}
+
+// Default adalah layer pertama; nilai yang cocok untuk development lokal.
+func Default() *Config {
 	return &Config{
+		Env:      "development",
+		Timezone: "Asia/Jakarta",
 		Db: Database{
-			Host: os.Getenv("DB_HOST"),
-			Port: os.Getenv("DB_PORT"),
-			User: os.Getenv("DB_USER"),
-			Pass: os.Getenv("DB_PASSWORD"),
-			Name: os.Getenv("DB_NAME"),
+			Host:             "localhost",
+			Port:             "5432",
+			User:             "postgres",
+			Name:             "mojo_db",
+			MaxOpenConns:     20,
+			MaxIdleConns:     5,
+			ConnMaxLifetime:  30 * time.Minute,
+			ConnMaxIdleTime:  5 * time.Minute,
+			StatementTimeout: 10 * time.Second,
 		},
 		Srv: Server{
-			Host: os.Getenv("SERVER_HOST"),
-			Port: os.Getenv("SERVER_PORT"),
+			Host:              "0.0.0.0",
+			Port:              "8000",
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      60 * time.Second, // export payroll bisa besar
+			IdleTimeout:       120 * time.Second,
+			ShutdownTimeout:   20 * time.Second,
+			DrainDelay:        5 * time.Second,
+			MaxHeaderBytes:    1 << 20,
+			MaxBodyBytes:      2 << 20,
+		},
+		Tenant: Tenant{
+			Default: "default",
+		},
+		JWT: JWT{
+			Issuer:     "mojo-autotech",
+			AccessTTL:  15 * time.Minute,
+			RefreshTTL: 7 * 24 * time.Hour,
+		},
+		Login: Login{
+			MaxFailed: 5,
+			Lockout:   15 * time.Minute,
+		},
+		CORS: CORS{
+			// AllowOrigins diisi applyEnvDefaults sesuai environment
+			AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+			AllowHeaders: []string{"Origin", "Content-Type", "Accept", "Accept-Language", "Authorization",
+				"X-Kiosk-Key", "X-Request-ID", "Idempotency-Key", "traceparent", "tracestate"},
+			MaxAge: 12 * time.Hour,
+		},
+		Security: Security{
+			HSTSMaxAge:   180 * 24 * time.Hour,
+			FrameOptions: "DENY",
+		},
+		Sync: Sync{
+			SkewTolerance: 2 * time.Minute,
+			MaxAge:        72 * time.Hour,
+		},
+		Kiosk: Kiosk{
+			QRTTL:        30 * time.Second,
+			PINMaxFailed: 5,
+			PINLockout:   15 * time.Minute,
+		},
+		Shift: Shift{
+			Start:     8 * time.Hour,
+			LateGrace: 15 * time.Minute,
+		},
+		Schedule: Schedule{
+			AutoCheckoutInterval: 15 * time.Minute,
+			AutoCheckoutAt:       17 * time.Hour,
+		},
+		Features: Features{
+			Kiosk:            true,
+			OfflineSync:      true,
+			AnomalyDetection: true,
+			APIDocs:          true,
+			LegacyRoutes:     true,
+		},
+		Log: Log{
+			Level:     "info",
+			Format:    "json",
+			SlowQuery: 200 * time.Millisecond,
+		},
+		Metrics: Metrics{
+			Enabled: true,
+			Listen:  ":9091",
+		},
+		Tracing: Tracing{
+			Exporter:    "none",
+			SampleRatio: 1,
+		},
+		RateLimit: RateLimit{
+			Enabled: true,
+			Backend: "memory",
+			API:     Rule{Burst: 300, Refill: 100 * time.Millisecond},
+			Login:   Rule{Burst: 20, Refill: 10 * time.Second},
+			CheckIn: Rule{Burst: 5, Refill: time.Minute},
+			Badge:   Rule{Burst: 5, Refill: time.Minute},
 		},
+		Idempotency: Idempotency{
+			Enabled:         true,
+			TTL:             24 * time.Hour,
+			LockTimeout:     time.Minute,
+			CleanupInterval: time.Hour,
+		},
+	}
+}
+
+// Load menyusun konfigurasi berlapis: default → file YAML (opsional) →
+// environment variable → flag, lalu memvalidasinya. File .env di working
+// directory dibaca kalau ada, tapi tidak wajib (env dari container tetap menang).
+func Load(f Flags) (*Config, error) {
+	if err := godotenv.Load(".env"); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("config: baca .env: %w", err)
+	}
+
+	cfg := Default()
+
+	file := f.File
+	if file == "" {
+		file = os.Getenv("CONFIG_FILE")
+	}
+	if file != "" {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("config: baca %s: %w", file, err)
+		}
+		dec := yaml.NewDecoder(strings.NewReader(string(raw)))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil {
+			return nil, fmt.Errorf("config: parse %s: %w", file, err)
+		}
+	}
+
+	env := &envLoader{}
+	env.apply(cfg)
+	if len(env.errs) > 0 {
+		return nil, listError("config: environment tidak valid", env.errs)
+	}
+
+	if f.Env != "" {
+		cfg.Env = f.Env
+	}
+	if f.Host != "" {
+		cfg.Srv.Host = f.Host
+	}
+	if f.Port != "" {
+		cfg.Srv.Port = f.Port
+	}
+	cfg.applyEnvDefaults()
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyEnvDefaults mengisi nilai yang default-nya bergantung pada environment
+// dan belum diisi lewat file/env. Dipanggil setelah flag karena -env bisa
+// mengganti environment.
+func (c *Config) applyEnvDefaults() {
+	// nil = tidak disebut sama sekali; list kosong dari file/env tetap dihormati
+	if c.CORS.AllowOrigins == nil && c.Env == "development" {
+		c.CORS.AllowOrigins = []string{"*"}
+	}
+}
+
+// Validate memeriksa seluruh nilai dan mengumpulkan semua kesalahan sekaligus.
+func (c *Config) Validate() error {
+	var errs []error
+	bad := func(format string, args ...any) { errs = append(errs, fmt.Errorf(format, args...)) }
+
+	switch c.Env {
+	case "development", "staging", "production":
+	default:
+		bad("env harus development, staging, atau production (sekarang %q)", c.Env)
+	}
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		bad("timezone %q tidak dikenal: %v", c.Timezone, err)
+	} else if strings.EqualFold(c.Timezone, "Local") {
+		// nama zona dikirim ke SQL; Postgres tidak mengenal "Local"
+		bad("timezone harus nama IANA (mis. Asia/Jakarta), bukan Local")
+	}
+
+	if c.Db.Host == "" || c.Db.User == "" || c.Db.Name == "" {
+		bad("db.host, db.user, dan db.name wajib diisi")
+	}
+	if _, err := strconv.Atoi(c.Db.Port); err != nil {
+		bad("db.port %q bukan angka", c.Db.Port)
+	}
+	if c.Db.MaxOpenConns < 1 {
+		bad("db.max_open_conns minimal 1")
+	}
+	if c.Db.MaxIdleConns < 0 || c.Db.MaxIdleConns > c.Db.MaxOpenConns {
+		bad("db.max_idle_conns harus di antara 0 dan max_open_conns (%d)", c.Db.MaxOpenConns)
+	}
+	if c.Db.StatementTimeout < 0 {
+		bad("db.statement_timeout tidak boleh negatif")
+	}
+
+	if _, err := strconv.Atoi(c.Srv.Port); err != nil {
+		bad("server.port %q bukan angka", c.Srv.Port)
+	}
+	if c.Srv.ReadTimeout <= 0 || c.Srv.ReadHeaderTimeout <= 0 || c.Srv.WriteTimeout <= 0 || c.Srv.IdleTimeout <= 0 {
+		bad("server.*_timeout harus > 0")
+	}
+	if c.Srv.ShutdownTimeout <= 0 {
+		bad("server.shutdown_timeout harus > 0")
+	}
+	if c.Srv.DrainDelay < 0 {
+		bad("server.drain_delay tidak boleh negatif")
+	}
+	if c.Srv.MaxHeaderBytes < 4<<10 {
+		bad("server.max_header_bytes minimal 4096")
+	}
+	if c.Srv.MaxBodyBytes < 1<<10 {
+		bad("server.max_body_bytes minimal 1024")
+	}
+
+	if c.JWT.Secret == "" {
+		bad("jwt.secret wajib diisi (AUTH_JWT_SECRET)")
+	} else if c.Env == "production" && len(c.JWT.Secret) < 32 {
+		bad("jwt.secret minimal 32 karakter di production")
+	}
+	if c.JWT.AccessTTL <= 0 || c.JWT.RefreshTTL <= 0 {
+		bad("jwt.access_ttl dan jwt.refresh_ttl harus > 0")
+	}
+	if c.JWT.RefreshTTL < c.JWT.AccessTTL {
+		bad("jwt.refresh_ttl tidak boleh lebih pendek dari access_ttl")
+	}
+
+	if c.Login.MaxFailed < 0 {
+		bad("login.max_failed tidak boleh negatif")
+	}
+	if c.Login.MaxFailed > 0 && c.Login.Lockout <= 0 {
+		bad("login.lockout harus > 0 kalau login.max_failed diisi")
+	}
+	if c.Kiosk.PINMaxFailed < 0 {
+		bad("kiosk.pin_max_failed tidak boleh negatif")
+	}
+	if c.Kiosk.PINMaxFailed > 0 && c.Kiosk.PINLockout <= 0 {
+		bad("kiosk.pin_lockout harus > 0 kalau kiosk.pin_max_failed diisi")
+	}
+
+	for _, o := range c.CORS.AllowOrigins {
+		if o == "*" {
+			if c.Env != "development" {
+				bad("cors.allow_origins \"*\" hanya boleh di development")
+			}
+			if c.CORS.AllowCredentials {
+				bad("cors.allow_credentials tidak bisa dipakai bersama origin \"*\"")
+			}
+			continue
+		}
+		if u, err := url.Parse(o); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			bad("cors.allow_origins: %q harus berbentuk scheme://host[:port]", o)
+		}
+	}
+	if len(c.CORS.AllowOrigins) > 0 && len(c.CORS.AllowMethods) == 0 {
+		bad("cors.allow_methods minimal satu method")
+	}
+	if c.CORS.MaxAge < 0 {
+		bad("cors.max_age tidak boleh negatif")
+	}
+	if c.Security.HSTSMaxAge < 0 {
+		bad("security.hsts_max_age tidak boleh negatif")
+	}
+	switch c.Security.FrameOptions {
+	case "DENY", "SAMEORIGIN":
+	default:
+		bad("security.frame_options harus DENY atau SAMEORIGIN (sekarang %q)", c.Security.FrameOptions)
+	}
+	for group, n := range c.Srv.BodyLimits {
+		if !slices.Contains(BodyLimitGroups, group) {
+			bad("server.body_limits: group %q tidak dikenal (pilihan: %s)", group, strings.Join(BodyLimitGroups, ", "))
+		} else if n <= 0 || n > c.Srv.MaxBodyBytes {
+			bad("server.body_limits.%s harus di antara 1 dan server.max_body_bytes (%d)", group, c.Srv.MaxBodyBytes)
+		}
+	}
+	if c.Sync.SkewTolerance <= 0 || c.Sync.MaxAge <= 0 {
+		bad("sync.skew_tolerance dan sync.max_age harus > 0")
+	}
+	if c.Kiosk.QRTTL < 5*time.Second {
+		bad("kiosk.qr_ttl minimal 5s")
+	}
+	if c.Shift.Start < 0 || c.Shift.Start >= 24*time.Hour {
+		bad("shift.start harus di antara 0 dan 24h")
+	}
+	if c.Shift.LateGrace < 0 {
+		bad("shift.late_grace tidak boleh negatif")
+	}
+	if c.Features.AutoCheckout {
+		if c.Schedule.AutoCheckoutInterval < time.Minute {
+			bad("schedule.auto_checkout_interval minimal 1m")
+		}
+		if c.Schedule.AutoCheckoutAt <= 0 || c.Schedule.AutoCheckoutAt >= 24*time.Hour {
+			bad("schedule.auto_checkout_at harus di antara 0 dan 24h")
+		}
+	}
+
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		bad("log.level harus debug, info, warn, atau error (sekarang %q)", c.Log.Level)
+	}
+	switch c.Log.Format {
+	case "json", "text":
+	default:
+		bad("log.format harus json atau text (sekarang %q)", c.Log.Format)
+	}
+	if c.Log.SlowQuery < 0 {
+		bad("log.slow_query tidak boleh negatif")
+	}
+	if c.Metrics.Enabled && c.Metrics.Listen == "" && c.Metrics.Token == "" {
+		bad("metrics.token wajib diisi kalau /metrics dilayani di listener utama (atau isi metrics.listen)")
+	}
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		bad("tracing.exporter harus none, stdout, atau otlp (sekarang %q)", c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		bad("tracing.sample_ratio harus di antara 0 dan 1")
+	}
+	if c.RateLimit.Enabled {
+		switch c.RateLimit.Backend {
+		case "memory":
+		case "redis":
+			if c.RateLimit.RedisURL == "" {
+				bad("rate_limit.redis_url wajib diisi untuk backend redis")
+			}
+		default:
+			bad("rate_limit.backend harus memory atau redis (sekarang %q)", c.RateLimit.Backend)
+		}
+		for name, r := range map[string]Rule{"api": c.RateLimit.API, "login": c.RateLimit.Login, "check_in": c.RateLimit.CheckIn, "badge": c.RateLimit.Badge} {
+			if r.Burst < 1 || r.Refill <= 0 {
+				bad("rate_limit.%s: burst minimal 1 dan refill harus positif", name)
+			}
+		}
+	}
+	if c.Tenant.Default == "" || len(c.Tenant.Default) > 40 {
+		bad("tenant.default wajib diisi, maksimal 40 karakter")
+	}
+	if c.Idempotency.Enabled {
+		if c.Idempotency.TTL < time.Minute {
+			bad("idempotency.ttl minimal 1m")
+		}
+		if c.Idempotency.LockTimeout <= 0 || c.Idempotency.LockTimeout >= c.Idempotency.TTL {
+			bad("idempotency.lock_timeout harus positif dan lebih kecil dari ttl")
+		}
+		if c.Idempotency.CleanupInterval < time.Minute {
+			bad("idempotency.cleanup_interval minimal 1m")
+		}
+	}
+
+	if len(errs) > 0 {
+		return listError("config tidak valid", errs)
+	}
+	return nil
+}
+
+// listError menggabungkan semua kesalahan jadi satu pesan, satu baris per item.
+func listError(title string, errs []error) error {
+	var b strings.Builder
+	b.WriteString(title + ":")
+	for _, err := range errs {
+		b.WriteString("\n  - " + err.Error())
+	}
+	return errors.New(b.String())
+}
+
+// Location mengembalikan timezone perusahaan, dipakai kalau lokasi kerja tidak
+// punya timezone sendiri. Aman dipanggil setelah Validate; tzdata di-embed jadi
+// LoadLocation tidak bergantung pada /usr/share/zoneinfo di container.
+// Nama zona ikut dikirim ke SQL (AT TIME ZONE), jadi tidak boleh FixedZone.
+func (c *Config) Location() *time.Location {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Summary untuk log saat start; tidak memuat secret.
+func (c *Config) Summary() string {
+	return fmt.Sprintf("env=%s tz=%s db=%s@%s:%s/%s pool=%d/%d server=%s:%s features=%+v",
+		c.Env, c.Timezone, c.Db.User, c.Db.Host, c.Db.Port, c.Db.Name,
+		c.Db.MaxIdleConns, c.Db.MaxOpenConns, c.Srv.Host, c.Srv.Port, c.Features)
+}
+
+// envLoader menimpa nilai config dari environment variable dan mengumpulkan
+// error parsing, bukan diam-diam kembali ke default.
+type envLoader struct {
+	errs []error
+}
+
+func (e *envLoader) apply(c *Config) {
+	e.str("APP_ENV", &c.Env)
+	e.str("TIMEZONE", &c.Timezone)
+
+	e.str("DB_HOST", &c.Db.Host)
+	e.str("DB_PORT", &c.Db.Port)
+	e.str("DB_USER", &c.Db.User)
+	e.secret("DB_PASSWORD", &c.Db.Pass)
+	e.str("DB_NAME", &c.Db.Name)
+	e.int("DB_MAX_OPEN_CONNS", &c.Db.MaxOpenConns)
+	e.int("DB_MAX_IDLE_CONNS", &c.Db.MaxIdleConns)
+	e.duration("DB_CONN_MAX_LIFETIME", &c.Db.ConnMaxLifetime)
+	e.duration("DB_CONN_MAX_IDLE_TIME", &c.Db.ConnMaxIdleTime)
+	e.duration("DB_STATEMENT_TIMEOUT", &c.Db.StatementTimeout)
+
+	e.str("SERVER_HOST", &c.Srv.Host)
+	e.str("SERVER_PORT", &c.Srv.Port)
+	e.list("TRUSTED_PROXIES", &c.Srv.TrustedProxies)
+	e.duration("SERVER_READ_TIMEOUT", &c.Srv.ReadTimeout)
+	e.duration("SERVER_READ_HEADER_TIMEOUT", &c.Srv.ReadHeaderTimeout)
+	e.duration("SERVER_WRITE_TIMEOUT", &c.Srv.WriteTimeout)
+	e.duration("SERVER_IDLE_TIMEOUT", &c.Srv.IdleTimeout)
+	e.duration("SERVER_SHUTDOWN_TIMEOUT", &c.Srv.ShutdownTimeout)
+	e.duration("SERVER_DRAIN_DELAY", &c.Srv.DrainDelay)
+	e.int("SERVER_MAX_HEADER_BYTES", &c.Srv.MaxHeaderBytes)
+	e.int64("SERVER_MAX_BODY_BYTES", &c.Srv.MaxBodyBytes)
+	e.sizes("SERVER_BODY_LIMITS", &c.Srv.BodyLimits)
+
+	e.str("TENANT_DEFAULT", &c.Tenant.Default)
+
+	e.secret("AUTH_JWT_SECRET", &c.JWT.Secret)
+	e.str("AUTH_JWT_ISSUER", &c.JWT.Issuer)
+	e.duration("AUTH_ACCESS_TTL", &c.JWT.AccessTTL)
+	e.duration("AUTH_REFRESH_TTL", &c.JWT.RefreshTTL)
+
+	e.int("LOGIN_MAX_FAILED", &c.Login.MaxFailed)
+	e.duration("LOGIN_LOCKOUT", &c.Login.Lockout)
+
+	e.list("CORS_ALLOW_ORIGINS", &c.CORS.AllowOrigins)
+	e.list("CORS_ALLOW_METHODS", &c.CORS.AllowMethods)
+	e.list("CORS_ALLOW_HEADERS", &c.CORS.AllowHeaders)
+	e.bool("CORS_ALLOW_CREDENTIALS", &c.CORS.AllowCredentials)
+	e.duration("CORS_MAX_AGE", &c.CORS.MaxAge)
+
+	e.duration("SECURITY_HSTS_MAX_AGE", &c.Security.HSTSMaxAge)
+	e.bool("SECURITY_HSTS_INCLUDE_SUBDOMAINS", &c.Security.HSTSIncludeSubdomains)
+	e.str("SECURITY_FRAME_OPTIONS", &c.Security.FrameOptions)
+
+	e.duration("SYNC_SKEW_TOLERANCE", &c.Sync.SkewTolerance)
+	e.duration("SYNC_MAX_AGE", &c.Sync.MaxAge)
+	e.duration("KIOSK_QR_TTL", &c.Kiosk.QRTTL)
+	e.int("KIOSK_PIN_MAX_FAILED", &c.Kiosk.PINMaxFailed)
+	e.duration("KIOSK_PIN_LOCKOUT", &c.Kiosk.PINLockout)
+	e.duration("SHIFT_START", &c.Shift.Start)
+	e.duration("SHIFT_LATE_GRACE", &c.Shift.LateGrace)
+	e.duration("AUTO_CHECKOUT_INTERVAL", &c.Schedule.AutoCheckoutInterval)
+	e.duration("AUTO_CHECKOUT_AT", &c.Schedule.AutoCheckoutAt)
+
+	e.bool("FEATURE_KIOSK", &c.Features.Kiosk)
+	e.bool("FEATURE_OFFLINE_SYNC", &c.Features.OfflineSync)
+	e.bool("FEATURE_ANOMALY_DETECTION", &c.Features.AnomalyDetection)
+	e.bool("FEATURE_AUTO_CHECKOUT", &c.Features.AutoCheckout)
+	e.bool("FEATURE_API_DOCS", &c.Features.APIDocs)
+	e.bool("FEATURE_LEGACY_ROUTES", &c.Features.LegacyRoutes)
+
+	e.str("LOG_LEVEL", &c.Log.Level)
+	e.str("LOG_FORMAT", &c.Log.Format)
+	e.duration("LOG_SLOW_QUERY", &c.Log.SlowQuery)
+
+	e.bool("METRICS_ENABLED", &c.Metrics.Enabled)
+	e.str("METRICS_LISTEN", &c.Metrics.Listen)
+	e.secret("METRICS_TOKEN", &c.Metrics.Token)
+
+	e.str("TRACING_EXPORTER", &c.Tracing.Exporter)
+	e.str("TRACING_ENDPOINT", &c.Tracing.Endpoint)
+	e.bool("TRACING_INSECURE", &c.Tracing.Insecure)
+	e.float("TRACING_SAMPLE_RATIO", &c.Tracing.SampleRatio)
+
+	e.bool("RATE_LIMIT_ENABLED", &c.RateLimit.Enabled)
+	e.str("RATE_LIMIT_BACKEND", &c.RateLimit.Backend)
+	e.secret("RATE_LIMIT_REDIS_URL", &c.RateLimit.RedisURL)
+	e.int("RATE_LIMIT_API_BURST", &c.RateLimit.API.Burst)
+	e.duration("RATE_LIMIT_API_REFILL", &c.RateLimit.API.Refill)
+	e.int("RATE_LIMIT_LOGIN_BURST", &c.RateLimit.Login.Burst)
+	e.duration("RATE_LIMIT_LOGIN_REFILL", &c.RateLimit.Login.Refill)
+	e.int("RATE_LIMIT_CHECK_IN_BURST", &c.RateLimit.CheckIn.Burst)
+	e.duration("RATE_LIMIT_CHECK_IN_REFILL", &c.RateLimit.CheckIn.Refill)
+	e.int("RATE_LIMIT_BADGE_BURST", &c.RateLimit.Badge.Burst)
+	e.duration("RATE_LIMIT_BADGE_REFILL", &c.RateLimit.Badge.Refill)
+	e.bool("IDEMPOTENCY_ENABLED", &c.Idempotency.Enabled)
+	e.duration("IDEMPOTENCY_TTL", &c.Idempotency.TTL)
+	e.duration("IDEMPOTENCY_LOCK_TIMEOUT", &c.Idempotency.LockTimeout)
+	e.duration("IDEMPOTENCY_CLEANUP_INTERVAL", &c.Idempotency.CleanupInterval)
+}
+
+func (e *envLoader) str(key string, dst *string) {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		*dst = v
+	}
+}
+
+func (e *envLoader) secret(key string, dst *Secret) {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		*dst = Secret(v)
+	}
+}
+
+func (e *envLoader) int(key string, dst *int) {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s=%q bukan angka", key, v))
+		return
+	}
+	*dst = n
+}
+
+func (e *envLoader) int64(key string, dst *int64) {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s=%q bukan angka", key, v))
+		return
+	}
+	*dst = n
+}
+
+func (e *envLoader) bool(key string, dst *bool) {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s=%q bukan boolean", key, v))
+		return
+	}
+	*dst = b
+}
+
+func (e *envLoader) float(key string, dst *float64) {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s=%q bukan angka", key, v))
+		return
+	}
+	*dst = f
+}
+
+func (e *envLoader) duration(key string, dst *time.Duration) {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s=%q bukan durasi (contoh: 30s, 15m, 24h)", key, v))
+		return
+	}
+	*dst = d
+}
+
+// sizes: pasangan group=byte dipisah koma, mis. "auth=16384,offline_sync=1048576".
+func (e *envLoader) sizes(key string, dst *map[string]int64) {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return
+	}
+	out := map[string]int64{}
+	for _, kv := range strings.Split(v, ",") {
+		name, size, found := strings.Cut(strings.TrimSpace(kv), "=")
+		n, err := strconv.ParseInt(size, 10, 64)
+		if !found || name == "" || err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s: %q bukan group=byte", key, kv))
+			continue
+		}
+		out[name] = n
+	}
+	*dst = out
+}
+
+// list: nilai dipisah koma. Variabel yang di-set kosong mengosongkan list.
+func (e *envLoader) list(key string, dst *[]string) {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+	out := []string{}
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
 	}
+	*dst = out
 }
@@ -2,28 +2,54 @@ package config
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/plugin/opentelemetry/tracing"
+
+	"mojo-autotech/logging"
 )
 
-func NewDB() *gorm.DB {
-	config := NewConfig()
+// NewDB membuka satu pool koneksi untuk seluruh aplikasi. Panggil sekali di
+// main lalu teruskan *gorm.DB ke constructor repository.
+func NewDB(cfg *Config) (*gorm.DB, error) {
 	dsn := fmt.Sprintf(
 		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		config.Db.Host,
-		config.Db.User,
-		config.Db.Pass,
-		config.Db.Name,
-		config.Db.Port,
+		cfg.Db.Host,
+		cfg.Db.User,
+		cfg.Db.Pass.Value(),
+		cfg.Db.Name,
+		cfg.Db.Port,
 	)
+	if cfg.Db.StatementTimeout > 0 {
+		// diteruskan pgx sebagai runtime parameter untuk setiap koneksi
+		dsn += fmt.Sprintf(" statement_timeout=%d", cfg.Db.StatementTimeout.Milliseconds())
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logging.NewGormLogger(slog.Default(), cfg.Log.SlowQuery),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Tracing.Enabled() {
+		// nilai parameter query tidak ikut span: bisa berisi PIN hash/koordinat karyawan
+		if err := db.Use(tracing.NewPlugin(tracing.WithoutQueryVariables(), tracing.WithoutMetrics())); err != nil {
+			return nil, err
+		}
+	}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
+	sqlDB.SetMaxOpenConns(cfg.Db.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.Db.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.Db.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.Db.ConnMaxIdleTime)
 
-	fmt.Println("Connected to database")
-	return db
+	slog.Info("database terhubung", "host", cfg.Db.Host, "db", cfg.Db.Name)
+	return db, nil
 }
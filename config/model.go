@@ -1,19 +1,229 @@
 package config
 
+import "time"
+
 type Config struct {
-	Db  Database
-	Srv Server
+	Env      string   `yaml:"env"      json:"env"` // development / staging / production
+	Timezone string   `yaml:"timezone" json:"timezone"`
+	Tenant   Tenant   `yaml:"tenant"   json:"tenant"`
+	Db       Database `yaml:"db"       json:"db"`
+	Srv      Server   `yaml:"server"   json:"server"`
+	JWT      JWT      `yaml:"jwt"      json:"jwt"`
+	Login    Login    `yaml:"login"    json:"login"`
+	CORS     CORS     `yaml:"cors"     json:"cors"`
+	Security Security `yaml:"security" json:"security"`
+	Sync     Sync     `yaml:"sync"     json:"sync"`
+	Kiosk    Kiosk    `yaml:"kiosk"    json:"kiosk"`
+	Shift    Shift    `yaml:"shift"    json:"shift"`
+	Schedule Schedule `yaml:"schedule" json:"schedule"`
+	Features Features `yaml:"features" json:"features"`
+	Log      Log      `yaml:"log"      json:"log"`
+	Metrics  Metrics  `yaml:"metrics"  json:"metrics"`
+	Tracing  Tracing  `yaml:"tracing"  json:"tracing"`
+
+	RateLimit   RateLimit   `yaml:"rate_limit"  json:"rate_limit"`
+	Idempotency Idempotency `yaml:"idempotency" json:"idempotency"`
 }
 
 type Database struct {
-	Host string `json:"host"`
-	Port string `json:"port"`
-	User string `json:"user"`
-	Pass string `json:"pass"`
-	Name string `json:"name"`
+	Host string `yaml:"host" json:"host"`
+	Port string `yaml:"port" json:"port"`
+	User string `yaml:"user" json:"user"`
+	Pass Secret `yaml:"pass" json:"pass"`
+	Name string `yaml:"name" json:"name"`
+
+	MaxOpenConns     int           `yaml:"max_open_conns"     json:"max_open_conns"`
+	MaxIdleConns     int           `yaml:"max_idle_conns"     json:"max_idle_conns"`
+	ConnMaxLifetime  time.Duration `yaml:"conn_max_lifetime"  json:"conn_max_lifetime"`
+	ConnMaxIdleTime  time.Duration `yaml:"conn_max_idle_time" json:"conn_max_idle_time"`
+	StatementTimeout time.Duration `yaml:"statement_timeout"  json:"statement_timeout"` // 0 = tanpa batas
 }
 
 type Server struct {
-	Host string `json:"host"`
-	Port string `json:"port"`
+	Host string `yaml:"host" json:"host"`
+	Port string `yaml:"port" json:"port"`
+	// TrustedProxies: CIDR/IP reverse proxy yang boleh mengisi X-Forwarded-For.
+	// Kosong = tidak ada proxy dipercaya, ClientIP() memakai alamat koneksi.
+	TrustedProxies []string `yaml:"trusted_proxies" json:"trusted_proxies"`
+
+	ReadTimeout       time.Duration `yaml:"read_timeout"        json:"read_timeout"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" json:"read_header_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"       json:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"        json:"idle_timeout"`
+	// ShutdownTimeout: batas waktu menunggu request yang sedang jalan saat SIGTERM
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" json:"shutdown_timeout"`
+	// DrainDelay: jeda antara /readyz mulai gagal dan listener ditutup
+	DrainDelay     time.Duration `yaml:"drain_delay"      json:"drain_delay"`
+	MaxHeaderBytes int           `yaml:"max_header_bytes" json:"max_header_bytes"`
+	MaxBodyBytes   int64         `yaml:"max_body_bytes"   json:"max_body_bytes"`
+	// BodyLimits: batas body per group route (lihat BodyLimitGroups), hanya
+	// bisa lebih ketat dari MaxBodyBytes yang berlaku untuk semua request.
+	BodyLimits map[string]int64 `yaml:"body_limits" json:"body_limits"`
+}
+
+// BodyLimitGroups adalah nama group route yang boleh diberi batas body sendiri.
+var BodyLimitGroups = []string{"auth", "attendance", "payroll", "work_location", "anomaly", "kiosk", "offline_sync"}
+
+type JWT struct {
+	Secret     Secret        `yaml:"secret"      json:"secret"`
+	Issuer     string        `yaml:"issuer"      json:"issuer"`
+	AccessTTL  time.Duration `yaml:"access_ttl"  json:"access_ttl"`
+	RefreshTTL time.Duration `yaml:"refresh_ttl" json:"refresh_ttl"`
+}
+
+// Metrics mengatur endpoint Prometheus /metrics. Listen kosong = dilayani di
+// listener utama dan Token wajib; kalau diisi (mis. ":9091") endpoint ada di
+// listener terpisah yang tidak diekspos lewat ingress.
+type Metrics struct {
+	Enabled bool   `yaml:"enabled" json:"enabled"`
+	Listen  string `yaml:"listen"  json:"listen"`
+	Token   Secret `yaml:"token"   json:"token"` // bearer token untuk scraper; opsional di listener terpisah
+}
+
+// RateLimit mengatur token bucket per IP/user. Karyawan satu kantor sering
+// keluar lewat satu IP NAT, jadi kuota per IP dibuat longgar; pembatas ketat
+// untuk check-in dihitung per user.
+type RateLimit struct {
+	Enabled  bool   `yaml:"enabled"   json:"enabled"`
+	Backend  string `yaml:"backend"   json:"backend"`   // memory / redis
+	RedisURL Secret `yaml:"redis_url" json:"redis_url"` // redis://[:password@]host:port/db
+
+	API     Rule `yaml:"api"      json:"api"`      // per IP, semua endpoint API
+	Login   Rule `yaml:"login"    json:"login"`    // per IP, /login
+	CheckIn Rule `yaml:"check_in" json:"check_in"` // per user, check-in dan check-out
+	Badge   Rule `yaml:"badge"    json:"badge"`    // per badge, punch kiosk (tebakan PIN dari kiosk mana pun)
 }
+
+// Rule: paling banyak Burst request beruntun, lalu satu request setiap Refill.
+type Rule struct {
+	Burst  int           `yaml:"burst"  json:"burst"`
+	Refill time.Duration `yaml:"refill" json:"refill"`
+}
+
+// Tenant mengatur multi-tenant. Default adalah kode tenant untuk login yang
+// tidak mengirim field tenant (app lama dan deployment satu perusahaan) dan
+// untuk CLI tanpa --tenant.
+type Tenant struct {
+	Default string `yaml:"default" json:"default"`
+}
+
+// Idempotency mengatur header Idempotency-Key pada endpoint check-in,
+// check-out, login dan create. Response disimpan di database selama TTL.
+type Idempotency struct {
+	Enabled bool          `yaml:"enabled" json:"enabled"`
+	TTL     time.Duration `yaml:"ttl"     json:"ttl"` // berapa lama response bisa di-replay
+	// LockTimeout: request yang belum selesai setelah ini dianggap ditinggal
+	// (mis. proses mati), key boleh dipakai lagi
+	LockTimeout time.Duration `yaml:"lock_timeout"     json:"lock_timeout"`
+	// CleanupInterval: seberapa sering key kedaluwarsa dihapus
+	CleanupInterval time.Duration `yaml:"cleanup_interval" json:"cleanup_interval"`
+}
+
+// Tracing mengatur ekspor span OpenTelemetry. Exporter "none" tetap
+// meneruskan header traceparent, hanya tidak ada span yang dikirim.
+type Tracing struct {
+	Exporter string `yaml:"exporter" json:"exporter"` // none / stdout / otlp
+	// Endpoint: host:port collector OTLP/HTTP; kosong = OTEL_EXPORTER_OTLP_ENDPOINT atau localhost:4318
+	Endpoint    string  `yaml:"endpoint"     json:"endpoint"`
+	Insecure    bool    `yaml:"insecure"     json:"insecure"`     // kirim lewat http, bukan https
+	SampleRatio float64 `yaml:"sample_ratio" json:"sample_ratio"` // 0..1, dipakai untuk trace baru (root)
+}
+
+// Enabled: ada exporter yang aktif.
+func (t Tracing) Enabled() bool { return t.Exporter != "" && t.Exporter != "none" }
+
+// Log mengatur logger slog aplikasi.
+type Log struct {
+	Level  string `yaml:"level"  json:"level"`  // debug / info / warn / error
+	Format string `yaml:"format" json:"format"` // json / text
+	// SlowQuery: query GORM yang lebih lama dari ini dicatat sebagai warning; 0 = mati
+	SlowQuery time.Duration `yaml:"slow_query" json:"slow_query"`
+}
+
+// Login mengatur penguncian akun setelah password salah berulang kali.
+type Login struct {
+	MaxFailed int           `yaml:"max_failed" json:"max_failed"` // 0 = tidak pernah dikunci
+	Lockout   time.Duration `yaml:"lockout"    json:"lockout"`
+}
+
+// CORS hanya relevan untuk web admin; app mobile tidak terkena CORS. Origin
+// kosong = tidak ada origin lain yang diizinkan (middleware CORS tidak dipasang).
+// Kalau tidak diisi sama sekali, development memakai "*" dan environment lain
+// kosong.
+type CORS struct {
+	AllowOrigins     []string      `yaml:"allow_origins"     json:"allow_origins"` // "https://admin.mojo.id", "https://*.mojo.id" atau "*"
+	AllowMethods     []string      `yaml:"allow_methods"     json:"allow_methods"`
+	AllowHeaders     []string      `yaml:"allow_headers"     json:"allow_headers"`
+	AllowCredentials bool          `yaml:"allow_credentials" json:"allow_credentials"` // tidak boleh bersama origin "*"
+	MaxAge           time.Duration `yaml:"max_age"           json:"max_age"`           // cache preflight di browser
+}
+
+// Security mengatur header keamanan yang dikirim di setiap response.
+type Security struct {
+	// HSTSMaxAge: 0 = header Strict-Transport-Security tidak dikirim. Browser
+	// mengabaikannya lewat HTTP biasa, jadi aman walau TLS di reverse proxy.
+	HSTSMaxAge            time.Duration `yaml:"hsts_max_age"            json:"hsts_max_age"`
+	HSTSIncludeSubdomains bool          `yaml:"hsts_include_subdomains" json:"hsts_include_subdomains"`
+	FrameOptions          string        `yaml:"frame_options"           json:"frame_options"` // DENY / SAMEORIGIN
+}
+
+// Sync mengatur penerimaan punch offline dari aplikasi mobile.
+type Sync struct {
+	SkewTolerance time.Duration `yaml:"skew_tolerance" json:"skew_tolerance"` // selisih jam device vs estimasi server yang masih diterima
+	MaxAge        time.Duration `yaml:"max_age"        json:"max_age"`        // punch lebih tua dari ini selalu ditandai untuk review
+}
+
+type Kiosk struct {
+	// QRTTL: umur QR code kiosk, kiosk harus me-refresh sebelum habis
+	QRTTL time.Duration `yaml:"qr_ttl" json:"qr_ttl"`
+	// PINMaxFailed: PIN salah berturut-turut sebelum badge dikunci selama
+	// PINLockout, seperti Login. 0 = tidak pernah dikunci.
+	PINMaxFailed int           `yaml:"pin_max_failed" json:"pin_max_failed"`
+	PINLockout   time.Duration `yaml:"pin_lockout"    json:"pin_lockout"`
+}
+
+// Shift menentukan kapan check-in dianggap terlambat.
+type Shift struct {
+	Start     time.Duration `yaml:"start"      json:"start"`      // jam masuk dari awal tanggal kerja (mis. 8h = 08:00)
+	LateGrace time.Duration `yaml:"late_grace" json:"late_grace"` // toleransi sebelum status LATE
+}
+
+// Schedule mengatur job latar belakang.
+type Schedule struct {
+	// AutoCheckoutInterval: seberapa sering absensi tanpa check-out dari hari
+	// sebelumnya ditutup otomatis.
+	AutoCheckoutInterval time.Duration `yaml:"auto_checkout_interval" json:"auto_checkout_interval"`
+	// AutoCheckoutAt: jam check-out yang dicatat, dihitung dari awal tanggal kerja (mis. 17h = 17:00).
+	AutoCheckoutAt time.Duration `yaml:"auto_checkout_at" json:"auto_checkout_at"`
+}
+
+// Features menyalakan/mematikan modul opsional tanpa build ulang.
+type Features struct {
+	Kiosk            bool `yaml:"kiosk"             json:"kiosk"`
+	OfflineSync      bool `yaml:"offline_sync"      json:"offline_sync"`
+	AnomalyDetection bool `yaml:"anomaly_detection" json:"anomaly_detection"`
+	AutoCheckout     bool `yaml:"auto_checkout"     json:"auto_checkout"`
+	APIDocs          bool `yaml:"api_docs"          json:"api_docs"`      // /docs dan /docs/openapi.*
+	LegacyRoutes     bool `yaml:"legacy_routes"     json:"legacy_routes"` // alias route lama tanpa /api/v1
+}
+
+// Secret menyimpan nilai rahasia (password, key). Semua cara mencetak/serialisasi
+// menghasilkan "******"; pakai Value() untuk nilai aslinya.
+type Secret string
+
+const redacted = "******"
+
+func (s Secret) Value() string { return string(s) }
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+func (s Secret) GoString() string { return `config.Secret("` + s.String() + `")` }
+
+func (s Secret) MarshalJSON() ([]byte, error) { return []byte(`"` + s.String() + `"`), nil }
+
+func (s Secret) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
@@ -0,0 +1,287 @@
+// Package fake berisi implementasi in-memory dari interface repository untuk
+// unit test service dan handler. Perilakunya meniru SQL aslinya (lihat
+// komentar per method); kebenaran SQL itu sendiri diuji oleh integration test
+// dengan build tag "integration".
+package fake
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mojo-autotech/clock"
+	entity "mojo-autotech/model/attedance"
+	"mojo-autotech/tenant"
+)
+
+const dateLayout = "2006-01-02"
+
+type attendanceKey struct {
+	tenantID uint
+	userID   uint
+	date     string
+}
+
+// AttendanceRepository meniru entity.IAttendanceRepository. Err, kalau diisi,
+// dikembalikan oleh semua method untuk mensimulasikan database error.
+type AttendanceRepository struct {
+	Err error
+
+	mu     sync.Mutex
+	clock  clock.Clock
+	nextID uint
+	rows   map[attendanceKey]*entity.Attendance
+	locked map[string]bool
+	zones  map[uint]string // timezone per lokasi kerja, pengganti join work_locations
+}
+
+var _ entity.IAttendanceRepository = (*AttendanceRepository)(nil)
+
+// NewAttendanceRepository memakai clk sebagai pengganti NOW() di SQL.
+func NewAttendanceRepository(clk clock.Clock) *AttendanceRepository {
+	return &AttendanceRepository{
+		clock:  clk,
+		rows:   map[attendanceKey]*entity.Attendance{},
+		locked: map[string]bool{},
+		zones:  map[uint]string{},
+	}
+}
+
+// LockDate menandai tanggal sebagai bagian periode payroll LOCKED, untuk
+// semua tenant.
+func (r *AttendanceRepository) LockDate(date time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.locked[date.Format(dateLayout)] = true
+}
+
+// SetTimezone memberi lokasi kerja timezone sendiri untuk AutoCheckOut.
+func (r *AttendanceRepository) SetTimezone(workLocationID uint, tz string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.zones[workLocationID] = tz
+}
+
+// All mengembalikan salinan semua baris, untuk assertion di test.
+func (r *AttendanceRepository) All() []entity.Attendance {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]entity.Attendance, 0, len(r.rows))
+	for _, a := range r.rows {
+		out = append(out, *a)
+	}
+	return out
+}
+
+func (r *AttendanceRepository) punchTime(at *time.Time) time.Time {
+	if at != nil {
+		return *at
+	}
+	return r.clock.Now()
+}
+
+// UpsertCheckIn: insert kalau (user, tanggal) belum ada; kalau sudah, field
+// yang diisi menimpa, check_in_at hanya mundur (LEAST), dan status dihitung
+// ulang hanya kalau punch ini lebih awal dari check-in yang tersimpan.
+func (r *AttendanceRepository) UpsertCheckIn(ctx context.Context, a entity.Attendance) (entity.Attendance, bool, error) {
+	if r.Err != nil {
+		return entity.Attendance{}, false, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return entity.Attendance{}, false, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	at := r.punchTime(a.CheckInAt)
+	key := attendanceKey{tid, a.UserID, a.Date.Format(dateLayout)}
+
+	cur, ok := r.rows[key]
+	if !ok {
+		r.nextID++
+		a.ID = r.nextID
+		a.TenantID = tid
+		a.CheckInAt = &at
+		a.CreatedAt, a.UpdatedAt = now, now
+		r.rows[key] = &a
+		return a, true, nil
+	}
+
+	if a.CheckInAt != nil && cur.CheckInAt != nil && a.CheckInAt.Before(*cur.CheckInAt) {
+		cur.Status = a.Status
+	}
+	if cur.CheckInAt == nil || at.Before(*cur.CheckInAt) {
+		cur.CheckInAt = &at
+	}
+	cur.CheckInLat = coalesce(a.CheckInLat, cur.CheckInLat)
+	cur.CheckInLng = coalesce(a.CheckInLng, cur.CheckInLng)
+	cur.CheckInPhotoURL = coalesce(a.CheckInPhotoURL, cur.CheckInPhotoURL)
+	cur.CheckInIP = coalesce(a.CheckInIP, cur.CheckInIP)
+	cur.CheckInKioskID = coalesce(a.CheckInKioskID, cur.CheckInKioskID)
+	cur.WorkLocationID = coalesce(a.WorkLocationID, cur.WorkLocationID)
+	cur.Activity = a.Activity
+	cur.UpdatedAt = now
+	return *cur, false, nil
+}
+
+func (r *AttendanceRepository) GetByUserAndDate(ctx context.Context, userID uint, date time.Time) (entity.Attendance, error) {
+	if r.Err != nil {
+		return entity.Attendance{}, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return entity.Attendance{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur, ok := r.rows[attendanceKey{tid, userID, date.Format(dateLayout)}]
+	if !ok {
+		return entity.Attendance{}, gorm.ErrRecordNotFound
+	}
+	return *cur, nil
+}
+
+func (r *AttendanceRepository) ListByUserBetween(ctx context.Context, userID uint, from, to time.Time) ([]entity.Attendance, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lo, hi := from.Format(dateLayout), to.Format(dateLayout)
+	out := []entity.Attendance{}
+	for k, a := range r.rows {
+		if k.tenantID == tid && k.userID == userID && k.date >= lo && k.date <= hi {
+			out = append(out, *a)
+		}
+	}
+	slices.SortFunc(out, func(a, b entity.Attendance) int {
+		return strings.Compare(b.Date.Format(dateLayout), a.Date.Format(dateLayout))
+	})
+	return out, nil
+}
+
+// CheckOut: gorm.ErrRecordNotFound kalau belum check-in atau sudah check-out.
+func (r *AttendanceRepository) CheckOut(ctx context.Context, userID uint, date time.Time, at *time.Time, ip *string, kioskID *uint) (entity.Attendance, error) {
+	if r.Err != nil {
+		return entity.Attendance{}, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return entity.Attendance{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur, ok := r.rows[attendanceKey{tid, userID, date.Format(dateLayout)}]
+	if !ok || cur.CheckOutAt != nil {
+		return entity.Attendance{}, gorm.ErrRecordNotFound
+	}
+	out := r.punchTime(at)
+	cur.CheckOutAt = &out
+	cur.CheckOutIP = ip
+	cur.CheckOutKioskID = kioskID
+	if cur.CheckInAt != nil {
+		cur.TotalMinutes = max(0, cur.TotalMinutes+int(out.Sub(*cur.CheckInAt).Minutes()))
+	}
+	cur.UpdatedAt = r.clock.Now()
+	return *cur, nil
+}
+
+func (r *AttendanceRepository) IsDateLocked(ctx context.Context, date time.Time) (bool, error) {
+	if r.Err != nil {
+		return false, r.Err
+	}
+	if _, err := tenant.ID(ctx); err != nil {
+		return false, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.locked[date.Format(dateLayout)], nil
+}
+
+func (r *AttendanceRepository) RecomputeTotals(ctx context.Context, from, to time.Time) (int64, error) {
+	if r.Err != nil {
+		return 0, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fromStr, toStr := from.Format(dateLayout), to.Format(dateLayout)
+	var n int64
+	for k, a := range r.rows {
+		if k.tenantID != tid || k.date < fromStr || k.date > toStr || r.locked[k.date] || a.CheckInAt == nil || a.CheckOutAt == nil {
+			continue
+		}
+		a.TotalMinutes = max(0, int(a.CheckOutAt.Sub(*a.CheckInAt).Minutes()))
+		a.UpdatedAt = r.clock.Now()
+		n++
+	}
+	return n, nil
+}
+
+// AutoCheckOut: tanggal kerja yang sudah lewat pada now di timezone lokasinya
+// (atau defaultTZ) ditutup dengan check-out = tanggal kerja + at, tidak lebih
+// awal dari check-in. Seperti AT TIME ZONE di SQL, nama timezone yang tidak
+// dikenal adalah error.
+func (r *AttendanceRepository) AutoCheckOut(ctx context.Context, now time.Time, at time.Duration, defaultTZ string) (int64, error) {
+	if r.Err != nil {
+		return 0, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for k, a := range r.rows {
+		if k.tenantID != tid {
+			continue
+		}
+		tz := defaultTZ
+		if a.WorkLocationID != nil && r.zones[*a.WorkLocationID] != "" {
+			tz = r.zones[*a.WorkLocationID]
+		}
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return n, err
+		}
+		if k.date >= now.In(loc).Format(dateLayout) || r.locked[k.date] || a.CheckInAt == nil || a.CheckOutAt != nil {
+			continue
+		}
+		d, _ := time.ParseInLocation(dateLayout, k.date, loc)
+		out := d.Add(at)
+		if out.Before(*a.CheckInAt) {
+			out = *a.CheckInAt
+		}
+		a.CheckOutAt = &out
+		a.TotalMinutes = max(0, a.TotalMinutes+int(out.Sub(*a.CheckInAt).Minutes()))
+		a.UpdatedAt = r.clock.Now()
+		n++
+	}
+	return n, nil
+}
+
+func coalesce[T any](v, fallback *T) *T {
+	if v != nil {
+		return v
+	}
+	return fallback
+}
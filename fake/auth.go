@@ -0,0 +1,255 @@
+package fake
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"mojo-autotech/clock"
+	entity "mojo-autotech/model/user_authentication"
+	"mojo-autotech/tenant"
+)
+
+// AuthRepository meniru entity.IAuthRepository. Username dan email
+// dibandingkan tanpa membedakan huruf besar/kecil seperti query aslinya, dan
+// hanya di dalam tenant dari context.
+type AuthRepository struct {
+	Err error
+
+	mu     sync.Mutex
+	clock  clock.Clock
+	nextID uint
+	users  map[userKey]*entity.User
+}
+
+type userKey struct {
+	tenantID uint
+	username string // lowercase
+}
+
+func keyOf(tenantID uint, username string) userKey {
+	return userKey{tenantID, strings.ToLower(username)}
+}
+
+var _ entity.IAuthRepository = (*AuthRepository)(nil)
+
+func NewAuthRepository(clk clock.Clock) *AuthRepository {
+	return &AuthRepository{clock: clk, users: map[userKey]*entity.User{}}
+}
+
+// AddUser menyimpan user dengan password plaintext yang di-hash di sini;
+// ID diisi otomatis kalau kosong, TenantID kosong berarti tenant 1.
+func (r *AuthRepository) AddUser(u entity.User, password string) entity.User {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		panic(err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if u.ID == 0 {
+		r.nextID++
+		u.ID = r.nextID
+	}
+	if u.TenantID == 0 {
+		u.TenantID = 1
+	}
+	u.PasswordHash = string(hash)
+	r.users[keyOf(u.TenantID, u.Username)] = &u
+	return u
+}
+
+// User mengembalikan salinan user tenant 1 untuk assertion di test.
+func (r *AuthRepository) User(username string) (entity.User, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[keyOf(1, username)]
+	if !ok {
+		return entity.User{}, false
+	}
+	return *u, true
+}
+
+func (r *AuthRepository) byID(tenantID, id uint) *entity.User {
+	for _, u := range r.users {
+		if u.TenantID == tenantID && u.ID == id {
+			return u
+		}
+	}
+	return nil
+}
+
+// Login: user tetap dikembalikan bersama ErrPasswordMismatch.
+func (r *AuthRepository) Login(ctx context.Context, req entity.LoginReq) (entity.User, error) {
+	if r.Err != nil {
+		return entity.User{}, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return entity.User{}, err
+	}
+	r.mu.Lock()
+	u, ok := r.users[keyOf(tid, req.Username)]
+	r.mu.Unlock()
+	if !ok || u.DeletedAt.Valid {
+		return entity.User{}, gorm.ErrRecordNotFound
+	}
+	if bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(req.Password)) != nil {
+		return *u, entity.ErrPasswordMismatch
+	}
+	return *u, nil
+}
+
+// CreateUser: req.Password sudah berupa hash dari service.
+func (r *AuthRepository) CreateUser(ctx context.Context, req entity.RegisterReq) (entity.User, error) {
+	if r.Err != nil {
+		return entity.User{}, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return entity.User{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	role := req.Role
+	if role == "" {
+		role = "EMPLOYEE"
+	}
+	now := r.clock.Now()
+	r.nextID++
+	u := &entity.User{
+		ID:           r.nextID,
+		TenantID:     tid,
+		UserId:       req.UserId,
+		Username:     req.Username,
+		Email:        req.Email,
+		FullName:     req.FullName,
+		Phone:        req.Phone,
+		PasswordHash: req.Password,
+		Role:         role,
+		IsActive:     true,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	r.users[keyOf(tid, req.Username)] = u
+	return *u, nil
+}
+
+func (r *AuthRepository) CountDuplicate(ctx context.Context, username, email string) (int64, error) {
+	if r.Err != nil {
+		return 0, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for _, u := range r.users {
+		if u.TenantID != tid {
+			continue
+		}
+		if strings.EqualFold(u.Username, username) || strings.EqualFold(u.Email, email) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// UpdatePassword juga membuka kunci akun, seperti query aslinya.
+func (r *AuthRepository) UpdatePassword(ctx context.Context, username, passwordHash string) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[keyOf(tid, username)]
+	if !ok || u.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+	u.PasswordHash = passwordHash
+	u.FailedLogin = 0
+	u.LockedUntil = nil
+	u.UpdatedAt = r.clock.Now()
+	return nil
+}
+
+func (r *AuthRepository) SetActive(ctx context.Context, username string, active bool) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[keyOf(tid, username)]
+	if !ok || u.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+	u.IsActive = active
+	u.UpdatedAt = r.clock.Now()
+	return nil
+}
+
+// RecordFailedLogin: begitu hitungan mencapai maxFailed, akun dikunci sampai
+// sekarang+lockout dan hitungan kembali ke 0. maxFailed 0 = tidak pernah dikunci.
+func (r *AuthRepository) RecordFailedLogin(ctx context.Context, userID uint, maxFailed int, lockout time.Duration) (*time.Time, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u := r.byID(tid, userID)
+	if u == nil {
+		return nil, nil
+	}
+	now := r.clock.Now()
+	if maxFailed > 0 && int(u.FailedLogin)+1 >= maxFailed {
+		until := now.Add(lockout)
+		u.LockedUntil = &until
+		u.FailedLogin = 0
+	} else {
+		u.FailedLogin++
+	}
+	u.UpdatedAt = now
+	return u.LockedUntil, nil
+}
+
+func (r *AuthRepository) RecordLogin(ctx context.Context, userID uint) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if u := r.byID(tid, userID); u != nil {
+		now := r.clock.Now()
+		u.FailedLogin = 0
+		u.LockedUntil = nil
+		u.LastLoginAt = &now
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package fake
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"mojo-autotech/clock"
+	entity "mojo-autotech/model/idempotency"
+	"mojo-autotech/tenant"
+)
+
+// IdempotencyRepository meniru entity.IIdempotencyRepository: key yang sudah
+// lewat expires_at boleh diambil alih seperti ON CONFLICT di query aslinya,
+// dan key yang sama di tenant lain tidak bentrok.
+type IdempotencyRepository struct {
+	Err error
+
+	mu    sync.Mutex
+	clock clock.Clock
+	rows  map[idemKey]entity.Record
+}
+
+type idemKey struct {
+	tenantID   uint
+	scope, key string
+}
+
+var _ entity.IIdempotencyRepository = (*IdempotencyRepository)(nil)
+
+func NewIdempotencyRepository(clk clock.Clock) *IdempotencyRepository {
+	return &IdempotencyRepository{clock: clk, rows: map[idemKey]entity.Record{}}
+}
+
+func (r *IdempotencyRepository) Reserve(ctx context.Context, scope, key, hash string, lock time.Duration) (entity.Record, bool, error) {
+	if r.Err != nil {
+		return entity.Record{}, false, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return entity.Record{}, false, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	k := idemKey{tid, scope, key}
+	if rec, ok := r.rows[k]; ok && rec.ExpiresAt.After(now) {
+		return rec, false, nil
+	}
+	r.rows[k] = entity.Record{
+		TenantID: tid, Scope: scope, Key: key, RequestHash: hash, CreatedAt: now, ExpiresAt: now.Add(lock),
+	}
+	return entity.Record{}, true, nil
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, scope, key string, res entity.Response, ttl time.Duration) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := idemKey{tid, scope, key}
+	rec, ok := r.rows[k]
+	if !ok {
+		return nil
+	}
+	rec.StatusCode, rec.ContentType, rec.Body = res.StatusCode, res.ContentType, res.Body
+	rec.ExpiresAt = r.clock.Now().Add(ttl)
+	r.rows[k] = rec
+	return nil
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, scope, key string) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.rows, idemKey{tid, scope, key})
+	return nil
+}
+
+func (r *IdempotencyRepository) DeleteExpired(_ context.Context) (int64, error) {
+	if r.Err != nil {
+		return 0, r.Err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	now := r.clock.Now()
+	for k, rec := range r.rows {
+		if !rec.ExpiresAt.After(now) {
+			delete(r.rows, k)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Len mengembalikan jumlah key yang tersimpan (termasuk yang kedaluwarsa).
+func (r *IdempotencyRepository) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.rows)
+}
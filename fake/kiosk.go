@@ -0,0 +1,237 @@
+package fake
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"mojo-autotech/clock"
+	entity "mojo-autotech/model/kiosk"
+	"mojo-autotech/tenant"
+)
+
+// KioskRepository meniru entity.IKioskRepository. Badge disimpan per user,
+// terpisah dari AuthRepository; baris tenant lain diperlakukan seperti tidak ada.
+type KioskRepository struct {
+	Err error
+
+	mu     sync.Mutex
+	clock  clock.Clock
+	nextID uint
+	kiosks map[uint]entity.Kiosk
+	badges map[uint]*badgeRow // per user id
+}
+
+type badgeRow struct {
+	tenantID uint
+	badgeID  string
+	failed   int
+	badge    entity.Badge
+}
+
+var _ entity.IKioskRepository = (*KioskRepository)(nil)
+
+func NewKioskRepository(clk clock.Clock) *KioskRepository {
+	return &KioskRepository{
+		clock:  clk,
+		kiosks: map[uint]entity.Kiosk{},
+		badges: map[uint]*badgeRow{},
+	}
+}
+
+// AddBadge menyimpan badge karyawan tenant 1 dengan PIN plaintext yang
+// di-hash di sini.
+func (r *KioskRepository) AddBadge(b entity.Badge, badgeID, pin string) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.MinCost)
+	if err != nil {
+		panic(err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b.PinHash = string(hash)
+	r.badges[b.UserID] = &badgeRow{tenantID: 1, badgeID: badgeID, badge: b}
+}
+
+func (r *KioskRepository) Create(ctx context.Context, k entity.Kiosk) (entity.Kiosk, error) {
+	if r.Err != nil {
+		return entity.Kiosk{}, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return entity.Kiosk{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	k.ID = r.nextID
+	k.TenantID = tid
+	r.kiosks[k.ID] = k
+	return k, nil
+}
+
+func (r *KioskRepository) List(ctx context.Context) ([]entity.Kiosk, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var out []entity.Kiosk
+	for _, k := range r.kiosks {
+		if k.TenantID == tid {
+			out = append(out, k)
+		}
+	}
+	slices.SortFunc(out, func(a, b entity.Kiosk) int { return int(a.ID) - int(b.ID) })
+	return out, nil
+}
+
+func (r *KioskRepository) GetByID(ctx context.Context, id uint) (entity.Kiosk, error) {
+	if r.Err != nil {
+		return entity.Kiosk{}, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return entity.Kiosk{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k, ok := r.kiosks[id]
+	if !ok || k.TenantID != tid {
+		return entity.Kiosk{}, gorm.ErrRecordNotFound
+	}
+	return k, nil
+}
+
+// GetByKeyHash mencari di semua tenant, seperti query aslinya.
+func (r *KioskRepository) GetByKeyHash(ctx context.Context, keyHash string) (entity.Kiosk, error) {
+	if r.Err != nil {
+		return entity.Kiosk{}, r.Err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, k := range r.kiosks {
+		if k.KeyHash == keyHash {
+			return k, nil
+		}
+	}
+	return entity.Kiosk{}, gorm.ErrRecordNotFound
+}
+
+func (r *KioskRepository) Touch(ctx context.Context, id uint) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if k, ok := r.kiosks[id]; ok && k.TenantID == tid {
+		now := r.clock.Now()
+		k.LastSeenAt = &now
+		r.kiosks[id] = k
+	}
+	return nil
+}
+
+func (r *KioskRepository) GetBadge(ctx context.Context, badgeID string) (entity.Badge, error) {
+	if r.Err != nil {
+		return entity.Badge{}, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return entity.Badge{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, row := range r.badges {
+		if row.tenantID == tid && row.badgeID == badgeID {
+			return row.badge, nil
+		}
+	}
+	return entity.Badge{}, gorm.ErrRecordNotFound
+}
+
+// SetBadge hanya mengubah badge yang sudah dibuat lewat AddBadge; user lain
+// dianggap tidak ada.
+func (r *KioskRepository) SetBadge(ctx context.Context, userID uint, badgeID, pinHash string) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, ok := r.badges[userID]
+	if !ok || row.tenantID != tid {
+		return gorm.ErrRecordNotFound
+	}
+	row.badgeID = badgeID
+	row.badge.PinHash = pinHash
+	row.failed = 0
+	row.badge.PinLockedUntil = nil
+	return nil
+}
+
+// RecordFailedPIN: begitu hitungan mencapai maxFailed, badge dikunci sampai
+// sekarang+lockout dan hitungan kembali ke 0. maxFailed 0 = tidak pernah dikunci.
+func (r *KioskRepository) RecordFailedPIN(ctx context.Context, userID uint, maxFailed int, lockout time.Duration) (*time.Time, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, ok := r.badges[userID]
+	if !ok || row.tenantID != tid {
+		return nil, nil
+	}
+	if maxFailed > 0 && row.failed+1 >= maxFailed {
+		until := r.clock.Now().Add(lockout)
+		row.badge.PinLockedUntil = &until
+		row.failed = 0
+	} else {
+		row.failed++
+	}
+	return row.badge.PinLockedUntil, nil
+}
+
+func (r *KioskRepository) ResetFailedPIN(ctx context.Context, userID uint) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if row, ok := r.badges[userID]; ok && row.tenantID == tid {
+		row.failed = 0
+		row.badge.PinLockedUntil = nil
+	}
+	return nil
+}
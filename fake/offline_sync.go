@@ -0,0 +1,206 @@
+package fake
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"mojo-autotech/clock"
+	entity "mojo-autotech/model/offline_sync"
+	org "mojo-autotech/model/org_structure"
+	"mojo-autotech/tenant"
+)
+
+type deviceKey struct {
+	userID   uint
+	deviceID string
+}
+
+type syncPunchKey struct {
+	userID  uint
+	punchID string
+}
+
+// OfflineSyncRepository meniru entity.IOfflineSyncRepository. Punch unik per
+// (user_id, punch_id) seperti ON CONFLICT di query aslinya; baris tenant lain
+// diperlakukan seperti tidak ada.
+type OfflineSyncRepository struct {
+	Err error
+
+	mu      sync.Mutex
+	clock   clock.Clock
+	nextID  uint
+	devices map[deviceKey]entity.Device
+	punches map[syncPunchKey]*entity.Punch
+}
+
+var _ entity.IOfflineSyncRepository = (*OfflineSyncRepository)(nil)
+
+func NewOfflineSyncRepository(clk clock.Clock) *OfflineSyncRepository {
+	return &OfflineSyncRepository{
+		clock:   clk,
+		devices: map[deviceKey]entity.Device{},
+		punches: map[syncPunchKey]*entity.Punch{},
+	}
+}
+
+// Len mengembalikan jumlah punch yang tersimpan di semua tenant.
+func (r *OfflineSyncRepository) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.punches)
+}
+
+func (r *OfflineSyncRepository) UpsertDevice(ctx context.Context, userID uint, deviceID, secret string) (entity.Device, error) {
+	if r.Err != nil {
+		return entity.Device{}, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return entity.Device{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	k := deviceKey{userID, deviceID}
+	d, ok := r.devices[k]
+	if ok && d.TenantID != tid {
+		return entity.Device{}, nil // WHERE tenant_id cocok gagal: RETURNING kosong
+	}
+	if !ok {
+		r.nextID++
+		d = entity.Device{ID: r.nextID, TenantID: tid, UserID: userID, DeviceID: deviceID, CreatedAt: now}
+	}
+	d.Secret, d.UpdatedAt = secret, now
+	r.devices[k] = d
+	return d, nil
+}
+
+func (r *OfflineSyncRepository) GetDevice(ctx context.Context, userID uint, deviceID string) (entity.Device, error) {
+	if r.Err != nil {
+		return entity.Device{}, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return entity.Device{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, ok := r.devices[deviceKey{userID, deviceID}]
+	if !ok || d.TenantID != tid {
+		return entity.Device{}, gorm.ErrRecordNotFound
+	}
+	return d, nil
+}
+
+func (r *OfflineSyncRepository) InsertPunch(ctx context.Context, p entity.Punch) (entity.Punch, bool, error) {
+	if r.Err != nil {
+		return entity.Punch{}, false, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return entity.Punch{}, false, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := syncPunchKey{p.UserID, p.PunchID}
+	if _, ok := r.punches[k]; ok {
+		return entity.Punch{}, false, nil
+	}
+	r.nextID++
+	now := r.clock.Now()
+	p.ID, p.TenantID, p.CreatedAt, p.UpdatedAt = r.nextID, tid, now, now
+	r.punches[k] = &p
+	return p, true, nil
+}
+
+func (r *OfflineSyncRepository) GetPunch(ctx context.Context, userID uint, punchID string) (entity.Punch, error) {
+	if r.Err != nil {
+		return entity.Punch{}, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return entity.Punch{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.punches[syncPunchKey{userID, punchID}]
+	if !ok || p.TenantID != tid {
+		return entity.Punch{}, gorm.ErrRecordNotFound
+	}
+	return *p, nil
+}
+
+func (r *OfflineSyncRepository) GetPunchByID(ctx context.Context, id uint) (entity.Punch, error) {
+	if r.Err != nil {
+		return entity.Punch{}, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return entity.Punch{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range r.punches {
+		if p.ID == id && p.TenantID == tid {
+			return *p, nil
+		}
+	}
+	return entity.Punch{}, gorm.ErrRecordNotFound
+}
+
+func (r *OfflineSyncRepository) ListByStatus(ctx context.Context, status string, scope org.Scope) ([]entity.Punch, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := []entity.Punch{}
+	for _, p := range r.punches {
+		if p.TenantID == tid && p.Status == status && scope.Allows(p.UserID) {
+			out = append(out, *p)
+		}
+	}
+	slices.SortFunc(out, func(a, b entity.Punch) int { return a.DeviceTime.Compare(b.DeviceTime) })
+	return out, nil
+}
+
+func (r *OfflineSyncRepository) SetResult(ctx context.Context, id uint, status, reason string, attendanceID *uint, reviewedBy *uint) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range r.punches {
+		if p.ID != id || p.TenantID != tid {
+			continue
+		}
+		p.Status, p.Reason = status, reason
+		if attendanceID != nil {
+			p.AttendanceID = attendanceID
+		}
+		if reviewedBy != nil {
+			now := r.clock.Now()
+			p.ReviewedBy, p.ReviewedAt = reviewedBy, &now
+		}
+		p.UpdatedAt = r.clock.Now()
+	}
+	return nil
+}
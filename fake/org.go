@@ -0,0 +1,299 @@
+package fake
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"gorm.io/gorm"
+
+	entity "mojo-autotech/model/org_structure"
+	"mojo-autotech/tenant"
+)
+
+// OrgRepository meniru entity.IOrgRepository. User diisi lewat AddMember
+// karena tabel users dikelola repository auth.
+type OrgRepository struct {
+	Err error
+
+	mu          sync.Mutex
+	nextID      uint
+	tenantOf    map[uint]uint // tenant setiap member
+	members     map[uint]*entity.Member
+	departments map[uint]entity.Department
+	teams       map[uint]entity.Team
+	lines       []entity.ReportingLine
+}
+
+var _ entity.IOrgRepository = (*OrgRepository)(nil)
+
+func NewOrgRepository() *OrgRepository {
+	return &OrgRepository{
+		tenantOf:    map[uint]uint{},
+		members:     map[uint]*entity.Member{},
+		departments: map[uint]entity.Department{},
+		teams:       map[uint]entity.Team{},
+	}
+}
+
+// AddMember menyimpan user aktif di tenant tenantID tanpa mencatat riwayat.
+func (r *OrgRepository) AddMember(tenantID uint, m entity.Member) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m.IsActive = true
+	r.tenantOf[m.ID] = tenantID
+	r.members[m.ID] = &m
+}
+
+// ctxTenant: Err dulu, lalu tenant dari context seperti repository aslinya.
+func (r *OrgRepository) ctxTenant(ctx context.Context) (uint, error) {
+	if r.Err != nil {
+		return 0, r.Err
+	}
+	return tenant.ID(ctx)
+}
+
+func (r *OrgRepository) id() uint {
+	r.nextID++
+	return r.nextID
+}
+
+func (r *OrgRepository) CreateDepartment(ctx context.Context, d entity.Department) (entity.Department, error) {
+	tid, err := r.ctxTenant(ctx)
+	if err != nil {
+		return entity.Department{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d.ID, d.TenantID = r.id(), tid
+	r.departments[d.ID] = d
+	return d, nil
+}
+
+func (r *OrgRepository) UpdateDepartment(ctx context.Context, d entity.Department) (entity.Department, error) {
+	tid, err := r.ctxTenant(ctx)
+	if err != nil {
+		return entity.Department{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cur, ok := r.departments[d.ID]; !ok || cur.TenantID != tid {
+		return entity.Department{}, gorm.ErrRecordNotFound
+	}
+	d.TenantID = tid
+	r.departments[d.ID] = d
+	return d, nil
+}
+
+func (r *OrgRepository) GetDepartment(ctx context.Context, id uint) (entity.Department, error) {
+	tid, err := r.ctxTenant(ctx)
+	if err != nil {
+		return entity.Department{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.departments[id]
+	if !ok || d.TenantID != tid {
+		return entity.Department{}, gorm.ErrRecordNotFound
+	}
+	return d, nil
+}
+
+func (r *OrgRepository) ListDepartments(ctx context.Context) ([]entity.Department, error) {
+	tid, err := r.ctxTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []entity.Department{}
+	for _, d := range r.departments {
+		if d.TenantID == tid {
+			out = append(out, d)
+		}
+	}
+	slices.SortFunc(out, func(a, b entity.Department) int { return cmp.Compare(a.Name, b.Name) })
+	return out, nil
+}
+
+func (r *OrgRepository) CreateTeam(ctx context.Context, t entity.Team) (entity.Team, error) {
+	tid, err := r.ctxTenant(ctx)
+	if err != nil {
+		return entity.Team{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t.ID, t.TenantID = r.id(), tid
+	r.teams[t.ID] = t
+	return t, nil
+}
+
+func (r *OrgRepository) UpdateTeam(ctx context.Context, t entity.Team) (entity.Team, error) {
+	tid, err := r.ctxTenant(ctx)
+	if err != nil {
+		return entity.Team{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cur, ok := r.teams[t.ID]; !ok || cur.TenantID != tid {
+		return entity.Team{}, gorm.ErrRecordNotFound
+	}
+	t.TenantID = tid
+	r.teams[t.ID] = t
+	return t, nil
+}
+
+func (r *OrgRepository) GetTeam(ctx context.Context, id uint) (entity.Team, error) {
+	tid, err := r.ctxTenant(ctx)
+	if err != nil {
+		return entity.Team{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.teams[id]
+	if !ok || t.TenantID != tid {
+		return entity.Team{}, gorm.ErrRecordNotFound
+	}
+	return t, nil
+}
+
+func (r *OrgRepository) ListTeams(ctx context.Context, departmentID uint) ([]entity.Team, error) {
+	tid, err := r.ctxTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []entity.Team{}
+	for _, t := range r.teams {
+		if t.TenantID == tid && (departmentID == 0 || t.DepartmentID == departmentID) {
+			out = append(out, t)
+		}
+	}
+	slices.SortFunc(out, func(a, b entity.Team) int {
+		return cmp.Or(cmp.Compare(a.DepartmentID, b.DepartmentID), cmp.Compare(a.Name, b.Name))
+	})
+	return out, nil
+}
+
+func (r *OrgRepository) GetMember(ctx context.Context, userID uint) (entity.Member, error) {
+	tid, err := r.ctxTenant(ctx)
+	if err != nil {
+		return entity.Member{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, ok := r.members[userID]
+	if !ok || r.tenantOf[userID] != tid {
+		return entity.Member{}, gorm.ErrRecordNotFound
+	}
+	return *m, nil
+}
+
+func (r *OrgRepository) ListMembers(ctx context.Context, teamID uint) ([]entity.Member, error) {
+	tid, err := r.ctxTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []entity.Member{}
+	for id, m := range r.members {
+		if r.tenantOf[id] == tid && m.TeamID != nil && *m.TeamID == teamID {
+			out = append(out, *m)
+		}
+	}
+	slices.SortFunc(out, func(a, b entity.Member) int { return cmp.Compare(a.ID, b.ID) })
+	return out, nil
+}
+
+// Reports: penelusuran melebar dari managerID seperti CTE rekursifnya.
+func (r *OrgRepository) Reports(ctx context.Context, managerID uint) ([]entity.Member, error) {
+	tid, err := r.ctxTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := map[uint]bool{managerID: true}
+	out := []entity.Member{}
+	level := []uint{managerID}
+	for depth := 1; len(level) > 0; depth++ {
+		var next []uint
+		for id, m := range r.members {
+			if r.tenantOf[id] != tid || seen[id] || m.ManagerID == nil || !slices.Contains(level, *m.ManagerID) {
+				continue
+			}
+			seen[id] = true
+			c := *m
+			c.Depth = depth
+			out = append(out, c)
+			next = append(next, id)
+		}
+		level = next
+	}
+	slices.SortFunc(out, func(a, b entity.Member) int {
+		return cmp.Or(cmp.Compare(a.Depth, b.Depth), cmp.Compare(a.ID, b.ID))
+	})
+	return out, nil
+}
+
+func (r *OrgRepository) Assign(ctx context.Context, line entity.ReportingLine) (entity.ReportingLine, error) {
+	tid, err := r.ctxTenant(ctx)
+	if err != nil {
+		return entity.ReportingLine{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.members[line.UserID]
+	if !ok || r.tenantOf[line.UserID] != tid {
+		return entity.ReportingLine{}, gorm.ErrRecordNotFound
+	}
+	m.TeamID, m.ManagerID = line.TeamID, line.ManagerID
+	for i := range r.lines {
+		if r.lines[i].UserID == line.UserID && r.lines[i].ValidTo == nil {
+			to := line.ValidFrom
+			r.lines[i].ValidTo = &to
+		}
+	}
+	line.ID, line.TenantID, line.CreatedAt = r.id(), tid, line.ValidFrom
+	r.lines = append(r.lines, line)
+	return line, nil
+}
+
+func (r *OrgRepository) History(ctx context.Context, userID uint) ([]entity.ReportingLine, error) {
+	tid, err := r.ctxTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []entity.ReportingLine{}
+	for _, l := range r.lines {
+		if l.TenantID == tid && l.UserID == userID {
+			out = append(out, l)
+		}
+	}
+	slices.SortFunc(out, func(a, b entity.ReportingLine) int {
+		return cmp.Or(b.ValidFrom.Compare(a.ValidFrom), cmp.Compare(b.ID, a.ID))
+	})
+	return out, nil
+}
+
+func (r *OrgRepository) SetRole(ctx context.Context, userID uint, role string) error {
+	tid, err := r.ctxTenant(ctx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, ok := r.members[userID]
+	if !ok || r.tenantOf[userID] != tid {
+		return gorm.ErrRecordNotFound
+	}
+	m.Role = role
+	return nil
+}
@@ -0,0 +1,90 @@
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"mojo-autotech/clock"
+	entity "mojo-autotech/model/tenant"
+)
+
+// TenantRepository meniru entity.ITenantRepository. Seperti migrasi 0005,
+// tenant id 1 "default" sudah ada sejak awal.
+type TenantRepository struct {
+	Err error
+
+	mu    sync.Mutex
+	clock clock.Clock
+	rows  []entity.Tenant
+}
+
+var _ entity.ITenantRepository = (*TenantRepository)(nil)
+
+func NewTenantRepository(clk clock.Clock) *TenantRepository {
+	now := clk.Now()
+	return &TenantRepository{
+		clock: clk,
+		rows: []entity.Tenant{{
+			ID: 1, Code: "default", Name: "Mojo Autotech", IsActive: true,
+			CreatedAt: now, UpdatedAt: now,
+		}},
+	}
+}
+
+// SetActive mengaktifkan atau menonaktifkan tenant; tidak ada di interface
+// karena belum ada endpoint-nya.
+func (r *TenantRepository) SetActive(code string, active bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.rows {
+		if r.rows[i].Code == code {
+			r.rows[i].IsActive = active
+		}
+	}
+}
+
+// Create: code unik seperti constraint di tabel.
+func (r *TenantRepository) Create(_ context.Context, t entity.Tenant) (entity.Tenant, error) {
+	if r.Err != nil {
+		return entity.Tenant{}, r.Err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, cur := range r.rows {
+		if cur.Code == t.Code {
+			return entity.Tenant{}, gorm.ErrDuplicatedKey
+		}
+	}
+	now := r.clock.Now()
+	t.ID = r.rows[len(r.rows)-1].ID + 1
+	t.CreatedAt, t.UpdatedAt = now, now
+	r.rows = append(r.rows, t)
+	return t, nil
+}
+
+func (r *TenantRepository) GetByCode(_ context.Context, code string) (entity.Tenant, error) {
+	if r.Err != nil {
+		return entity.Tenant{}, r.Err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, t := range r.rows {
+		if t.Code == code {
+			return t, nil
+		}
+	}
+	return entity.Tenant{}, gorm.ErrRecordNotFound
+}
+
+func (r *TenantRepository) List(_ context.Context) ([]entity.Tenant, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]entity.Tenant(nil), r.rows...), nil
+}
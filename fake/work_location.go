@@ -0,0 +1,106 @@
+package fake
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"gorm.io/gorm"
+
+	entity "mojo-autotech/model/work_location"
+	"mojo-autotech/tenant"
+)
+
+// WorkLocationRepository meniru entity.IWorkLocationRepository; baris tenant
+// lain diperlakukan seperti tidak ada.
+type WorkLocationRepository struct {
+	Err error
+
+	mu     sync.Mutex
+	nextID uint
+	rows   map[uint]entity.WorkLocation
+}
+
+var _ entity.IWorkLocationRepository = (*WorkLocationRepository)(nil)
+
+func NewWorkLocationRepository() *WorkLocationRepository {
+	return &WorkLocationRepository{rows: map[uint]entity.WorkLocation{}}
+}
+
+func (r *WorkLocationRepository) Create(ctx context.Context, w entity.WorkLocation) (entity.WorkLocation, error) {
+	if r.Err != nil {
+		return entity.WorkLocation{}, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return entity.WorkLocation{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	w.ID = r.nextID
+	w.TenantID = tid
+	r.rows[w.ID] = w
+	return w, nil
+}
+
+func (r *WorkLocationRepository) Update(ctx context.Context, w entity.WorkLocation) (entity.WorkLocation, error) {
+	if r.Err != nil {
+		return entity.WorkLocation{}, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return entity.WorkLocation{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cur, ok := r.rows[w.ID]; !ok || cur.TenantID != tid {
+		return entity.WorkLocation{}, gorm.ErrRecordNotFound
+	}
+	w.TenantID = tid
+	r.rows[w.ID] = w
+	return w, nil
+}
+
+func (r *WorkLocationRepository) GetByID(ctx context.Context, id uint) (entity.WorkLocation, error) {
+	if r.Err != nil {
+		return entity.WorkLocation{}, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return entity.WorkLocation{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w, ok := r.rows[id]
+	if !ok || w.TenantID != tid {
+		return entity.WorkLocation{}, gorm.ErrRecordNotFound
+	}
+	return w, nil
+}
+
+func (r *WorkLocationRepository) List(ctx context.Context) ([]entity.WorkLocation, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]entity.WorkLocation, 0, len(r.rows))
+	for _, w := range r.rows {
+		if w.TenantID == tid {
+			out = append(out, w)
+		}
+	}
+	// urut id seperti ORDER BY id
+	slices.SortFunc(out, func(a, b entity.WorkLocation) int { return cmp.Compare(a.ID, b.ID) })
+	return out, nil
+}
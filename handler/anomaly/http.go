@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	mid "mojo-autotech/middleware"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+	anomalySvc "mojo-autotech/service/anomaly"
+)
+
+// scope membatasi MANAGER ke anomali bawahannya, lihat mid.ReportScope.
+func HttpAnomalyHandler(router gin.IRouter, svc anomalySvc.IAnomalyService, auth, scope gin.HandlerFunc) {
+	h := NewAnomalyHandler(svc)
+	g := router.Group("/anomalies", auth, mid.RequireRole("ADMIN", "MANAGER"), scope)
+	{
+		g.GET("", h.List)
+		g.POST("/:id/review", h.Review)
+	}
+}
+
+type AnomalyHandler struct {
+	anomaly anomalySvc.IAnomalyService
+}
+
+func NewAnomalyHandler(svc anomalySvc.IAnomalyService) *AnomalyHandler {
+	return &AnomalyHandler{
+		anomaly: svc,
+	}
+}
+
+// List: antrean review, default hanya yang masih OPEN. ?status=ALL untuk semua.
+func (h *AnomalyHandler) List(ctx *gin.Context) {
+	status := ctx.DefaultQuery("status", "OPEN")
+	if status == "ALL" {
+		status = ""
+	}
+
+	out, err := h.anomaly.List(ctx.Request.Context(), status, mid.CurrentScope(ctx))
+	if err != nil {
+		mid.Fail(ctx, i18n.AnomalyListFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.AnomalyList, out)
+}
+
+func (h *AnomalyHandler) Review(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Validation(i18n.AnomalyIDInvalid))
+		return
+	}
+	var param anomalySvc.ReviewReq
+	if err := ctx.ShouldBind(&param); err != nil {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
+		return
+	}
+	reviewerID, _ := mid.CurrentUserID(ctx)
+
+	out, err := h.anomaly.Review(ctx.Request.Context(), uint(id), reviewerID, mid.CurrentScope(ctx), param)
+	if err != nil {
+		mid.Fail(ctx, i18n.AnomalyReviewFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.AnomalyReviewed, out)
+}
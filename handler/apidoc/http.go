@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/apidoc"
+)
+
+// Dokumentasi API; tanpa auth supaya tim mobile/web bisa membukanya langsung.
+func HttpDocsHandler(router gin.IRouter) {
+	router.GET("/docs", UI)
+	router.GET("/docs/swagger-init.js", Script)
+	router.GET("/docs/openapi.yaml", SpecYAML)
+	router.GET("/docs/openapi.json", SpecJSON)
+}
+
+// UI menimpa CSP default API (default-src 'none') yang akan memblokir Swagger UI.
+func UI(ctx *gin.Context) {
+	ctx.Header("Content-Security-Policy", apidoc.CSP)
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", apidoc.SwaggerUI())
+}
+
+func Script(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/javascript; charset=utf-8", apidoc.SwaggerInit())
+}
+
+func SpecYAML(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "application/yaml", apidoc.YAML())
+}
+
+func SpecJSON(ctx *gin.Context) {
+	b, err := apidoc.JSON()
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.Data(http.StatusOK, "application/json", b)
+}
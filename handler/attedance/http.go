@@ -3,146 +3,109 @@ package handler
 import (
 	"net/http"
 
-	mid "mojo-autotech/middleware"
-
 	"github.com/gin-gonic/gin"
 
-	"mojo-autotech/constant"
-	"mojo-autotech/model"
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+	mid "mojo-autotech/middleware"
 	attSvc "mojo-autotech/service/attedance"
+	"mojo-autotech/tracing"
 )
 
-func HttpAttendanceHandler(router *gin.Engine) {
-	h := NewAttendanceHandler()
-	router.POST("/attendance/check-in", mid.Auth(), h.CheckIn)
-	router.POST("/attendance/check-out", mid.Auth(), h.CheckOut)
-	router.GET("/attendance/today", mid.Auth(), h.Today)
-}
+var tracer = tracing.Tracer("handler/attendance")
 
-var attendance = func() attSvc.IAttendanceService {
-	return attSvc.NewAttendanceService()
+// limit dan idem dihitung per user, jadi harus setelah auth.
+func HttpAttendanceHandler(router gin.IRouter, svc attSvc.IAttendanceService, auth, limit, idem gin.HandlerFunc) {
+	h := NewAttendanceHandler(svc)
+	router.POST("/attendance/check-in", auth, limit, idem, h.CheckIn)
+	router.POST("/attendance/check-out", auth, limit, idem, h.CheckOut)
+	router.GET("/attendance/today", auth, h.Today)
 }
 
 type AttendanceHandler struct {
 	attendance attSvc.IAttendanceService
 }
 
-func NewAttendanceHandler() *AttendanceHandler {
+func NewAttendanceHandler(svc attSvc.IAttendanceService) *AttendanceHandler {
 	return &AttendanceHandler{
-		attendance: attendance(),
+		attendance: svc,
 	}
 }
 
 func (h *AttendanceHandler) CheckIn(ctx *gin.Context) {
+	reqCtx, span := tracer.Start(ctx.Request.Context(), "AttendanceHandler.CheckIn")
+	defer span.End()
+
 	var param attSvc.CheckInReq
 	if err := ctx.ShouldBind(&param); err != nil {
-		ctx.JSON(http.StatusBadRequest, model.Response{
-			Code: http.StatusBadRequest,
-			Msg:  constant.ReqParamInvalid,
-			Err:  err.Error(),
-		})
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
 		return
 	}
 
 	uid, ok := ctx.Get("user_id")
 	if !ok {
-		ctx.JSON(http.StatusUnauthorized, model.Response{
-			Code: http.StatusUnauthorized,
-			Msg:  "Unauthorized",
-			Err:  "user_id tidak ditemukan di context",
-		})
+		mid.Fail(ctx, i18n.Unauthorized, apperror.Unauthorized(i18n.UserIDMissing))
 		return
 	}
 	userID, ok := toUint(uid)
 	if !ok || userID == 0 {
-		ctx.JSON(http.StatusUnauthorized, model.Response{
-			Code: http.StatusUnauthorized,
-			Msg:  "Unauthorized",
-			Err:  "tipe/isi user_id tidak valid",
-		})
+		mid.Fail(ctx, i18n.Unauthorized, apperror.Unauthorized(i18n.UserIDInvalid))
 		return
 	}
 
 	param.UserId = userID
 	param.IP = ctx.ClientIP()
 
-	out, created, err := h.attendance.CheckIn(ctx.Request.Context(), param)
+	out, created, err := h.attendance.CheckIn(reqCtx, param)
 	if err != nil {
-		status := http.StatusInternalServerError
-		switch err.Error() {
-		case "unauthorized":
-			status = http.StatusUnauthorized
-		}
-		ctx.JSON(status, model.Response{
-			Code: status,
-			Msg:  "Gagal check-in",
-			Err:  err.Error(),
-		})
+		mid.Fail(ctx, i18n.CheckInFailed, err)
 		return
 	}
 
 	code := http.StatusCreated
-	msg := "Check-in berhasil"
+	msg := i18n.CheckInOK
 	if !created {
 		code = http.StatusOK
-		msg = "Check-in diperbarui"
+		msg = i18n.CheckInUpdated
 	}
-	ctx.JSON(code, model.Response{
-		Code: code,
-		Msg:  msg,
-		Data: out,
-	})
+	mid.Respond(ctx, code, msg, out)
 }
 
 func (h *AttendanceHandler) CheckOut(ctx *gin.Context) {
+	reqCtx, span := tracer.Start(ctx.Request.Context(), "AttendanceHandler.CheckOut")
+	defer span.End()
+
+	// body opsional: kiosk_qr kalau check-out di depan kiosk, work_location_id
+	// kalau lokasi check-in punya timezone sendiri
+	var param attSvc.CheckOutReq
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&param); err != nil {
+			mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
+			return
+		}
+	}
+
 	uid, ok := ctx.Get("user_id")
 	if !ok {
-		ctx.JSON(http.StatusUnauthorized, model.Response{
-			Code: http.StatusUnauthorized,
-			Msg:  "Unauthorized",
-			Err:  "user_id tidak ditemukan di context",
-		})
+		mid.Fail(ctx, i18n.Unauthorized, apperror.Unauthorized(i18n.UserIDMissing))
 		return
 	}
 	userID, ok := toUint(uid)
 	if !ok || userID == 0 {
-		ctx.JSON(http.StatusUnauthorized, model.Response{
-			Code: http.StatusUnauthorized,
-			Msg:  "Unauthorized",
-			Err:  "tipe/isi user_id tidak valid",
-		})
+		mid.Fail(ctx, i18n.Unauthorized, apperror.Unauthorized(i18n.UserIDInvalid))
 		return
 	}
 
-	out, err := h.attendance.CheckOut(ctx.Request.Context(), attSvc.CheckOutReq{
-		UserId: userID,
-		IP:     ctx.ClientIP(),
-	})
+	param.UserId = userID
+	param.IP = ctx.ClientIP()
+
+	out, err := h.attendance.CheckOut(reqCtx, param)
 	if err != nil {
-		status := http.StatusInternalServerError
-		switch err.Error() {
-		case "unauthorized":
-			status = http.StatusUnauthorized
-		case "belum check-in":
-			status = http.StatusConflict
-		case "sudah check-out":
-			status = http.StatusConflict
-		case "belum check-in atau sudah check-out":
-			status = http.StatusConflict
-		}
-		ctx.JSON(status, model.Response{
-			Code: status,
-			Msg:  "Gagal check-out",
-			Err:  err.Error(),
-		})
+		mid.Fail(ctx, i18n.CheckOutFailed, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, model.Response{
-		Code: http.StatusOK,
-		Msg:  "Check-out berhasil",
-		Data: out,
-	})
+	mid.Respond(ctx, http.StatusOK, i18n.CheckOutOK, out)
 }
 
 func toUint(v any) (uint, bool) {
@@ -166,42 +129,25 @@ func toUint(v any) (uint, bool) {
 }
 
 func (h *AttendanceHandler) Today(ctx *gin.Context) {
+	reqCtx, span := tracer.Start(ctx.Request.Context(), "AttendanceHandler.Today")
+	defer span.End()
+
 	uid, ok := ctx.Get("user_id")
 	if !ok {
-		ctx.JSON(http.StatusUnauthorized, model.Response{
-			Code: http.StatusUnauthorized,
-			Msg:  "Unauthorized",
-			Err:  "user_id tidak ditemukan di context",
-		})
+		mid.Fail(ctx, i18n.Unauthorized, apperror.Unauthorized(i18n.UserIDMissing))
 		return
 	}
 	userID, ok := toUint(uid)
 	if !ok || userID == 0 {
-		ctx.JSON(http.StatusUnauthorized, model.Response{
-			Code: http.StatusUnauthorized,
-			Msg:  "Unauthorized",
-			Err:  "tipe/isi user_id tidak valid",
-		})
+		mid.Fail(ctx, i18n.Unauthorized, apperror.Unauthorized(i18n.UserIDInvalid))
 		return
 	}
 
-	out, err := h.attendance.GetToday(ctx.Request.Context(), userID)
+	out, err := h.attendance.GetToday(reqCtx, userID)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "unauthorized" {
-			status = http.StatusUnauthorized
-		}
-		ctx.JSON(status, model.Response{
-			Code: status,
-			Msg:  "Gagal memuat status hari ini",
-			Err:  err.Error(),
-		})
+		mid.Fail(ctx, i18n.TodayFailed, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, model.Response{
-		Code: http.StatusOK,
-		Msg:  "Status hari ini",
-		Data: out,
-	})
+	mid.Respond(ctx, http.StatusOK, i18n.TodayStatus, out)
 }
@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	_ "time/tzdata"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/clock"
+	"mojo-autotech/config"
+	"mojo-autotech/fake"
+	"mojo-autotech/i18n"
+	mid "mojo-autotech/middleware"
+	"mojo-autotech/model"
+	attSvc "mojo-autotech/service/attedance"
+	"mojo-autotech/utils"
+)
+
+var (
+	testJWT = utils.NewJWT("test-secret", "test")
+	errFake = errors.New("koneksi database putus")
+)
+
+// newTestRouter merakit handler dengan middleware asli (Errors, Language,
+// Auth, Idempotency) di atas service yang memakai fake repository.
+func newTestRouter(t *testing.T, now time.Time) (*gin.Engine, *fake.AttendanceRepository, *clock.Fake) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	wib, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clk := clock.NewFake(now.In(wib))
+	repo := fake.NewAttendanceRepository(clk)
+	shift := config.Shift{Start: 8 * time.Hour, LateGrace: 15 * time.Minute}
+	svc := attSvc.NewAttendanceService(repo, nil, fake.NewWorkLocationRepository(), nil, nil, wib, shift, clk)
+
+	router := gin.New()
+	router.Use(mid.Errors(), mid.Language())
+	pass := func(c *gin.Context) { c.Next() }
+	idem := mid.Idempotency(fake.NewIdempotencyRepository(clk), mid.IdempotencyPolicy{
+		TTL: 24 * time.Hour, Lock: time.Minute, Secret: []byte("test-secret"), Scope: mid.ByUser,
+	})
+	HttpAttendanceHandler(router, svc, mid.Auth(testJWT), pass, idem)
+	return router, repo, clk
+}
+
+// bearer membuat token karyawan di tenant default (id 1).
+func bearer(t *testing.T, uid uint) string {
+	t.Helper()
+	return bearerTenant(t, uid, 1)
+}
+
+func bearerTenant(t *testing.T, uid, tenantID uint) string {
+	t.Helper()
+	token, _, err := testJWT.GenerateAccessToken(uid, tenantID, "EMPLOYEE", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + token
+}
+
+func do(router *gin.Engine, method, path, auth, body string) (*httptest.ResponseRecorder, model.Response) {
+	return doKey(router, method, path, auth, "", body)
+}
+
+// doKey seperti do, dengan header Idempotency-Key kalau key tidak kosong.
+func doKey(router *gin.Engine, method, path, auth, key, body string) (*httptest.ResponseRecorder, model.Response) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(mid.IdempotencyKeyHeader, key)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var res model.Response
+	_ = json.Unmarshal(w.Body.Bytes(), &res)
+	return w, res
+}
+
+func TestCheckInThenCheckOut(t *testing.T) {
+	router, _, clk := newTestRouter(t, time.Date(2026, 3, 2, 0, 55, 0, 0, time.UTC)) // 07:55 WIB
+	auth := bearer(t, 7)
+
+	w, res := do(router, http.MethodPost, "/attendance/check-in", auth, `{"activity":"servis"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("check-in pertama: status %d, body %s", w.Code, w.Body)
+	}
+	if res.MsgCode == "" {
+		t.Fatalf("msg_code kosong: %s", w.Body)
+	}
+
+	w, _ = do(router, http.MethodPost, "/attendance/check-in", auth, `{"activity":"spooring"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("check-in kedua: status %d, mau 200", w.Code)
+	}
+
+	clk.Advance(8 * time.Hour)
+	w, _ = do(router, http.MethodPost, "/attendance/check-out", auth, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("check-out: status %d, body %s", w.Code, w.Body)
+	}
+
+	w, res = do(router, http.MethodPost, "/attendance/check-out", auth, "")
+	if w.Code != http.StatusConflict || res.ErrCode != "CONFLICT" {
+		t.Fatalf("check-out kedua: status %d err_code %q, mau 409 CONFLICT", w.Code, res.ErrCode)
+	}
+}
+
+func TestAttendanceIsScopedPerTenant(t *testing.T) {
+	router, repo, _ := newTestRouter(t, time.Date(2026, 3, 2, 0, 55, 0, 0, time.UTC)) // 07:55 WIB
+
+	w, _ := do(router, http.MethodPost, "/attendance/check-in", bearer(t, 7), `{"activity":"servis"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("check-in tenant 1: status %d, body %s", w.Code, w.Body)
+	}
+
+	// user id sama di tenant lain tidak melihat absensi tenant 1
+	other := bearerTenant(t, 7, 2)
+	w, res := do(router, http.MethodPost, "/attendance/check-out", other, "")
+	if w.Code != http.StatusConflict || res.ErrCode != "CONFLICT" {
+		t.Fatalf("check-out tenant 2: status %d err_code %q, mau 409 CONFLICT", w.Code, res.ErrCode)
+	}
+	if rows := repo.All(); len(rows) != 1 || rows[0].CheckOutAt != nil {
+		t.Fatalf("absensi tenant 1 ikut berubah: %+v", rows)
+	}
+}
+
+func TestCheckInValidation(t *testing.T) {
+	router, repo, _ := newTestRouter(t, time.Now())
+
+	w, res := do(router, http.MethodPost, "/attendance/check-in", bearer(t, 7), `{}`)
+	if w.Code != http.StatusBadRequest || res.ErrCode != "VALIDATION" {
+		t.Fatalf("status %d err_code %q, mau 400 VALIDATION", w.Code, res.ErrCode)
+	}
+	if len(repo.All()) != 0 {
+		t.Fatal("request invalid tidak boleh menyentuh repository")
+	}
+}
+
+func TestAttendanceRequiresToken(t *testing.T) {
+	router, _, _ := newTestRouter(t, time.Now())
+
+	cases := map[string]string{
+		"tanpa token":  "",
+		"token rusak":  "Bearer bukan.jwt.valid",
+		"bukan bearer": "Basic dXNlcjpwYXNz",
+		"tanpa tenant": bearerTenant(t, 7, 0),
+	}
+	for name, auth := range cases {
+		t.Run(name, func(t *testing.T) {
+			w, res := do(router, http.MethodGet, "/attendance/today", auth, "")
+			if w.Code != http.StatusUnauthorized || res.ErrCode != "UNAUTHORIZED" {
+				t.Fatalf("status %d err_code %q, mau 401 UNAUTHORIZED", w.Code, res.ErrCode)
+			}
+		})
+	}
+}
+
+func TestAttendanceDatabaseError(t *testing.T) {
+	router, repo, _ := newTestRouter(t, time.Now())
+	repo.Err = errFake
+
+	w, res := do(router, http.MethodGet, "/attendance/today", bearer(t, 7), "")
+	if w.Code != http.StatusInternalServerError || res.ErrCode != "INTERNAL" {
+		t.Fatalf("status %d err_code %q, mau 500 INTERNAL", w.Code, res.ErrCode)
+	}
+	if strings.Contains(w.Body.String(), errFake.Error()) {
+		t.Fatal("detail error internal tidak boleh bocor ke response")
+	}
+}
+
+func TestCheckInIdempotencyKey(t *testing.T) {
+	router, repo, clk := newTestRouter(t, time.Date(2026, 3, 2, 0, 55, 0, 0, time.UTC))
+	auth := bearer(t, 7)
+	const path, key = "/attendance/check-in", "3f1c9a52-7d0e-4b8e-9a61-0c5f2d7b8e11"
+
+	first, _ := doKey(router, http.MethodPost, path, auth, key, `{"activity":"servis"}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("check-in pertama: status %d, body %s", first.Code, first.Body)
+	}
+
+	// retry (mis. koneksi putus sebelum response diterima) mendapat response
+	// pertama, bukan 200 "sudah check-in"
+	clk.Advance(time.Minute)
+	w, _ := doKey(router, http.MethodPost, path, auth, key, `{"activity":"servis"}`)
+	if w.Code != http.StatusCreated || w.Body.String() != first.Body.String() {
+		t.Fatalf("replay: status %d body %s, mau sama dengan response pertama", w.Code, w.Body)
+	}
+	if w.Header().Get(mid.ReplayedHeader) != "true" {
+		t.Fatalf("header %s tidak dikirim pada replay", mid.ReplayedHeader)
+	}
+	if len(repo.All()) != 1 {
+		t.Fatalf("replay tidak boleh membuat absensi baru: %d baris", len(repo.All()))
+	}
+
+	w, res := doKey(router, http.MethodPost, path, auth, key, `{"activity":"spooring"}`)
+	if w.Code != http.StatusUnprocessableEntity || res.ErrCode != "UNPROCESSABLE" {
+		t.Fatalf("key dipakai untuk body lain: status %d err_code %q, mau 422 UNPROCESSABLE", w.Code, res.ErrCode)
+	}
+	if res.MsgCode != string(i18n.IdemKeyReused) {
+		t.Fatalf("key dipakai untuk body lain: msg_code %q, mau %q", res.MsgCode, i18n.IdemKeyReused)
+	}
+
+	// key milik user lain tidak bentrok
+	w, _ = doKey(router, http.MethodPost, path, bearer(t, 8), key, `{"activity":"servis"}`)
+	if w.Code != http.StatusCreated || w.Header().Get(mid.ReplayedHeader) != "" {
+		t.Fatalf("user lain dengan key sama: status %d, mau 201 tanpa replay", w.Code)
+	}
+
+	w, res = doKey(router, http.MethodPost, path, auth, "key dengan spasi", `{"activity":"servis"}`)
+	if w.Code != http.StatusBadRequest || res.ErrCode != "VALIDATION" {
+		t.Fatalf("key invalid: status %d err_code %q, mau 400 VALIDATION", w.Code, res.ErrCode)
+	}
+}
+
+func TestIdempotencyKeyReleasedOnServerError(t *testing.T) {
+	router, repo, _ := newTestRouter(t, time.Date(2026, 3, 2, 0, 55, 0, 0, time.UTC))
+	auth := bearer(t, 7)
+	const key = "retry-setelah-500"
+
+	repo.Err = errFake
+	w, _ := doKey(router, http.MethodPost, "/attendance/check-in", auth, key, `{"activity":"servis"}`)
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status %d, mau 500", w.Code)
+	}
+
+	// 5xx tidak disimpan: retry dengan key yang sama diproses ulang
+	repo.Err = nil
+	w, _ = doKey(router, http.MethodPost, "/attendance/check-in", auth, key, `{"activity":"servis"}`)
+	if w.Code != http.StatusCreated || w.Header().Get(mid.ReplayedHeader) != "" {
+		t.Fatalf("retry setelah 500: status %d, mau 201 tanpa replay", w.Code)
+	}
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/i18n"
+	mid "mojo-autotech/middleware"
+	healthSvc "mojo-autotech/service/health"
+)
+
+// Probe untuk orchestrator; tanpa auth dan tanpa akses data user.
+func HttpHealthHandler(router gin.IRouter, svc healthSvc.IHealthService) {
+	h := NewHealthHandler(svc)
+	router.GET("/healthz", h.Live)
+	router.GET("/readyz", h.Ready)
+	router.GET("/version", h.Version)
+}
+
+type HealthHandler struct {
+	health healthSvc.IHealthService
+}
+
+func NewHealthHandler(svc healthSvc.IHealthService) *HealthHandler {
+	return &HealthHandler{
+		health: svc,
+	}
+}
+
+// Live hanya menandakan proses masih melayani HTTP; sengaja tidak menyentuh DB
+// supaya DB yang lambat tidak membuat pod di-restart berulang.
+func (h *HealthHandler) Live(ctx *gin.Context) {
+	mid.Respond(ctx, http.StatusOK, i18n.HealthOK, nil)
+}
+
+func (h *HealthHandler) Ready(ctx *gin.Context) {
+	out := h.health.Ready(ctx.Request.Context())
+	if !out.Ready {
+		mid.Respond(ctx, http.StatusServiceUnavailable, i18n.NotReady, out)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.Ready, out)
+}
+
+func (h *HealthHandler) Version(ctx *gin.Context) {
+	mid.Respond(ctx, http.StatusOK, i18n.VersionInfo, h.health.Version(ctx.Request.Context()))
+}
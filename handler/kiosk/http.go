@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	mid "mojo-autotech/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+	kioskSvc "mojo-autotech/service/kiosk"
+	"mojo-autotech/tenant"
+)
+
+// KioskKeyHeader dikirim perangkat kiosk di setiap request.
+const KioskKeyHeader = "X-Kiosk-Key"
+
+// badgeLimit dipasang di /kiosk/punch setelah DeviceAuth, biasanya
+// mid.RateLimit dengan key ByBadge.
+func HttpKioskHandler(router gin.IRouter, svc kioskSvc.IKioskService, auth, badgeLimit gin.HandlerFunc) {
+	h := NewKioskHandler(svc)
+
+	admin := router.Group("/kiosks", auth, mid.RequireRole("ADMIN"))
+	{
+		admin.POST("", h.Register)
+		admin.GET("", h.List)
+		admin.PUT("/badges", h.SetBadge)
+	}
+
+	device := router.Group("/kiosk", h.DeviceAuth())
+	{
+		device.GET("/qr", h.QR)
+		device.POST("/punch", badgeLimit, h.Punch)
+	}
+}
+
+type KioskHandler struct {
+	kiosk kioskSvc.IKioskService
+}
+
+func NewKioskHandler(svc kioskSvc.IKioskService) *KioskHandler {
+	return &KioskHandler{
+		kiosk: svc,
+	}
+}
+
+// DeviceAuth memastikan request berasal dari kiosk terdaftar lalu menyimpannya
+// di context, bersama tenant kiosk untuk repository.
+func (h *KioskHandler) DeviceAuth() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		k, err := h.kiosk.Authenticate(ctx.Request.Context(), ctx.GetHeader(KioskKeyHeader))
+		if err != nil {
+			mid.Fail(ctx, i18n.Unauthorized, err)
+			return
+		}
+		ctx.Set("kiosk", k)
+		ctx.Request = ctx.Request.WithContext(tenant.WithID(ctx.Request.Context(), k.TenantID))
+		ctx.Next()
+	}
+}
+
+// ByBadge: satu bucket rate limit per badge di tenant kiosk, jadi tebakan PIN
+// untuk satu badge dibatasi walau datang dari banyak kiosk atau IP. Wajib
+// dipasang setelah DeviceAuth. Body dibaca dengan ShouldBindBodyWith supaya
+// Punch masih bisa membacanya. Body rusak atau tanpa badge_id (mis. punch QR)
+// jatuh ke bucket per IP, bukan satu bucket kosong bersama seluruh tenant.
+func ByBadge(c *gin.Context) string {
+	var req kioskSvc.PunchReq
+	badge := ""
+	if err := c.ShouldBindBodyWith(&req, binding.JSON); err == nil {
+		badge = strings.TrimSpace(req.BadgeID)
+	}
+	if badge == "" {
+		return mid.ByIP(c)
+	}
+	tid, _ := tenant.ID(c.Request.Context())
+	return "badge:" + strconv.FormatUint(uint64(tid), 10) + ":" + badge
+}
+
+func (h *KioskHandler) Register(ctx *gin.Context) {
+	var param kioskSvc.RegisterKioskReq
+	if err := ctx.ShouldBind(&param); err != nil {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
+		return
+	}
+
+	out, err := h.kiosk.Register(ctx.Request.Context(), param)
+	if err != nil {
+		mid.Fail(ctx, i18n.KioskRegisterFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusCreated, i18n.KioskRegistered, out)
+}
+
+func (h *KioskHandler) List(ctx *gin.Context) {
+	out, err := h.kiosk.List(ctx.Request.Context())
+	if err != nil {
+		mid.Fail(ctx, i18n.KioskListFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.KioskList, out)
+}
+
+func (h *KioskHandler) SetBadge(ctx *gin.Context) {
+	var param kioskSvc.SetBadgeReq
+	if err := ctx.ShouldBind(&param); err != nil {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
+		return
+	}
+
+	if err := h.kiosk.SetBadge(ctx.Request.Context(), param); err != nil {
+		mid.Fail(ctx, i18n.BadgeSaveFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.BadgeSaved, nil)
+}
+
+func (h *KioskHandler) QR(ctx *gin.Context) {
+	k := ctx.MustGet("kiosk").(kioskSvc.Kiosk)
+
+	out, err := h.kiosk.IssueQR(ctx.Request.Context(), k)
+	if err != nil {
+		mid.Fail(ctx, i18n.KioskQRFailed, err)
+		return
+	}
+	ctx.Header("Cache-Control", "no-store")
+	mid.Respond(ctx, http.StatusOK, i18n.KioskQR, out)
+}
+
+func (h *KioskHandler) Punch(ctx *gin.Context) {
+	var param kioskSvc.PunchReq
+	if err := ctx.ShouldBindBodyWith(&param, binding.JSON); err != nil {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
+		return
+	}
+	k := ctx.MustGet("kiosk").(kioskSvc.Kiosk)
+
+	out, created, err := h.kiosk.Punch(ctx.Request.Context(), k, param, ctx.ClientIP())
+	if err != nil {
+		mid.Fail(ctx, i18n.KioskPunchFailed, err)
+		return
+	}
+
+	code := http.StatusOK
+	msg := i18n.CheckOutOK
+	if param.Type == "IN" {
+		msg = i18n.CheckInUpdated
+		if created {
+			code = http.StatusCreated
+			msg = i18n.CheckInOK
+		}
+	}
+	mid.Respond(ctx, code, msg, out)
+}
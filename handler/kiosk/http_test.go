@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/clock"
+	"mojo-autotech/config"
+	"mojo-autotech/fake"
+	mid "mojo-autotech/middleware"
+	entity "mojo-autotech/model/kiosk"
+	"mojo-autotech/ratelimit"
+	attSvc "mojo-autotech/service/attedance"
+	kioskSvc "mojo-autotech/service/kiosk"
+	"mojo-autotech/tenant"
+)
+
+// TestPunchRateLimitedPerBadge: bucket dihitung per badge, bukan per kiosk,
+// dan Punch masih bisa membaca body setelah ByBadge.
+func TestPunchRateLimitedPerBadge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	clk := clock.NewFake(time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC))
+	repo := fake.NewKioskRepository(clk)
+	repo.AddBadge(entity.Badge{UserID: 7, Username: "budi", IsActive: true}, "B-001", "1234")
+	shift := config.Shift{Start: 8 * time.Hour, LateGrace: 15 * time.Minute}
+	attendance := attSvc.NewAttendanceService(fake.NewAttendanceRepository(clk), repo, fake.NewWorkLocationRepository(),
+		nil, nil, time.FixedZone("WIB", 7*60*60), shift, clk)
+	svc := kioskSvc.NewKioskService(repo, attendance, nil, config.Kiosk{PINMaxFailed: 5, PINLockout: 15 * time.Minute}, clk)
+	reg, err := svc.Register(tenant.WithID(context.Background(), 1), kioskSvc.RegisterKioskReq{Code: "K1", Name: "Bay 1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	router.Use(mid.Errors(), mid.Language())
+	limit := mid.RateLimit(ratelimit.NewMemoryStore(), "badge", config.Rule{Burst: 1, Refill: time.Hour}, ByBadge)
+	HttpKioskHandler(router, svc, func(c *gin.Context) { c.Next() }, limit)
+
+	punch := func(badge, pin string) int {
+		req := httptest.NewRequest(http.MethodPost, "/kiosk/punch",
+			strings.NewReader(`{"badge_id":"`+badge+`","pin":"`+pin+`","type":"IN"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(KioskKeyHeader, reg.DeviceKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := punch("B-001", "1234"); code != http.StatusCreated {
+		t.Fatalf("punch pertama: status %d, mau 201", code)
+	}
+	if code := punch("B-001", "1234"); code != http.StatusTooManyRequests {
+		t.Fatalf("punch kedua badge yang sama: status %d, mau 429", code)
+	}
+	if code := punch("B-002", "1234"); code != http.StatusUnauthorized {
+		t.Fatalf("badge lain: status %d, mau 401 (bucket terpisah)", code)
+	}
+
+	// body tanpa badge memakai bucket IP, bukan satu bucket kosong untuk seluruh tenant
+	noBadge := func(ip string) int {
+		req := httptest.NewRequest(http.MethodPost, "/kiosk/punch", strings.NewReader(`{"type":"IN"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(KioskKeyHeader, reg.DeviceKey)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	noBadge("10.0.0.1")
+	if code := noBadge("10.0.0.2"); code == http.StatusTooManyRequests {
+		t.Fatal("body tanpa badge dari kiosk lain ikut dibatasi")
+	}
+	if code := noBadge("10.0.0.1"); code != http.StatusTooManyRequests {
+		t.Fatalf("body tanpa badge dari IP yang sama: status %d, mau 429", code)
+	}
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+	"mojo-autotech/metrics"
+	mid "mojo-autotech/middleware"
+)
+
+// Endpoint scrape Prometheus. Token kosong hanya dipakai di listener terpisah
+// (lihat config.Metrics); selain itu scraper wajib mengirim bearer token.
+func HttpMetricsHandler(router gin.IRouter, token string) {
+	h := promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})
+	router.GET("/metrics", RequireToken(token), gin.WrapH(h))
+}
+
+// RequireToken membandingkan bearer token dengan waktu konstan.
+func RequireToken(token string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if token == "" {
+			ctx.Next()
+			return
+		}
+		got, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			mid.Fail(ctx, i18n.Unauthorized, apperror.Unauthorized(i18n.InvalidToken))
+			return
+		}
+		ctx.Next()
+	}
+}
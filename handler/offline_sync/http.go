@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	mid "mojo-autotech/middleware"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+	syncSvc "mojo-autotech/service/offline_sync"
+)
+
+// scope membatasi MANAGER ke punch bawahannya, lihat mid.ReportScope.
+func HttpOfflineSyncHandler(router gin.IRouter, svc syncSvc.IOfflineSyncService, auth, scope gin.HandlerFunc) {
+	h := NewOfflineSyncHandler(svc)
+	router.POST("/attendance/sync/devices", auth, h.RegisterDevice)
+	router.POST("/attendance/sync", auth, h.Sync)
+
+	review := router.Group("/attendance/sync/flagged", auth, mid.RequireRole("ADMIN", "MANAGER"), scope)
+	{
+		review.GET("", h.ListFlagged)
+		review.POST("/:id/review", h.Review)
+	}
+}
+
+type OfflineSyncHandler struct {
+	sync syncSvc.IOfflineSyncService
+}
+
+func NewOfflineSyncHandler(svc syncSvc.IOfflineSyncService) *OfflineSyncHandler {
+	return &OfflineSyncHandler{
+		sync: svc,
+	}
+}
+
+func (h *OfflineSyncHandler) RegisterDevice(ctx *gin.Context) {
+	var param syncSvc.RegisterDeviceReq
+	if err := ctx.ShouldBind(&param); err != nil {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
+		return
+	}
+	userID, ok := mid.CurrentUserID(ctx)
+	if !ok {
+		mid.Fail(ctx, i18n.Unauthorized, apperror.Unauthorized(i18n.UserIDMissing))
+		return
+	}
+
+	out, err := h.sync.RegisterDevice(ctx.Request.Context(), userID, param)
+	if err != nil {
+		mid.Fail(ctx, i18n.DeviceRegisterFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusCreated, i18n.DeviceRegistered, out)
+}
+
+func (h *OfflineSyncHandler) Sync(ctx *gin.Context) {
+	var param syncSvc.SyncReq
+	if err := ctx.ShouldBind(&param); err != nil {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
+		return
+	}
+	userID, ok := mid.CurrentUserID(ctx)
+	if !ok {
+		mid.Fail(ctx, i18n.Unauthorized, apperror.Unauthorized(i18n.UserIDMissing))
+		return
+	}
+
+	out, err := h.sync.Sync(ctx.Request.Context(), userID, ctx.ClientIP(), param)
+	if err != nil {
+		mid.Fail(ctx, i18n.SyncFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.SyncDone, out)
+}
+
+func (h *OfflineSyncHandler) ListFlagged(ctx *gin.Context) {
+	out, err := h.sync.ListFlagged(ctx.Request.Context(), mid.CurrentScope(ctx))
+	if err != nil {
+		mid.Fail(ctx, i18n.FlaggedListFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.FlaggedList, out)
+}
+
+func (h *OfflineSyncHandler) Review(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Validation(i18n.PunchIDInvalid))
+		return
+	}
+	var param syncSvc.ReviewReq
+	if err := ctx.ShouldBind(&param); err != nil {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
+		return
+	}
+	reviewerID, _ := mid.CurrentUserID(ctx)
+
+	out, err := h.sync.Review(ctx.Request.Context(), uint(id), reviewerID, mid.CurrentScope(ctx), param)
+	if err != nil {
+		mid.Fail(ctx, i18n.PunchReviewFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.PunchReviewed, out)
+}
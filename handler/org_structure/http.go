@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	mid "mojo-autotech/middleware"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+	orgSvc "mojo-autotech/service/org_structure"
+)
+
+func HttpOrgHandler(router gin.IRouter, svc orgSvc.IOrgService, auth gin.HandlerFunc) {
+	h := NewOrgHandler(svc)
+	// bawahan langsung dan tidak langsung milik user yang login
+	router.GET("/org/reports", auth, mid.RequireRole("ADMIN", "MANAGER"), h.Reports)
+
+	admin := router.Group("/org", auth, mid.RequireRole("ADMIN"))
+	{
+		admin.GET("/departments", h.ListDepartments)
+		admin.POST("/departments", h.CreateDepartment)
+		admin.PUT("/departments/:id", h.UpdateDepartment)
+		admin.GET("/teams", h.ListTeams)
+		admin.POST("/teams", h.CreateTeam)
+		admin.PUT("/teams/:id", h.UpdateTeam)
+		admin.GET("/teams/:id/members", h.ListMembers)
+		admin.PUT("/users/:id/assignment", h.Assign)
+		admin.GET("/users/:id/history", h.History)
+		admin.PUT("/users/:id/role", h.SetRole)
+	}
+}
+
+type OrgHandler struct {
+	org orgSvc.IOrgService
+}
+
+func NewOrgHandler(svc orgSvc.IOrgService) *OrgHandler {
+	return &OrgHandler{
+		org: svc,
+	}
+}
+
+func (h *OrgHandler) ListDepartments(ctx *gin.Context) {
+	out, err := h.org.ListDepartments(ctx.Request.Context())
+	if err != nil {
+		mid.Fail(ctx, i18n.DepartmentListFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.DepartmentList, out)
+}
+
+func (h *OrgHandler) CreateDepartment(ctx *gin.Context) {
+	var param orgSvc.DepartmentReq
+	if err := ctx.ShouldBind(&param); err != nil {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
+		return
+	}
+
+	out, err := h.org.CreateDepartment(ctx.Request.Context(), param)
+	if err != nil {
+		mid.Fail(ctx, i18n.DepartmentCreateFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusCreated, i18n.DepartmentCreated, out)
+}
+
+func (h *OrgHandler) UpdateDepartment(ctx *gin.Context) {
+	id, ok := pathID(ctx, i18n.DepartmentIDInvalid)
+	if !ok {
+		return
+	}
+	var param orgSvc.DepartmentReq
+	if err := ctx.ShouldBind(&param); err != nil {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
+		return
+	}
+
+	out, err := h.org.UpdateDepartment(ctx.Request.Context(), id, param)
+	if err != nil {
+		mid.Fail(ctx, i18n.DepartmentUpdateFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.DepartmentUpdated, out)
+}
+
+// ListTeams: ?department_id= untuk satu departemen saja.
+func (h *OrgHandler) ListTeams(ctx *gin.Context) {
+	var deptID uint64
+	if s := ctx.Query("department_id"); s != "" {
+		var err error
+		if deptID, err = strconv.ParseUint(s, 10, 64); err != nil || deptID == 0 {
+			mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Validation(i18n.DepartmentIDInvalid))
+			return
+		}
+	}
+
+	out, err := h.org.ListTeams(ctx.Request.Context(), uint(deptID))
+	if err != nil {
+		mid.Fail(ctx, i18n.TeamListFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.TeamList, out)
+}
+
+func (h *OrgHandler) CreateTeam(ctx *gin.Context) {
+	var param orgSvc.TeamReq
+	if err := ctx.ShouldBind(&param); err != nil {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
+		return
+	}
+
+	out, err := h.org.CreateTeam(ctx.Request.Context(), param)
+	if err != nil {
+		mid.Fail(ctx, i18n.TeamCreateFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusCreated, i18n.TeamCreated, out)
+}
+
+func (h *OrgHandler) UpdateTeam(ctx *gin.Context) {
+	id, ok := pathID(ctx, i18n.TeamIDInvalid)
+	if !ok {
+		return
+	}
+	var param orgSvc.TeamReq
+	if err := ctx.ShouldBind(&param); err != nil {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
+		return
+	}
+
+	out, err := h.org.UpdateTeam(ctx.Request.Context(), id, param)
+	if err != nil {
+		mid.Fail(ctx, i18n.TeamUpdateFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.TeamUpdated, out)
+}
+
+func (h *OrgHandler) ListMembers(ctx *gin.Context) {
+	id, ok := pathID(ctx, i18n.TeamIDInvalid)
+	if !ok {
+		return
+	}
+
+	out, err := h.org.ListMembers(ctx.Request.Context(), id)
+	if err != nil {
+		mid.Fail(ctx, i18n.MemberListFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.MemberList, out)
+}
+
+// Assign memindahkan karyawan ke tim dan/atau atasan lain.
+func (h *OrgHandler) Assign(ctx *gin.Context) {
+	id, ok := pathID(ctx, i18n.UserIDInvalid)
+	if !ok {
+		return
+	}
+	var param orgSvc.AssignReq
+	if err := ctx.ShouldBind(&param); err != nil {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
+		return
+	}
+	adminID, _ := mid.CurrentUserID(ctx)
+
+	out, err := h.org.Assign(ctx.Request.Context(), id, adminID, param)
+	if err != nil {
+		mid.Fail(ctx, i18n.AssignmentFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.AssignmentSaved, out)
+}
+
+func (h *OrgHandler) History(ctx *gin.Context) {
+	id, ok := pathID(ctx, i18n.UserIDInvalid)
+	if !ok {
+		return
+	}
+
+	out, err := h.org.History(ctx.Request.Context(), id)
+	if err != nil {
+		mid.Fail(ctx, i18n.AssignmentHistoryFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.AssignmentHistory, out)
+}
+
+func (h *OrgHandler) SetRole(ctx *gin.Context) {
+	id, ok := pathID(ctx, i18n.UserIDInvalid)
+	if !ok {
+		return
+	}
+	var param orgSvc.RoleReq
+	if err := ctx.ShouldBind(&param); err != nil {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
+		return
+	}
+
+	out, err := h.org.SetRole(ctx.Request.Context(), id, param)
+	if err != nil {
+		mid.Fail(ctx, i18n.RoleUpdateFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.RoleUpdated, out)
+}
+
+func (h *OrgHandler) Reports(ctx *gin.Context) {
+	userID, ok := mid.CurrentUserID(ctx)
+	if !ok {
+		mid.Fail(ctx, i18n.Unauthorized, apperror.Unauthorized(i18n.UserIDMissing))
+		return
+	}
+
+	out, err := h.org.Reports(ctx.Request.Context(), userID)
+	if err != nil {
+		mid.Fail(ctx, i18n.ReportsListFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.ReportsList, out)
+}
+
+// pathID membaca :id; kalau tidak valid response 400 sudah dikirim.
+func pathID(ctx *gin.Context, invalid i18n.Key) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Validation(invalid))
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	mid "mojo-autotech/middleware"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+	paySvc "mojo-autotech/service/payroll"
+)
+
+func HttpPayrollHandler(router gin.IRouter, svc paySvc.IPayrollService, auth gin.HandlerFunc) {
+	h := NewPayrollHandler(svc)
+	g := router.Group("/payroll", auth, mid.RequireRole("ADMIN"))
+	{
+		g.POST("/periods", h.CreatePeriod)
+		g.GET("/periods", h.ListPeriods)
+		g.GET("/periods/:id/summary", h.Summary)
+		g.GET("/periods/:id/export", h.Export)
+		g.POST("/periods/:id/close", h.Close)
+	}
+}
+
+type PayrollHandler struct {
+	payroll paySvc.IPayrollService
+}
+
+func NewPayrollHandler(svc paySvc.IPayrollService) *PayrollHandler {
+	return &PayrollHandler{
+		payroll: svc,
+	}
+}
+
+func (h *PayrollHandler) CreatePeriod(ctx *gin.Context) {
+	var param paySvc.CreatePeriodReq
+	if err := ctx.ShouldBind(&param); err != nil {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
+		return
+	}
+
+	out, err := h.payroll.CreatePeriod(ctx.Request.Context(), param)
+	if err != nil {
+		mid.Fail(ctx, i18n.PeriodCreateFailed, err)
+		return
+	}
+
+	mid.Respond(ctx, http.StatusCreated, i18n.PeriodCreated, out)
+}
+
+func (h *PayrollHandler) ListPeriods(ctx *gin.Context) {
+	out, err := h.payroll.ListPeriods(ctx.Request.Context())
+	if err != nil {
+		mid.Fail(ctx, i18n.PeriodListFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.PeriodList, out)
+}
+
+func (h *PayrollHandler) Summary(ctx *gin.Context) {
+	id, ok := periodID(ctx)
+	if !ok {
+		return
+	}
+
+	period, rows, err := h.payroll.Summaries(ctx.Request.Context(), id)
+	if err != nil {
+		mid.Fail(ctx, i18n.PayrollSummaryFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.PayrollSummary, gin.H{"period": period, "employees": rows})
+}
+
+// Export: preview file payroll, periode tetap OPEN.
+func (h *PayrollHandler) Export(ctx *gin.Context) {
+	id, ok := periodID(ctx)
+	if !ok {
+		return
+	}
+
+	file, err := h.payroll.Export(ctx.Request.Context(), id, ctx.Query("format"))
+	if err != nil {
+		mid.Fail(ctx, i18n.PayrollExportFailed, err)
+		return
+	}
+	writeFile(ctx, file)
+}
+
+// Close: export final lalu kunci periode.
+func (h *PayrollHandler) Close(ctx *gin.Context) {
+	id, ok := periodID(ctx)
+	if !ok {
+		return
+	}
+	adminID, _ := mid.CurrentUserID(ctx)
+
+	_, file, err := h.payroll.Close(ctx.Request.Context(), id, ctx.Query("format"), adminID)
+	if err != nil {
+		mid.Fail(ctx, i18n.PeriodCloseFailed, err)
+		return
+	}
+	writeFile(ctx, file)
+}
+
+func periodID(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Validation(i18n.PeriodIDInvalid))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeFile(ctx *gin.Context, file paySvc.ExportFile) {
+	ctx.Header("Content-Disposition", `attachment; filename="`+file.Filename+`"`)
+	ctx.Header("X-Checksum-SHA256", file.Checksum)
+	ctx.Data(http.StatusOK, file.ContentType, file.Body)
+}
@@ -1,87 +1,82 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
-	"mojo-autotech/constant"
-	"mojo-autotech/model"
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+	mid "mojo-autotech/middleware"
 	ua "mojo-autotech/model/user_authentication"
+	tenantSvc "mojo-autotech/service/tenant"
 	authsvc "mojo-autotech/service/user_authentication"
 )
 
-func HttpHandler(router *gin.Engine) {
-	handler := NewHandler()
+// limit dipasang sebelum handler supaya tebakan password ditolak sebelum
+// menyentuh bcrypt dan database. Middleware Idempotency-Key dipisah per
+// endpoint karena response login (berisi token) tidak boleh disimpan.
+//
+// /create hanya untuk ADMIN dan membuat akun di tenant token-nya; admin
+// pertama sebuah tenant dibuat lewat CLI `user create-admin`.
+func HttpHandler(router gin.IRouter, svc authsvc.IAuthService, auth, limit, idemLogin, idemCreate gin.HandlerFunc) {
+	handler := NewHandler(svc)
 	{
-		router.POST("/login", handler.Login)
-		router.POST("/create", handler.CreateAccount)
+		router.POST("/login", limit, idemLogin, handler.Login)
+		router.POST("/create", auth, mid.RequireRole("ADMIN"), idemCreate, handler.CreateAccount)
 	}
 }
 
-var authentication = func() authsvc.IAuthService {
-
-	return authsvc.NewAuthService()
+// LoginTenant dipakai IdempotencyPolicy.Tenant untuk /login: request belum
+// punya token, jadi tenant diambil dari field tenant di body seperti Login.
+// Body yang tidak valid jatuh ke tenant default; handler yang menolaknya.
+func LoginTenant(tenants tenantSvc.ITenantService) func(ctx context.Context, body []byte) (uint, error) {
+	return func(ctx context.Context, body []byte) (uint, error) {
+		var req struct {
+			Tenant string `json:"tenant"`
+		}
+		_ = json.Unmarshal(body, &req)
+		t, err := tenants.Resolve(ctx, req.Tenant)
+		return t.ID, err
+	}
 }
 
 type Handler struct {
 	authentication authsvc.IAuthService
 }
 
-func NewHandler() *Handler {
+func NewHandler(svc authsvc.IAuthService) *Handler {
 	return &Handler{
-		authentication: authentication(),
+		authentication: svc,
 	}
 }
 
 func (h *Handler) Login(ctx *gin.Context) {
 	var param ua.LoginReq
 	if err := ctx.ShouldBind(&param); err != nil {
-		ctx.JSON(http.StatusBadRequest, model.Response{
-			Code: http.StatusBadRequest,
-			Msg:  constant.ReqParamInvalid,
-			Err:  err.Error(),
-		})
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
 		return
 	}
-	res, err := h.authentication.Login(ctx, param)
+	res, err := h.authentication.Login(ctx.Request.Context(), param)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, model.Response{
-			Code: http.StatusInternalServerError,
-			Msg:  constant.LoginError,
-			Err:  err.Error(),
-		})
+		mid.Fail(ctx, i18n.LoginFailed, err)
 		return
 	}
-	ctx.JSON(http.StatusCreated, model.Response{
-		Code: http.StatusCreated,
-		Msg:  constant.LoginSuccess,
-		Data: res,
-	})
+	mid.Respond(ctx, http.StatusCreated, i18n.LoginSuccess, res)
 }
 
 func (h *Handler) CreateAccount(ctx *gin.Context) {
 	var param ua.RegisterReq
 	if err := ctx.ShouldBind(&param); err != nil {
-		ctx.JSON(http.StatusBadRequest, model.Response{
-			Code: http.StatusBadRequest,
-			Msg:  constant.ReqParamInvalid,
-			Err:  err.Error(),
-		})
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
 		return
 	}
-	res, err := h.authentication.CreateAccount(ctx, param)
+	res, err := h.authentication.CreateAccount(ctx.Request.Context(), param)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, model.Response{
-			Code: http.StatusInternalServerError,
-			Msg:  "Gagal membuat akun",
-			Err:  err.Error(),
-		})
+		mid.Fail(ctx, i18n.AccountCreateFailed, err)
 		return
 	}
-	ctx.JSON(http.StatusCreated, model.Response{
-		Code: http.StatusCreated,
-		Msg:  "Akun berhasil dibuat",
-		Data: res,
-	})
+	mid.Respond(ctx, http.StatusCreated, i18n.AccountCreated, res)
 }
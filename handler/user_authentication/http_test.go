@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/clock"
+	"mojo-autotech/config"
+	"mojo-autotech/fake"
+	mid "mojo-autotech/middleware"
+	"mojo-autotech/model"
+	ua "mojo-autotech/model/user_authentication"
+	tenantSvc "mojo-autotech/service/tenant"
+	authsvc "mojo-autotech/service/user_authentication"
+	"mojo-autotech/utils"
+)
+
+var testJWT = utils.NewJWT("test-secret", "test")
+
+func newTestRouter() (*gin.Engine, *fake.AuthRepository, *fake.IdempotencyRepository) {
+	gin.SetMode(gin.TestMode)
+
+	clk := clock.NewFake(time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC))
+	repo := fake.NewAuthRepository(clk)
+	repo.AddUser(ua.User{Username: "budi", Email: "budi@mojo.id", Role: "EMPLOYEE", IsActive: true}, "rahasia123")
+	tenants := tenantSvc.NewTenantService(fake.NewTenantRepository(clk), "default")
+	svc := authsvc.NewAuthService(repo, tenants, testJWT,
+		config.JWT{AccessTTL: 15 * time.Minute, RefreshTTL: time.Hour},
+		config.Login{MaxFailed: 2, Lockout: 15 * time.Minute}, clk)
+
+	store := fake.NewIdempotencyRepository(clk)
+	policy := mid.IdempotencyPolicy{TTL: 24 * time.Hour, Lock: time.Minute, Secret: []byte("test-secret"), Scope: mid.ByUser}
+	noStore := policy
+	noStore.Scope, noStore.SkipSuccess = mid.ByIP, true
+	noStore.Tenant = LoginTenant(tenants)
+
+	router := gin.New()
+	router.Use(mid.Errors(), mid.Language())
+	HttpHandler(router, svc, mid.Auth(testJWT), func(c *gin.Context) { c.Next() },
+		mid.Idempotency(store, noStore), mid.Idempotency(store, policy))
+	return router, repo, store
+}
+
+func login(router *gin.Engine, body string) (*httptest.ResponseRecorder, model.Response) {
+	return post(router, "/login", "", "", body)
+}
+
+func bearer(t *testing.T, uid, tenantID uint, role string) string {
+	t.Helper()
+	token, _, err := testJWT.GenerateAccessToken(uid, tenantID, role, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + token
+}
+
+func post(router *gin.Engine, path, auth, key, body string) (*httptest.ResponseRecorder, model.Response) {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	if key != "" {
+		req.Header.Set(mid.IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var res model.Response
+	_ = json.Unmarshal(w.Body.Bytes(), &res)
+	return w, res
+}
+
+func TestLogin(t *testing.T) {
+	router, _, _ := newTestRouter()
+
+	w, res := login(router, `{"username":"budi","password":"rahasia123"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status %d, body %s", w.Code, w.Body)
+	}
+	data, _ := res.Data.(map[string]any)
+	if data["access_token"] == "" || data["access_token"] == nil {
+		t.Fatalf("access_token kosong: %s", w.Body)
+	}
+	if strings.Contains(w.Body.String(), "password") {
+		t.Fatal("response login tidak boleh memuat password/hash")
+	}
+}
+
+func TestLoginFailures(t *testing.T) {
+	router, _, _ := newTestRouter()
+
+	w, res := login(router, `{"username":"budi"}`)
+	if w.Code != http.StatusBadRequest || res.ErrCode != "VALIDATION" {
+		t.Fatalf("tanpa password: status %d err_code %q, mau 400 VALIDATION", w.Code, res.ErrCode)
+	}
+
+	w, res = login(router, `{"username":"budi","password":"salah"}`)
+	if w.Code != http.StatusUnauthorized || res.ErrCode != "UNAUTHORIZED" {
+		t.Fatalf("password salah: status %d err_code %q, mau 401 UNAUTHORIZED", w.Code, res.ErrCode)
+	}
+
+	// percobaan gagal ke-2 mencapai MaxFailed
+	w, res = login(router, `{"username":"budi","password":"salah"}`)
+	if w.Code != http.StatusLocked || res.ErrCode != "LOCKED" {
+		t.Fatalf("akun dikunci: status %d err_code %q, mau 423 LOCKED", w.Code, res.ErrCode)
+	}
+
+	w, _ = login(router, `{"username":"budi","password":"rahasia123"}`)
+	if w.Code != http.StatusLocked {
+		t.Fatalf("password benar saat terkunci: status %d, mau 423", w.Code)
+	}
+}
+
+func TestLoginIdempotencyKeyNotStored(t *testing.T) {
+	router, _, store := newTestRouter()
+	body := `{"username":"budi","password":"rahasia123"}`
+
+	for i := 0; i < 2; i++ {
+		w, _ := post(router, "/login", "", "login-1", body)
+		if w.Code != http.StatusCreated || w.Header().Get(mid.ReplayedHeader) != "" {
+			t.Fatalf("login ke-%d: status %d, mau 201 tanpa replay", i+1, w.Code)
+		}
+	}
+	if store.Len() != 0 {
+		t.Fatal("response login berisi token, tidak boleh disimpan")
+	}
+
+	// login gagal disimpan di tenant dari body dan di-replay
+	wrong := `{"username":"budi","password":"salah"}`
+	first, _ := post(router, "/login", "", "login-2", wrong)
+	w, _ := post(router, "/login", "", "login-2", wrong)
+	if first.Code != http.StatusUnauthorized || w.Code != http.StatusUnauthorized || w.Header().Get(mid.ReplayedHeader) != "true" {
+		t.Fatalf("login gagal: status %d lalu %d (replay %q), mau 401 yang di-replay", first.Code, w.Code, w.Header().Get(mid.ReplayedHeader))
+	}
+}
+
+func TestCreateAccountRequiresAdmin(t *testing.T) {
+	router, repo, _ := newTestRouter()
+	body := `{"tenant":"default","user_id":2,"username":"sari","email":"sari@mojo.id","full_name":"Sari","password":"rahasia123"}`
+
+	if w, _ := post(router, "/create", "", "", body); w.Code != http.StatusUnauthorized {
+		t.Fatalf("tanpa token: status %d, mau 401", w.Code)
+	}
+	if w, _ := post(router, "/create", bearer(t, 1, 1, "EMPLOYEE"), "", body); w.Code != http.StatusForbidden {
+		t.Fatalf("token EMPLOYEE: status %d, mau 403", w.Code)
+	}
+
+	// tenant diambil dari token admin, field tenant di body diabaikan
+	if w, _ := post(router, "/create", bearer(t, 9, 2, "ADMIN"), "", body); w.Code != http.StatusCreated {
+		t.Fatalf("admin tenant 2: status %d, body %s", w.Code, w.Body)
+	}
+	if _, ok := repo.User("sari"); ok {
+		t.Fatal("akun dari admin tenant 2 tidak boleh masuk tenant default")
+	}
+}
+
+func TestCreateAccountIgnoresRole(t *testing.T) {
+	router, _, _ := newTestRouter()
+	body := `{"user_id":2,"username":"sari","email":"sari@mojo.id","full_name":"Sari","password":"rahasia123","role":"MANAGER"}`
+
+	w, res := post(router, "/create", bearer(t, 1, 1, "ADMIN"), "", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: status %d, body %s", w.Code, w.Body)
+	}
+	if data, _ := res.Data.(map[string]any); data["role"] != "EMPLOYEE" {
+		t.Fatalf("role = %v, mau EMPLOYEE", data["role"])
+	}
+}
+
+func TestCreateAccountIdempotencyKey(t *testing.T) {
+	router, _, _ := newTestRouter()
+	admin := bearer(t, 1, 1, "ADMIN")
+	body := `{"user_id":2,"username":"sari","email":"sari@mojo.id","full_name":"Sari","password":"rahasia123"}`
+
+	first, _ := post(router, "/create", admin, "daftar-sari", body)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("create: status %d, body %s", first.Code, first.Body)
+	}
+	// tanpa key, percobaan kedua kena 409 akun sudah ada; dengan key, replay
+	w, _ := post(router, "/create", admin, "daftar-sari", body)
+	if w.Code != http.StatusCreated || w.Body.String() != first.Body.String() {
+		t.Fatalf("replay create: status %d body %s", w.Code, w.Body)
+	}
+}
+
+func TestCreateAccountIdempotencyKeyPerTenant(t *testing.T) {
+	router, _, _ := newTestRouter()
+	body := `{"user_id":2,"username":"sari","email":"sari@mojo.id","full_name":"Sari","password":"rahasia123"}`
+
+	if w, _ := post(router, "/create", bearer(t, 1, 1, "ADMIN"), "daftar-sari", body); w.Code != http.StatusCreated {
+		t.Fatalf("create tenant 1: status %d, body %s", w.Code, w.Body)
+	}
+	// scope user:1 dan key yang sama di tenant lain bukan retry
+	w, _ := post(router, "/create", bearer(t, 1, 2, "ADMIN"), "daftar-sari", body)
+	if w.Code != http.StatusCreated || w.Header().Get(mid.ReplayedHeader) != "" {
+		t.Fatalf("create tenant 2: status %d replay %q, mau 201 tanpa replay", w.Code, w.Header().Get(mid.ReplayedHeader))
+	}
+}
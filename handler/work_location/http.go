@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	mid "mojo-autotech/middleware"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+	wlSvc "mojo-autotech/service/work_location"
+)
+
+func HttpWorkLocationHandler(router gin.IRouter, svc wlSvc.IWorkLocationService, auth gin.HandlerFunc) {
+	h := NewWorkLocationHandler(svc)
+	router.GET("/work-locations", auth, h.List)
+
+	admin := router.Group("/work-locations", auth, mid.RequireRole("ADMIN"))
+	{
+		admin.POST("", h.Create)
+		admin.PUT("/:id", h.Update)
+		admin.PUT("/:id/ip-policy", h.SetIPPolicy)
+	}
+}
+
+type WorkLocationHandler struct {
+	location wlSvc.IWorkLocationService
+}
+
+func NewWorkLocationHandler(svc wlSvc.IWorkLocationService) *WorkLocationHandler {
+	return &WorkLocationHandler{
+		location: svc,
+	}
+}
+
+func (h *WorkLocationHandler) Create(ctx *gin.Context) {
+	var param wlSvc.WorkLocationReq
+	if err := ctx.ShouldBind(&param); err != nil {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
+		return
+	}
+
+	out, err := h.location.Create(ctx.Request.Context(), param)
+	if err != nil {
+		mid.Fail(ctx, i18n.LocationCreateFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusCreated, i18n.LocationCreated, out)
+}
+
+func (h *WorkLocationHandler) Update(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Validation(i18n.LocationIDInvalid))
+		return
+	}
+	var param wlSvc.WorkLocationReq
+	if err := ctx.ShouldBind(&param); err != nil {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
+		return
+	}
+
+	out, err := h.location.Update(ctx.Request.Context(), uint(id), param)
+	if err != nil {
+		mid.Fail(ctx, i18n.LocationUpdateFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.LocationUpdated, out)
+}
+
+func (h *WorkLocationHandler) SetIPPolicy(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Validation(i18n.LocationIDInvalid))
+		return
+	}
+	var param wlSvc.IPPolicyReq
+	if err := ctx.ShouldBind(&param); err != nil {
+		mid.Fail(ctx, i18n.ReqParamInvalid, apperror.Invalid(err))
+		return
+	}
+
+	out, err := h.location.SetIPPolicy(ctx.Request.Context(), uint(id), param)
+	if err != nil {
+		mid.Fail(ctx, i18n.IPPolicySaveFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.IPPolicySaved, out)
+}
+
+func (h *WorkLocationHandler) List(ctx *gin.Context) {
+	out, err := h.location.List(ctx.Request.Context())
+	if err != nil {
+		mid.Fail(ctx, i18n.LocationListFailed, err)
+		return
+	}
+	mid.Respond(ctx, http.StatusOK, i18n.LocationList, out)
+}
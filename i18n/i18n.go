@@ -0,0 +1,70 @@
+// Package i18n berisi katalog pesan API dalam Bahasa Indonesia dan Inggris.
+// Setiap pesan punya Key yang stabil; client mencocokkan Key, bukan teks.
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type Lang string
+
+const (
+	ID Lang = "id"
+	EN Lang = "en"
+
+	// Default dipakai kalau Accept-Language kosong/tidak didukung, dan untuk log/CLI.
+	Default = ID
+)
+
+// Key adalah kode pesan yang stabil, mis. "attendance.not_checked_in".
+type Key string
+
+// T menerjemahkan key ke bahasa lang. Key yang tidak ada di katalog
+// dikembalikan apa adanya supaya tetap terlihat di response.
+func T(lang Lang, key Key, args ...any) string {
+	m, ok := catalogue[key]
+	if !ok {
+		return string(key)
+	}
+	text := m.id
+	if lang == EN {
+		text = m.en
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// Negotiate memilih bahasa dari header Accept-Language berdasarkan q-value,
+// mis. "en-US,en;q=0.9,id;q=0.8" → EN.
+func Negotiate(header string) Lang {
+	best, bestQ := Default, -1.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		lang, ok := supported(tag)
+		if ok && q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
+
+func supported(tag string) (Lang, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	switch base {
+	case "id", "in": // "in" kode lama untuk Indonesia, masih dikirim Android lama
+		return ID, true
+	case "en":
+		return EN, true
+	}
+	return "", false
+}
@@ -0,0 +1,337 @@
+package i18n
+
+// Daftar key pesan. Key tidak boleh diganti setelah dirilis karena dipakai client;
+// teks boleh diubah kapan saja.
+const (
+	// umum
+	ReqParamInvalid Key = "request.invalid"
+	ReqMalformed    Key = "request.malformed"
+	ReqValidation   Key = "request.validation_failed"
+	BodyTooLarge    Key = "request.body_too_large"
+	TooManyRequests Key = "request.too_many"
+	RateLimited     Key = "request.rate_limited"
+	IdemKeyInvalid  Key = "request.idempotency_key_invalid"
+	IdemKeyReused   Key = "request.idempotency_key_reused"
+	IdemInProgress  Key = "request.idempotency_in_progress"
+	InternalError   Key = "server.internal"
+	Unauthorized    Key = "auth.unauthorized"
+	Forbidden       Key = "auth.forbidden"
+	HealthOK        Key = "health.ok"
+	Ready           Key = "health.ready"
+	NotReady        Key = "health.not_ready"
+	VersionInfo     Key = "health.version"
+
+	// auth & user
+	MissingBearerToken  Key = "auth.missing_bearer_token"
+	InvalidToken        Key = "auth.invalid_token"
+	RoleNotAllowed      Key = "auth.role_not_allowed"
+	UserIDMissing       Key = "auth.user_id_missing"
+	UserIDInvalid       Key = "auth.user_id_invalid"
+	LoginSuccess        Key = "auth.login.ok"
+	LoginFailed         Key = "auth.login.failed"
+	BadCredentials      Key = "auth.bad_credentials"
+	AccountInactive     Key = "auth.account_inactive"
+	AccountLocked       Key = "auth.account_locked"
+	AccountCreated      Key = "auth.account.created"
+	AccountCreateFailed Key = "auth.account.create_failed"
+	AccountExists       Key = "auth.account.exists"
+	UsernameEmpty       Key = "auth.username_empty"
+	EmailEmpty          Key = "auth.email_empty"
+	EmailInvalid        Key = "auth.email_invalid"
+	PasswordEmpty       Key = "auth.password_empty"
+	PasswordTooShort    Key = "auth.password_too_short"
+	UsernameNotFound    Key = "user.username_not_found"
+	UserNotFound        Key = "user.not_found"
+
+	// tenant
+	TenantUnknown     Key = "tenant.unknown"
+	TenantExists      Key = "tenant.exists"
+	TenantCodeInvalid Key = "tenant.code_invalid"
+
+	// absensi
+	CheckInOK         Key = "attendance.check_in.ok"
+	CheckInUpdated    Key = "attendance.check_in.updated"
+	CheckInFailed     Key = "attendance.check_in.failed"
+	CheckOutOK        Key = "attendance.check_out.ok"
+	CheckOutFailed    Key = "attendance.check_out.failed"
+	TodayStatus       Key = "attendance.today"
+	TodayFailed       Key = "attendance.today.failed"
+	ActivityRequired  Key = "attendance.activity_required"
+	NotCheckedIn      Key = "attendance.not_checked_in"
+	AlreadyCheckedOut Key = "attendance.already_checked_out"
+	PeriodLocked      Key = "attendance.period_locked"
+	IPNotAllowed      Key = "attendance.ip_not_allowed"
+	LocationUnknown   Key = "attendance.location_unknown"
+	LocationRequired  Key = "attendance.location_required"
+	DateRangeInvalid  Key = "attendance.date_range_invalid"
+
+	// kiosk
+	KioskQRInvalid      Key = "kiosk.qr_invalid"
+	KioskUnknown        Key = "kiosk.unknown"
+	KioskInactive       Key = "kiosk.inactive"
+	KioskKeyMissing     Key = "kiosk.key_missing"
+	BadgeBadCredentials Key = "kiosk.bad_credentials"
+	BadgeLocked         Key = "kiosk.badge_locked"
+	KioskRegistered     Key = "kiosk.registered"
+	KioskRegisterFailed Key = "kiosk.register_failed"
+	KioskList           Key = "kiosk.list"
+	KioskListFailed     Key = "kiosk.list_failed"
+	BadgeSaved          Key = "kiosk.badge.saved"
+	BadgeSaveFailed     Key = "kiosk.badge.save_failed"
+	KioskQR             Key = "kiosk.qr"
+	KioskQRFailed       Key = "kiosk.qr_failed"
+	KioskPunchFailed    Key = "kiosk.punch_failed"
+
+	// sync offline
+	DeviceRegistered     Key = "sync.device.registered"
+	DeviceRegisterFailed Key = "sync.device.register_failed"
+	DeviceNotRegistered  Key = "sync.device.not_registered"
+	SyncDone             Key = "sync.done"
+	SyncFailed           Key = "sync.failed"
+	FlaggedList          Key = "sync.flagged.list"
+	FlaggedListFailed    Key = "sync.flagged.list_failed"
+	PunchReviewed        Key = "sync.punch.reviewed"
+	PunchReviewFailed    Key = "sync.punch.review_failed"
+	PunchNotFound        Key = "sync.punch.not_found"
+	PunchNotPending      Key = "sync.punch.not_pending"
+	PunchIDInvalid       Key = "sync.punch.id_invalid"
+
+	// lokasi kerja
+	LocationCreated      Key = "location.created"
+	LocationCreateFailed Key = "location.create_failed"
+	LocationUpdated      Key = "location.updated"
+	LocationUpdateFailed Key = "location.update_failed"
+	LocationList         Key = "location.list"
+	LocationListFailed   Key = "location.list_failed"
+	IPPolicySaved        Key = "location.ip_policy.saved"
+	IPPolicySaveFailed   Key = "location.ip_policy.save_failed"
+	LocationNotFound     Key = "location.not_found"
+	LocationIDInvalid    Key = "location.id_invalid"
+	NetworksRequired     Key = "location.networks_required"
+
+	// payroll
+	PeriodCreated           Key = "payroll.period.created"
+	PeriodCreateFailed      Key = "payroll.period.create_failed"
+	PeriodList              Key = "payroll.period.list"
+	PeriodListFailed        Key = "payroll.period.list_failed"
+	PayrollSummary          Key = "payroll.summary"
+	PayrollSummaryFailed    Key = "payroll.summary_failed"
+	PayrollExportFailed     Key = "payroll.export_failed"
+	PeriodCloseFailed       Key = "payroll.period.close_failed"
+	PeriodStartInvalid      Key = "payroll.period.start_invalid"
+	PeriodEndInvalid        Key = "payroll.period.end_invalid"
+	PeriodRangeInvalid      Key = "payroll.period.range_invalid"
+	PeriodOverlap           Key = "payroll.period.overlap"
+	PeriodNotFound          Key = "payroll.period.not_found"
+	PeriodIDInvalid         Key = "payroll.period.id_invalid"
+	ExportFormatUnsupported Key = "payroll.export.format_unsupported"
+
+	// anomali
+	AnomalyList         Key = "anomaly.list"
+	AnomalyListFailed   Key = "anomaly.list_failed"
+	AnomalyReviewed     Key = "anomaly.reviewed"
+	AnomalyReviewFailed Key = "anomaly.review_failed"
+	AnomalyNotFound     Key = "anomaly.not_found"
+	AnomalyIDInvalid    Key = "anomaly.id_invalid"
+
+	// struktur organisasi
+	DepartmentCreated       Key = "org.department.created"
+	DepartmentCreateFailed  Key = "org.department.create_failed"
+	DepartmentUpdated       Key = "org.department.updated"
+	DepartmentUpdateFailed  Key = "org.department.update_failed"
+	DepartmentList          Key = "org.department.list"
+	DepartmentListFailed    Key = "org.department.list_failed"
+	DepartmentNotFound      Key = "org.department.not_found"
+	DepartmentExists        Key = "org.department.exists"
+	DepartmentIDInvalid     Key = "org.department.id_invalid"
+	TeamCreated             Key = "org.team.created"
+	TeamCreateFailed        Key = "org.team.create_failed"
+	TeamUpdated             Key = "org.team.updated"
+	TeamUpdateFailed        Key = "org.team.update_failed"
+	TeamList                Key = "org.team.list"
+	TeamListFailed          Key = "org.team.list_failed"
+	TeamNotFound            Key = "org.team.not_found"
+	TeamExists              Key = "org.team.exists"
+	TeamIDInvalid           Key = "org.team.id_invalid"
+	MemberList              Key = "org.member.list"
+	MemberListFailed        Key = "org.member.list_failed"
+	ManagerNotFound         Key = "org.manager.not_found"
+	ManagerCycle            Key = "org.manager.cycle"
+	AssignmentSaved         Key = "org.assignment.saved"
+	AssignmentFailed        Key = "org.assignment.failed"
+	AssignmentHistory       Key = "org.assignment.history"
+	AssignmentHistoryFailed Key = "org.assignment.history_failed"
+	RoleUpdated             Key = "org.role.updated"
+	RoleUpdateFailed        Key = "org.role.update_failed"
+	ReportsList             Key = "org.reports.list"
+	ReportsListFailed       Key = "org.reports.list_failed"
+	OutsideReports          Key = "org.outside_reports"
+)
+
+type message struct{ id, en string }
+
+var catalogue = map[Key]message{
+	// umum
+	ReqParamInvalid: {"Parameter request tidak valid", "Request parameter is invalid"},
+	ReqMalformed:    {"Body request tidak bisa dibaca", "Request body could not be parsed"},
+	ReqValidation:   {"Ada field yang tidak valid", "Some fields are invalid"},
+	BodyTooLarge:    {"Body melebihi batas ukuran", "Request body is too large"},
+	TooManyRequests: {"Terlalu banyak permintaan", "Too many requests"},
+	RateLimited:     {"Terlalu banyak permintaan, coba lagi dalam %d detik", "Too many requests, try again in %d seconds"},
+	IdemKeyInvalid:  {"Header Idempotency-Key harus 1-255 karakter ASCII yang terlihat", "Idempotency-Key header must be 1-255 visible ASCII characters"},
+	IdemKeyReused:   {"Idempotency-Key sudah dipakai untuk request dengan isi berbeda", "Idempotency-Key was already used for a different request"},
+	IdemInProgress:  {"Request dengan Idempotency-Key yang sama masih diproses", "A request with the same Idempotency-Key is still in progress"},
+	InternalError:   {"Terjadi kesalahan pada server", "Internal server error"},
+	Unauthorized:    {"Tidak terautentikasi", "Unauthorized"},
+	Forbidden:       {"Akses ditolak", "Forbidden"},
+	HealthOK:        {"ok", "ok"},
+	Ready:           {"siap", "ready"},
+	NotReady:        {"belum siap", "not ready"},
+	VersionInfo:     {"versi", "version"},
+
+	// auth & user
+	MissingBearerToken:  {"Bearer token tidak ada", "Missing bearer token"},
+	InvalidToken:        {"Token tidak valid atau kedaluwarsa", "Token is invalid or expired"},
+	RoleNotAllowed:      {"Role tidak diizinkan", "Role is not allowed"},
+	UserIDMissing:       {"user_id tidak ditemukan di context", "user_id is missing from context"},
+	UserIDInvalid:       {"Tipe/isi user_id tidak valid", "user_id has an invalid type or value"},
+	LoginSuccess:        {"Login sukses", "Login successful"},
+	LoginFailed:         {"Login gagal", "Login failed"},
+	BadCredentials:      {"Username atau password salah", "Wrong username or password"},
+	AccountInactive:     {"Akun tidak aktif", "Account is inactive"},
+	AccountLocked:       {"Akun dikunci karena terlalu banyak login gagal, coba lagi dalam %d menit", "Account is locked after too many failed logins, try again in %d minutes"},
+	AccountCreated:      {"Akun berhasil dibuat", "Account created"},
+	AccountCreateFailed: {"Gagal membuat akun", "Failed to create account"},
+	AccountExists:       {"Username atau email sudah terdaftar", "Username or email is already registered"},
+	UsernameEmpty:       {"Username wajib diisi", "Username is required"},
+	EmailEmpty:          {"Email wajib diisi", "Email is required"},
+	EmailInvalid:        {"Format email tidak valid", "Email is not valid"},
+	PasswordEmpty:       {"Password wajib diisi", "Password is required"},
+	PasswordTooShort:    {"Password minimal 8 karakter", "Password must be at least 8 characters"},
+	UsernameNotFound:    {"Username tidak ditemukan", "Username not found"},
+	UserNotFound:        {"User tidak ditemukan", "User not found"},
+
+	// tenant
+	TenantUnknown:     {"Perusahaan tidak dikenal atau tidak aktif", "Unknown or inactive company"},
+	TenantExists:      {"Kode perusahaan sudah dipakai", "Company code is already taken"},
+	TenantCodeInvalid: {"Kode perusahaan hanya boleh huruf kecil, angka dan tanda -", "Company code may only contain lowercase letters, digits and -"},
+
+	// absensi
+	CheckInOK:         {"Check-in berhasil", "Checked in"},
+	CheckInUpdated:    {"Check-in diperbarui", "Check-in updated"},
+	CheckInFailed:     {"Gagal check-in", "Check-in failed"},
+	CheckOutOK:        {"Check-out berhasil", "Checked out"},
+	CheckOutFailed:    {"Gagal check-out", "Check-out failed"},
+	TodayStatus:       {"Status hari ini", "Today's status"},
+	TodayFailed:       {"Gagal memuat status hari ini", "Failed to load today's status"},
+	ActivityRequired:  {"Activity wajib diisi", "Activity is required"},
+	NotCheckedIn:      {"Belum check-in hari ini", "Not checked in today"},
+	AlreadyCheckedOut: {"Sudah check-out", "Already checked out"},
+	PeriodLocked:      {"Periode payroll sudah dikunci", "Payroll period is locked"},
+	IPNotAllowed:      {"IP di luar jaringan lokasi kerja", "IP is outside the work location network"},
+	LocationUnknown:   {"Lokasi kerja tidak ditemukan", "Work location not found"},
+	LocationRequired:  {"Lokasi kerja wajib diisi", "Work location is required"},
+	DateRangeInvalid:  {"Tanggal akhir sebelum tanggal awal", "End date is before start date"},
+
+	// kiosk
+	KioskQRInvalid:      {"QR kiosk tidak valid atau kedaluwarsa", "Kiosk QR is invalid or expired"},
+	KioskUnknown:        {"Kiosk tidak terdaftar", "Kiosk is not registered"},
+	KioskInactive:       {"Kiosk tidak aktif", "Kiosk is inactive"},
+	KioskKeyMissing:     {"Kiosk key tidak ada", "Kiosk key is missing"},
+	BadgeBadCredentials: {"Badge atau PIN salah", "Wrong badge or PIN"},
+	BadgeLocked:         {"Badge dikunci karena terlalu banyak PIN salah, coba lagi dalam %d menit", "Badge is locked after too many wrong PINs, try again in %d minutes"},
+	KioskRegistered:     {"Kiosk terdaftar", "Kiosk registered"},
+	KioskRegisterFailed: {"Gagal mendaftarkan kiosk", "Failed to register kiosk"},
+	KioskList:           {"Daftar kiosk", "Kiosk list"},
+	KioskListFailed:     {"Gagal memuat kiosk", "Failed to load kiosks"},
+	BadgeSaved:          {"Badge disimpan", "Badge saved"},
+	BadgeSaveFailed:     {"Gagal menyimpan badge", "Failed to save badge"},
+	KioskQR:             {"QR kiosk", "Kiosk QR"},
+	KioskQRFailed:       {"Gagal membuat QR", "Failed to create QR"},
+	KioskPunchFailed:    {"Gagal mencatat absensi", "Failed to record attendance"},
+
+	// sync offline
+	DeviceRegistered:     {"Perangkat terdaftar", "Device registered"},
+	DeviceRegisterFailed: {"Gagal mendaftarkan perangkat", "Failed to register device"},
+	DeviceNotRegistered:  {"Device belum terdaftar", "Device is not registered"},
+	SyncDone:             {"Sinkronisasi selesai", "Sync completed"},
+	SyncFailed:           {"Gagal sinkronisasi absensi", "Attendance sync failed"},
+	FlaggedList:          {"Punch menunggu review", "Punches awaiting review"},
+	FlaggedListFailed:    {"Gagal memuat punch yang ditandai", "Failed to load flagged punches"},
+	PunchReviewed:        {"Punch direview", "Punch reviewed"},
+	PunchReviewFailed:    {"Gagal review punch", "Failed to review punch"},
+	PunchNotFound:        {"Punch tidak ditemukan", "Punch not found"},
+	PunchNotPending:      {"Punch tidak menunggu review", "Punch is not awaiting review"},
+	PunchIDInvalid:       {"ID punch tidak valid", "Invalid punch id"},
+
+	// lokasi kerja
+	LocationCreated:      {"Lokasi kerja dibuat", "Work location created"},
+	LocationCreateFailed: {"Gagal membuat lokasi kerja", "Failed to create work location"},
+	LocationUpdated:      {"Lokasi kerja diperbarui", "Work location updated"},
+	LocationUpdateFailed: {"Gagal memperbarui lokasi kerja", "Failed to update work location"},
+	LocationList:         {"Daftar lokasi kerja", "Work location list"},
+	LocationListFailed:   {"Gagal memuat lokasi kerja", "Failed to load work locations"},
+	IPPolicySaved:        {"IP policy disimpan", "IP policy saved"},
+	IPPolicySaveFailed:   {"Gagal menyimpan IP policy", "Failed to save IP policy"},
+	LocationNotFound:     {"Lokasi kerja tidak ditemukan", "Work location not found"},
+	LocationIDInvalid:    {"ID lokasi tidak valid", "Invalid location id"},
+	NetworksRequired:     {"Networks wajib diisi kalau ip_policy bukan OFF", "Networks are required unless ip_policy is OFF"},
+
+	// payroll
+	PeriodCreated:           {"Periode payroll dibuat", "Payroll period created"},
+	PeriodCreateFailed:      {"Gagal membuat periode payroll", "Failed to create payroll period"},
+	PeriodList:              {"Daftar periode payroll", "Payroll period list"},
+	PeriodListFailed:        {"Gagal memuat periode payroll", "Failed to load payroll periods"},
+	PayrollSummary:          {"Rekap payroll", "Payroll summary"},
+	PayrollSummaryFailed:    {"Gagal memuat rekap payroll", "Failed to load payroll summary"},
+	PayrollExportFailed:     {"Gagal export payroll", "Payroll export failed"},
+	PeriodCloseFailed:       {"Gagal menutup periode payroll", "Failed to close payroll period"},
+	PeriodStartInvalid:      {"period_start harus berformat YYYY-MM-DD", "period_start must be formatted as YYYY-MM-DD"},
+	PeriodEndInvalid:        {"period_end harus berformat YYYY-MM-DD", "period_end must be formatted as YYYY-MM-DD"},
+	PeriodRangeInvalid:      {"period_end sebelum period_start", "period_end is before period_start"},
+	PeriodOverlap:           {"Periode bertumpuk dengan periode lain", "Period overlaps another period"},
+	PeriodNotFound:          {"Periode tidak ditemukan", "Period not found"},
+	PeriodIDInvalid:         {"ID periode tidak valid", "Invalid period id"},
+	ExportFormatUnsupported: {"Format export %q tidak didukung", "Export format %q is not supported"},
+
+	// anomali
+	AnomalyList:         {"Daftar anomali", "Anomaly list"},
+	AnomalyListFailed:   {"Gagal memuat anomali", "Failed to load anomalies"},
+	AnomalyReviewed:     {"Anomali direview", "Anomaly reviewed"},
+	AnomalyReviewFailed: {"Gagal review anomali", "Failed to review anomaly"},
+	AnomalyNotFound:     {"Anomali tidak ditemukan atau sudah direview", "Anomaly not found or already reviewed"},
+	AnomalyIDInvalid:    {"ID anomali tidak valid", "Invalid anomaly id"},
+
+	// struktur organisasi
+	DepartmentCreated:       {"Departemen dibuat", "Department created"},
+	DepartmentCreateFailed:  {"Gagal membuat departemen", "Failed to create department"},
+	DepartmentUpdated:       {"Departemen diperbarui", "Department updated"},
+	DepartmentUpdateFailed:  {"Gagal memperbarui departemen", "Failed to update department"},
+	DepartmentList:          {"Daftar departemen", "Department list"},
+	DepartmentListFailed:    {"Gagal memuat departemen", "Failed to load departments"},
+	DepartmentNotFound:      {"Departemen tidak ditemukan", "Department not found"},
+	DepartmentExists:        {"Nama departemen sudah dipakai", "Department name is already in use"},
+	DepartmentIDInvalid:     {"ID departemen tidak valid", "Invalid department id"},
+	TeamCreated:             {"Tim dibuat", "Team created"},
+	TeamCreateFailed:        {"Gagal membuat tim", "Failed to create team"},
+	TeamUpdated:             {"Tim diperbarui", "Team updated"},
+	TeamUpdateFailed:        {"Gagal memperbarui tim", "Failed to update team"},
+	TeamList:                {"Daftar tim", "Team list"},
+	TeamListFailed:          {"Gagal memuat tim", "Failed to load teams"},
+	TeamNotFound:            {"Tim tidak ditemukan", "Team not found"},
+	TeamExists:              {"Nama tim sudah dipakai di departemen ini", "Team name is already in use in this department"},
+	TeamIDInvalid:           {"ID tim tidak valid", "Invalid team id"},
+	MemberList:              {"Daftar anggota tim", "Team member list"},
+	MemberListFailed:        {"Gagal memuat anggota tim", "Failed to load team members"},
+	ManagerNotFound:         {"Atasan tidak ditemukan", "Manager not found"},
+	ManagerCycle:            {"Atasan tidak boleh diri sendiri atau bawahannya", "Manager cannot be the user or one of their reports"},
+	AssignmentSaved:         {"Penempatan disimpan", "Assignment saved"},
+	AssignmentFailed:        {"Gagal menyimpan penempatan", "Failed to save assignment"},
+	AssignmentHistory:       {"Riwayat penempatan", "Assignment history"},
+	AssignmentHistoryFailed: {"Gagal memuat riwayat penempatan", "Failed to load assignment history"},
+	RoleUpdated:             {"Role diperbarui", "Role updated"},
+	RoleUpdateFailed:        {"Gagal memperbarui role", "Failed to update role"},
+	ReportsList:             {"Daftar bawahan", "Reports list"},
+	ReportsListFailed:       {"Gagal memuat bawahan", "Failed to load reports"},
+	OutsideReports:          {"Data ini milik karyawan di luar tim Anda", "This record belongs to an employee outside your reporting line"},
+}
@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	enLocale "github.com/go-playground/locales/en"
+	idLocale "github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTrans "github.com/go-playground/validator/v10/translations/en"
+	idTrans "github.com/go-playground/validator/v10/translations/id"
+)
+
+var translators = map[Lang]ut.Translator{}
+
+// RegisterValidator memasang terjemahan pesan validator ke engine binding gin
+// dan memakai nama field dari tag json (bukan nama field Go). Panggil sekali saat start.
+func RegisterValidator() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("i18n: validator binding bukan go-playground/validator")
+	}
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return f.Name
+	})
+
+	uni := ut.New(enLocale.New(), enLocale.New(), idLocale.New())
+	en, _ := uni.GetTranslator("en")
+	id, _ := uni.GetTranslator("id")
+	if err := enTrans.RegisterDefaultTranslations(v, en); err != nil {
+		return err
+	}
+	if err := idTrans.RegisterDefaultTranslations(v, id); err != nil {
+		return err
+	}
+	translators[EN], translators[ID] = en, id
+	return nil
+}
+
+// ValidationFields menerjemahkan error validator menjadi map field → pesan.
+// ok=false kalau err bukan error validator.
+func ValidationFields(lang Lang, err error) (fields map[string]string, ok bool) {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil, false
+	}
+	tr := translators[lang]
+	fields = make(map[string]string, len(verrs))
+	for _, fe := range verrs {
+		if tr != nil {
+			fields[fe.Field()] = fe.Translate(tr)
+		} else {
+			fields[fe.Field()] = fe.Error()
+		}
+	}
+	return fields, true
+}
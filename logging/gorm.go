@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// GormLogger meneruskan log GORM ke slog. Query biasa di level debug, query
+// lebih lama dari slow di level warn, query gagal di level error. Nilai
+// parameter tidak pernah dicetak (lihat ParamsFilter), jadi hash password,
+// secret perangkat, email dsb. tidak bocor ke log.
+type GormLogger struct {
+	log   *slog.Logger
+	slow  time.Duration
+	level gormlogger.LogLevel
+}
+
+func NewGormLogger(l *slog.Logger, slow time.Duration) *GormLogger {
+	return &GormLogger{log: l, slow: slow, level: gormlogger.Info}
+}
+
+func (g *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	c := *g
+	c.level = level
+	return &c
+}
+
+func (g *GormLogger) Info(ctx context.Context, msg string, args ...any) {
+	if g.level >= gormlogger.Info {
+		g.log.InfoContext(ctx, fmt.Sprintf(msg, args...), "component", "gorm")
+	}
+}
+
+func (g *GormLogger) Warn(ctx context.Context, msg string, args ...any) {
+	if g.level >= gormlogger.Warn {
+		g.log.WarnContext(ctx, fmt.Sprintf(msg, args...), "component", "gorm")
+	}
+}
+
+func (g *GormLogger) Error(ctx context.Context, msg string, args ...any) {
+	if g.level >= gormlogger.Error {
+		g.log.ErrorContext(ctx, fmt.Sprintf(msg, args...), "component", "gorm")
+	}
+}
+
+func (g *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if g.level <= gormlogger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && g.level >= gormlogger.Error:
+		sql, rows := fc()
+		g.log.ErrorContext(ctx, "query gagal", "component", "gorm", "sql", sql, "rows", rows,
+			"duration_ms", elapsed.Milliseconds(), "err", err)
+	case g.slow > 0 && elapsed > g.slow && g.level >= gormlogger.Warn:
+		sql, rows := fc()
+		g.log.WarnContext(ctx, "query lambat", "component", "gorm", "sql", sql, "rows", rows,
+			"duration_ms", elapsed.Milliseconds())
+	case g.level >= gormlogger.Info && g.log.Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		g.log.DebugContext(ctx, "query", "component", "gorm", "sql", sql, "rows", rows,
+			"duration_ms", elapsed.Milliseconds())
+	}
+}
+
+// ParamsFilter dipanggil GORM sebelum SQL dirangkai untuk log; membuang nilai
+// parameter sehingga yang tercatat hanya placeholder ($1, $2, ...).
+func (g *GormLogger) ParamsFilter(_ context.Context, sql string, _ ...any) (string, []any) {
+	return sql, nil
+}
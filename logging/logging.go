@@ -0,0 +1,136 @@
+// Package logging menyiapkan logger slog aplikasi: output JSON (atau text
+// saat development), atribut request_id/user_id/tenant_id/route/trace_id otomatis dari context,
+// dan redaksi nilai sensitif seperti password dan token.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"mojo-autotech/tenant"
+)
+
+// Redacted menggantikan nilai atribut yang dianggap sensitif.
+const Redacted = "[REDACTED]"
+
+// New membuat logger dengan level "debug"/"info"/"warn"/"error" dan format
+// "json"/"text". Semua log yang memakai *Context(ctx, ...) otomatis membawa
+// field request dari WithRequest.
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("logging: level %q: %w", level, err)
+	}
+	opts := &slog.HandlerOptions{Level: lvl, ReplaceAttr: redact}
+
+	var h slog.Handler
+	switch format {
+	case "json":
+		h = slog.NewJSONHandler(w, opts)
+	case "text":
+		h = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("logging: format %q tidak dikenal", format)
+	}
+	return slog.New(contextHandler{h}), nil
+}
+
+// redact mengganti nilai atribut yang key-nya menunjukkan data rahasia.
+// Body request tidak pernah di-log utuh; ini jaring pengaman kalau ada
+// pemanggil yang mencatat field satu per satu.
+func redact(_ []string, a slog.Attr) slog.Attr {
+	if Sensitive(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	return a
+}
+
+var sensitiveKeys = map[string]bool{
+	"pin":           true,
+	"authorization": true,
+	"cookie":        true,
+	"set-cookie":    true,
+	"x-kiosk-key":   true,
+	"device_key":    true,
+	"signature":     true,
+	"kiosk_qr":      true,
+}
+
+// Sensitive true kalau nilai dengan key ini tidak boleh masuk log.
+func Sensitive(key string) bool {
+	k := strings.ToLower(key)
+	return sensitiveKeys[k] ||
+		strings.Contains(k, "password") ||
+		strings.HasSuffix(k, "token") ||
+		strings.HasSuffix(k, "secret")
+}
+
+type ctxKey struct{}
+
+// fields disimpan sebagai pointer supaya middleware yang jalan belakangan
+// (mis. Auth mengisi user_id) terlihat oleh log yang memakai context yang sama.
+type fields struct {
+	requestID string
+	route     string
+	userID    uint
+}
+
+// WithRequest menempelkan request ID dan route (pola gin, mis.
+// "/api/v1/attendance/check-in") ke context.
+func WithRequest(ctx context.Context, requestID, route string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, &fields{requestID: requestID, route: route})
+}
+
+// SetUserID mencatat user yang terautentikasi untuk request di ctx.
+func SetUserID(ctx context.Context, userID uint) {
+	if f, ok := ctx.Value(ctxKey{}).(*fields); ok {
+		f.userID = userID
+	}
+}
+
+// RequestID mengembalikan request ID di ctx, kosong kalau tidak ada.
+func RequestID(ctx context.Context) string {
+	if f, ok := ctx.Value(ctxKey{}).(*fields); ok {
+		return f.requestID
+	}
+	return ""
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if f, ok := ctx.Value(ctxKey{}).(*fields); ok {
+		if f.requestID != "" {
+			r.AddAttrs(slog.String("request_id", f.requestID))
+		}
+		if f.route != "" {
+			r.AddAttrs(slog.String("route", f.route))
+		}
+		if f.userID != 0 {
+			r.AddAttrs(slog.Uint64("user_id", uint64(f.userID)))
+		}
+	}
+	if tid, ok := tenant.FromContext(ctx); ok {
+		r.AddAttrs(slog.Uint64("tenant_id", uint64(tid)))
+	}
+	// korelasi log ↔ trace; kosong kalau tracing mati atau tidak ada span aktif
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
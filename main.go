@@ -1,43 +1,51 @@
 package main
 
 import (
-	"log"
-	"time"
-
-	"github.com/gin-contrib/cors"
-	"github.com/gin-gonic/gin"
-
-	"mojo-autotech/config"
-	a "mojo-autotech/handler/attedance"
-	h "mojo-autotech/handler/user_authentication"
-
-	"mojo-autotech/model/attedance"
-	"mojo-autotech/model/user_authentication"
+	"fmt"
+	"os"
 )
 
-func main() {
-	cfg := config.NewConfig()
-	router := gin.Default()
+const usage = `usage: mojo-autotech [command] [flags]
 
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: false,
-		MaxAge:           12 * time.Hour,
-	}))
+commands:
+  serve                                   jalankan HTTP server (default)
+  migrate up|down|status|create           kelola migrasi skema
+  tenant create|list                      kelola perusahaan (tenant)
+  user create-admin                       buat akun ADMIN
+  user reset-password                     ganti password user
+  user deactivate                         nonaktifkan user
+  attendance recompute --from --to        hitung ulang total menit kerja
+  seed                                    isi data awal untuk development
 
-	db := config.NewDB()
-	_ = db.AutoMigrate(&user_authentication.User{})
-	_ = db.AutoMigrate(&attedance.Attendance{})
+jalankan "mojo-autotech <command> -h" untuk flag tiap command.
 
-	h.HttpHandler(router)
-	a.HttpAttendanceHandler(router)
+konfigurasi: default → file YAML ($CONFIG_FILE atau serve --config) → env/.env → flag.
+lihat config.example.yaml untuk semua key.`
+
+func main() {
+	args := os.Args[1:]
+	if len(args) == 0 {
+		runServe(nil)
+		return
+	}
 
-	addr := cfg.Srv.Host + ":" + cfg.Srv.Port
-	log.Println("Server running at http://" + addr)
-	if err := router.Run(addr); err != nil {
-		log.Fatalf("Failed to run server: %v", err)
+	switch args[0] {
+	case "serve":
+		runServe(args[1:])
+	case "migrate":
+		runMigrate(args[1:])
+	case "tenant":
+		runTenant(args[1:])
+	case "user":
+		runUser(args[1:])
+	case "attendance":
+		runAttendance(args[1:])
+	case "seed":
+		runSeed(args[1:])
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
 	}
 }
@@ -0,0 +1,110 @@
+// Package metrics mendefinisikan metrik Prometheus aplikasi. Semua metrik
+// didaftarkan ke Registry sendiri (bukan default global) supaya /metrics hanya
+// berisi yang memang kita ekspor.
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "mojo"
+
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	// HTTPDuration: latensi per route (pola gin, bukan path mentah) dan status.
+	HTTPDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Durasi request HTTP per method, route dan status.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"method", "route", "status"})
+
+	// CheckIns: result "created" (check-in pertama hari itu) atau "updated".
+	CheckIns = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "attendance",
+		Name:      "check_ins_total",
+		Help:      "Jumlah check-in berhasil.",
+	}, []string{"result"})
+
+	CheckOuts = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "attendance",
+		Name:      "check_outs_total",
+		Help:      "Jumlah check-out berhasil.",
+	})
+
+	LateArrivals = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "attendance",
+		Name:      "late_arrivals_total",
+		Help:      "Jumlah check-in pertama yang berstatus LATE.",
+	})
+
+	// LoginFailures: reason "bad_credentials", "locked" atau "inactive".
+	LoginFailures = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "login_failures_total",
+		Help:      "Jumlah login yang ditolak.",
+	}, []string{"reason"})
+
+	Lockouts = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "lockouts_total",
+		Help:      "Jumlah akun yang dikunci karena password salah berulang kali.",
+	})
+
+	// RateLimited: policy "api", "login" atau "check_in".
+	RateLimited = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "rate_limited_total",
+		Help:      "Jumlah request yang ditolak rate limiter.",
+	}, []string{"policy"})
+
+	// Idempotency: result "replayed" (response lama dikirim ulang), "mismatch"
+	// (key dipakai untuk body lain) atau "in_progress".
+	Idempotency = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "idempotency_total",
+		Help:      "Jumlah request ber-Idempotency-Key yang tidak diteruskan ke handler.",
+	}, []string{"result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	// label yang diketahui langsung muncul dengan nilai 0, supaya rate() tidak
+	// kosong sebelum kejadian pertama
+	for _, r := range []string{"created", "updated"} {
+		CheckIns.WithLabelValues(r)
+	}
+	for _, r := range []string{"bad_credentials", "locked", "inactive"} {
+		LoginFailures.WithLabelValues(r)
+	}
+	for _, p := range []string{"api", "login", "check_in"} {
+		RateLimited.WithLabelValues(p)
+	}
+	for _, r := range []string{"replayed", "mismatch", "in_progress"} {
+		Idempotency.WithLabelValues(r)
+	}
+}
+
+// RegisterDB mengekspor statistik pool database/sql (open, in use, idle,
+// wait count/duration, ...). Panggil sekali setelah pool dibuat.
+func RegisterDB(db *sql.DB, name string) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
@@ -1,72 +1,107 @@
 package middleware
 
 import (
-	"errors"
-	"net/http"
-	"os"
+	"context"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
-	"mojo-autotech/model"
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+	"mojo-autotech/logging"
+	org "mojo-autotech/model/org_structure"
+	"mojo-autotech/tenant"
+	"mojo-autotech/tracing"
 	"mojo-autotech/utils"
 )
 
-func Auth() gin.HandlerFunc {
+var tracer = tracing.Tracer("middleware")
+
+// Auth memverifikasi bearer token; jwt dibuat sekali dari config saat start.
+func Auth(j *utils.JWT) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		auth := c.GetHeader("Authorization")
 		if !strings.HasPrefix(auth, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, model.Response{
-				Code: http.StatusUnauthorized,
-				Msg:  "Unauthorized",
-				Err:  "missing bearer token",
-			})
-			c.Abort()
+			Fail(c, i18n.Unauthorized, apperror.Unauthorized(i18n.MissingBearerToken))
 			return
 		}
 		tokenStr := strings.TrimPrefix(auth, "Bearer ")
 
-		secret := os.Getenv("AUTH_JWT_SECRET")
-		if secret == "" {
-			c.JSON(http.StatusUnauthorized, model.Response{
-				Code: http.StatusUnauthorized,
-				Msg:  "Unauthorized",
-				Err:  "AUTH_JWT_SECRET not set",
-			})
-			c.Abort()
+		_, span := tracer.Start(c.Request.Context(), "Auth.ParseAccessToken")
+		claims, err := j.ParseAccessToken(tokenStr)
+		span.End()
+		if err != nil {
+			Fail(c, i18n.Unauthorized, apperror.Wrap(apperror.CodeUnauthorized, i18n.InvalidToken, err))
 			return
 		}
 
-		token, err := jwt.ParseWithClaims(tokenStr, &utils.AccessClaims{}, func(t *jwt.Token) (interface{}, error) {
-			if t.Method.Alg() != jwt.SigningMethodHS256.Alg() {
-				return nil, errors.New("unexpected signing method")
+		c.Set("user_id", claims.UID)
+		c.Set("role", claims.Role)
+		// repository membaca tenant dari context request
+		c.Request = c.Request.WithContext(tenant.WithID(c.Request.Context(), claims.TenantID))
+		logging.SetUserID(c.Request.Context(), claims.UID)
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(
+			attribute.Int64("user.id", int64(claims.UID)), attribute.String("user.role", claims.Role),
+			attribute.Int64("tenant.id", int64(claims.TenantID)),
+		)
+		c.Next()
+	}
+}
+
+// RequireRole dipasang setelah Auth(); menolak request kalau role di token tidak termasuk roles.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := c.Get("role")
+		for _, r := range roles {
+			if role == r {
+				c.Next()
+				return
 			}
-			return []byte(secret), nil
-		})
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, model.Response{
-				Code: http.StatusUnauthorized,
-				Msg:  "Unauthorized",
-				Err:  "invalid token",
-			})
-			c.Abort()
-			return
 		}
+		Fail(c, i18n.Forbidden, apperror.Forbidden(i18n.RoleNotAllowed))
+	}
+}
 
-		claims, ok := token.Claims.(*utils.AccessClaims)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, model.Response{
-				Code: http.StatusUnauthorized,
-				Msg:  "Unauthorized",
-				Err:  "invalid claims",
-			})
-			c.Abort()
+// ScopeFunc menghitung scope data untuk user dan role-nya, lihat
+// org_structure.IOrgService.Scope.
+type ScopeFunc func(ctx context.Context, userID uint, role string) (org.Scope, error)
+
+// ReportScope dipasang setelah Auth() dan RequireRole() pada endpoint yang
+// dipakai ADMIN dan MANAGER bersama; handler membacanya dengan CurrentScope.
+func ReportScope(fn ScopeFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uid, _ := CurrentUserID(c)
+		role, _ := c.Get("role")
+		roleStr, _ := role.(string)
+		scope, err := fn(c.Request.Context(), uid, roleStr)
+		if err != nil {
+			Fail(c, i18n.InternalError, err)
 			return
 		}
-
-		c.Set("user_id", claims.UID)
-		c.Set("role", claims.Role)
+		c.Set("scope", scope)
 		c.Next()
 	}
 }
+
+// CurrentScope mengambil scope yang di-set oleh ReportScope(). Tanpa
+// ReportScope hasilnya scope kosong, jadi tidak ada data yang terlihat.
+func CurrentScope(c *gin.Context) org.Scope {
+	v, _ := c.Get("scope")
+	scope, _ := v.(org.Scope)
+	return scope
+}
+
+// CurrentUserID mengambil user_id yang di-set oleh Auth().
+func CurrentUserID(c *gin.Context) (uint, bool) {
+	v, ok := c.Get("user_id")
+	if !ok {
+		return 0, false
+	}
+	uid, ok := v.(uint)
+	if !ok || uid == 0 {
+		return 0, false
+	}
+	return uid, true
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+)
+
+var ErrBodyTooLarge = apperror.New(apperror.CodeTooLarge, i18n.BodyTooLarge)
+
+// BodyLimit menolak body lebih besar dari max byte. Content-Length yang sudah
+// ketahuan terlalu besar langsung 413; body chunked dipotong MaxBytesReader
+// sehingga binding gagal begitu batas terlewati.
+func BodyLimit(max int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > max {
+			Fail(c, i18n.BodyTooLarge, ErrBodyTooLarge)
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, max)
+		c.Next()
+	}
+}
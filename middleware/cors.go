@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/config"
+)
+
+// exposeHeaders: header response yang boleh dibaca JavaScript web admin. Ini
+// bagian kontrak API (bukan config) karena server sendiri yang mengirimnya.
+var exposeHeaders = []string{"Content-Length", "Content-Language", "Deprecation", "Link", "Retry-After", RequestIDHeader, ReplayedHeader}
+
+// CORS memasang kebijakan CORS dari config. Origin boleh berupa wildcard
+// subdomain seperti "https://*.mojo.id".
+func CORS(cfg config.CORS) gin.HandlerFunc {
+	return cors.New(cors.Config{
+		AllowOrigins:     cfg.AllowOrigins,
+		AllowWildcard:    true,
+		AllowMethods:     cfg.AllowMethods,
+		AllowHeaders:     cfg.AllowHeaders,
+		ExposeHeaders:    exposeHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	})
+}
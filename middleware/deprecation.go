@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated dipasang di route lama yang masih dilayani demi app versi lama.
+// Response tetap sama, hanya ditambah header Deprecation (RFC 9745, tanggal
+// sejak kapan deprecated) dan Link ke path yang sama di bawah successor,
+// mis. /login → </api/v1/login>; rel="successor-version".
+func Deprecated(since time.Time, successor string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(since.Unix(), 10)
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Link", "<"+successor+c.Request.URL.Path+`>; rel="successor-version"`)
+		c.Next()
+	}
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+	"mojo-autotech/model"
+)
+
+// Errors merender error yang dicatat lewat Fail (atau c.Error) menjadi
+// model.Response dalam bahasa request. Status HTTP ditentukan dari
+// apperror.Code; error tanpa Code dianggap INTERNAL, detailnya hanya masuk log.
+func Errors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		flush(c)
+	}
+}
+
+// flush merender error terakhir kalau belum ada response yang ditulis.
+func flush(c *gin.Context) {
+	if len(c.Errors) == 0 || c.Writer.Written() {
+		return
+	}
+	last := c.Errors.Last()
+	msgKey, _ := last.Meta.(i18n.Key)
+	render(c, last.Err, msgKey)
+}
+
+// render menulis err sebagai model.Response; msgKey kosong diganti pesan
+// default untuk Code-nya.
+func render(c *gin.Context, err error, msgKey i18n.Key) {
+	lang := Lang(c)
+	code := apperror.CodeOf(err)
+	status := apperror.Status(code)
+	if msgKey == "" {
+		msgKey = defaultMsg[code]
+	}
+
+	res := model.Response{
+		Code:    status,
+		Msg:     i18n.T(lang, msgKey),
+		MsgCode: string(msgKey),
+		ErrCode: string(code),
+	}
+
+	var appErr *apperror.Error
+	switch {
+	case code == apperror.CodeInternal:
+		slog.ErrorContext(c.Request.Context(), "internal error", "method", c.Request.Method, "err", err)
+		res.Err, res.ErrMsgCode = i18n.T(lang, i18n.InternalError), string(i18n.InternalError)
+	case apperror.As(err, &appErr):
+		res.Err, res.ErrMsgCode = i18n.T(lang, appErr.Key, appErr.Args...), string(appErr.Key)
+		if fields, ok := i18n.ValidationFields(lang, appErr.Err); ok {
+			res.Err = joinFields(fields)
+			res.Data = gin.H{"fields": fields}
+		}
+	}
+	c.JSON(status, res)
+}
+
+var defaultMsg = map[apperror.Code]i18n.Key{
+	apperror.CodeValidation:   i18n.ReqParamInvalid,
+	apperror.CodeUnauthorized: i18n.Unauthorized,
+	apperror.CodeForbidden:    i18n.Forbidden,
+	apperror.CodeTooLarge:     i18n.BodyTooLarge,
+	apperror.CodeInternal:     i18n.InternalError,
+}
+
+// joinFields menggabungkan pesan per field, urut nama field supaya stabil.
+func joinFields(fields map[string]string) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msgs := make([]string, len(names))
+	for i, name := range names {
+		msgs[i] = fields[name]
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Fail menghentikan request dengan err; msg menjadi ringkasan di Response.Msg.
+func Fail(c *gin.Context, msg i18n.Key, err error) {
+	_ = c.Error(err).SetMeta(msg)
+	c.Abort()
+}
+
+// Respond menulis response sukses dengan pesan dari katalog sesuai bahasa request.
+func Respond(c *gin.Context, status int, msg i18n.Key, data any) {
+	c.JSON(status, model.Response{
+		Code:    status,
+		Msg:     i18n.T(Lang(c), msg),
+		MsgCode: string(msg),
+		Data:    data,
+	})
+}
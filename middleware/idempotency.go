@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+	"mojo-autotech/metrics"
+	idem "mojo-autotech/model/idempotency"
+	"mojo-autotech/tenant"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// ReplayedHeader ditambahkan pada response yang diambil dari simpanan.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKey = 255
+)
+
+var (
+	ErrIdemKeyInvalid = apperror.Validation(i18n.IdemKeyInvalid)
+	ErrIdemKeyReused  = apperror.New(apperror.CodeUnprocessable, i18n.IdemKeyReused)
+	ErrIdemInProgress = apperror.Conflict(i18n.IdemInProgress)
+)
+
+// IdempotencyPolicy mengatur satu pemasangan middleware Idempotency.
+type IdempotencyPolicy struct {
+	TTL  time.Duration // lama response disimpan untuk replay
+	Lock time.Duration // batas request pertama dianggap masih berjalan
+	// Secret: kunci HMAC hash request. Body login berisi password, jadi hash
+	// tanpa kunci bisa ditebak offline dari isi tabel.
+	Secret []byte
+	// Scope pemilik key; key dari dua user berbeda tidak pernah bentrok.
+	Scope KeyFunc
+	// Tenant mencari tenant request yang belum membawanya di context (login
+	// belum punya token; tenant-nya ada di body). Nil = tenant dari Auth.
+	// Kalau error, request diproses tanpa key.
+	Tenant func(ctx context.Context, body []byte) (uint, error)
+	// SkipSuccess: response 2xx tidak disimpan, retry setelah sukses diproses
+	// ulang. Dipakai login supaya token tidak ikut tersimpan di database.
+	SkipSuccess bool
+}
+
+// Idempotency membuat request ber-header Idempotency-Key aman diulang: retry
+// dengan key dan isi yang sama mendapat response pertama apa adanya, key yang
+// dipakai untuk isi berbeda ditolak 422, dan retry yang datang saat request
+// pertama belum selesai ditolak 409. Response 5xx tidak disimpan supaya client
+// bisa mencoba lagi. Request tanpa header diproses seperti biasa.
+//
+// Dipasang setelah Auth (scope dan tenant dari token) dan BodyLimit.
+func Idempotency(store idem.IIdempotencyRepository, p IdempotencyPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if !validIdempotencyKey(key) {
+			Fail(c, i18n.ReqParamInvalid, ErrIdemKeyInvalid)
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			Fail(c, i18n.ReqParamInvalid, apperror.Invalid(err))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// key disimpan per tenant; context request sendiri tidak diubah
+		ctx := c.Request.Context()
+		if p.Tenant != nil {
+			tid, err := p.Tenant(ctx, body)
+			if err != nil {
+				c.Next()
+				return
+			}
+			ctx = tenant.WithID(ctx, tid)
+		}
+		scope, hash := p.Scope(c), requestHash(p.Secret, c, body)
+		existing, reserved, err := store.Reserve(ctx, scope, key, hash, p.Lock)
+		if err != nil {
+			// seperti rate limiter: database bermasalah tidak boleh menahan absensi
+			slog.WarnContext(ctx, "idempotency store gagal, request diproses tanpa key", "err", err)
+			c.Next()
+			return
+		}
+		if !reserved {
+			replay(c, existing, hash)
+			return
+		}
+
+		// request ini pemilik key: simpan response-nya, atau lepas key kalau gagal
+		release := func() {
+			if err := store.Release(context.WithoutCancel(ctx), scope, key); err != nil {
+				slog.WarnContext(ctx, "lepas idempotency key gagal", "err", err)
+			}
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				release()
+				panic(r)
+			}
+		}()
+
+		w := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		// error dari Fail dirender sekarang supaya ikut tersimpan
+		flush(c)
+		c.Writer = w.ResponseWriter
+
+		status := w.Status()
+		if status >= http.StatusInternalServerError || (p.SkipSuccess && status < http.StatusMultipleChoices) {
+			release()
+			return
+		}
+		res := idem.Response{StatusCode: status, ContentType: w.Header().Get("Content-Type"), Body: w.buf.Bytes()}
+		if err := store.Complete(context.WithoutCancel(ctx), scope, key, res, p.TTL); err != nil {
+			slog.WarnContext(ctx, "simpan response idempotency gagal", "err", err)
+			release()
+		}
+	}
+}
+
+// replay menjawab retry dari record milik request sebelumnya.
+func replay(c *gin.Context, rec idem.Record, hash string) {
+	switch {
+	case rec.Scope == "" || rec.Pending():
+		// record kosong: pemilik key baru saja melepasnya, client cukup mencoba lagi
+		metrics.Idempotency.WithLabelValues("in_progress").Inc()
+		c.Header("Retry-After", "1")
+		Fail(c, i18n.IdemInProgress, ErrIdemInProgress)
+	case !hmac.Equal([]byte(rec.RequestHash), []byte(hash)):
+		metrics.Idempotency.WithLabelValues("mismatch").Inc()
+		Fail(c, i18n.IdemKeyReused, ErrIdemKeyReused)
+	default:
+		metrics.Idempotency.WithLabelValues("replayed").Inc()
+		c.Header(ReplayedHeader, "true")
+		c.Data(rec.StatusCode, rec.ContentType, rec.Body)
+		c.Abort()
+	}
+}
+
+// requestHash mengikat key ke method, route (tanpa prefix versi, jadi alias
+// legacy dan /api/v1 dianggap sama) dan body.
+func requestHash(secret []byte, c *gin.Context, body []byte) string {
+	route := c.FullPath()
+	if i := strings.Index(route, "/api/v"); i >= 0 {
+		if j := strings.Index(route[i+len("/api/v"):], "/"); j >= 0 {
+			route = route[i+len("/api/v")+j:]
+		}
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(c.Request.Method + " " + route + "\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validIdempotencyKey: 1-255 karakter ASCII yang terlihat (UUID, ULID, dsb.).
+func validIdempotencyKey(k string) bool {
+	if len(k) > maxIdempotencyKey {
+		return false
+	}
+	for i := 0; i < len(k); i++ {
+		if k[i] < 0x21 || k[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// captureWriter menyalin body response supaya bisa disimpan.
+type captureWriter struct {
+	gin.ResponseWriter
+	buf bytes.Buffer
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.buf.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.buf.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/i18n"
+)
+
+const langKey = "lang"
+
+// Language memilih bahasa response dari Accept-Language (default Indonesia).
+func Language() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+		c.Set(langKey, lang)
+		c.Header("Content-Language", string(lang))
+		c.Header("Vary", "Accept-Language")
+		c.Next()
+	}
+}
+
+// Lang mengambil bahasa yang dipilih Language().
+func Lang(c *gin.Context) i18n.Lang {
+	if v, ok := c.Get(langKey); ok {
+		if lang, ok := v.(i18n.Lang); ok {
+			return lang
+		}
+	}
+	return i18n.Default
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+	"mojo-autotech/logging"
+)
+
+// RequestIDHeader diterima dari client/proxy dan selalu dikirim balik.
+const RequestIDHeader = "X-Request-ID"
+
+// request ID dari luar hanya dipakai kalau aman ditulis ke log apa adanya
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// RequestID memakai X-Request-ID dari request (kalau valid) atau membuat yang
+// baru, mengirimnya balik di response, dan menaruhnya di context request
+// bersama route supaya semua log di handler/service/repo ikut membawanya.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+		c.Set("request_id", id)
+		c.Request = c.Request.WithContext(logging.WithRequest(c.Request.Context(), id, c.FullPath()))
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// AccessLog mencatat satu baris per request setelah selesai. Query string
+// tidak dicatat karena bisa berisi token; probe hanya di level debug.
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		case c.FullPath() == "/healthz" || c.FullPath() == "/readyz":
+			level = slog.LevelDebug
+		}
+
+		attrs := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", status,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"bytes", c.Writer.Size(),
+			"client_ip", c.ClientIP(),
+			"user_agent", c.Request.UserAgent(),
+		}
+		if last := c.Errors.Last(); last != nil {
+			attrs = append(attrs, "err_code", string(apperror.CodeOf(last.Err)))
+		}
+		slog.Log(c.Request.Context(), level, "http request", attrs...)
+	}
+}
+
+// Recovery mengganti recovery bawaan gin: panic dicatat lewat slog beserta
+// stack trace, client menerima response INTERNAL biasa.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, rec any) {
+		slog.ErrorContext(c.Request.Context(), "panic", "panic", fmt.Sprint(rec), "stack", string(debug.Stack()))
+		render(c, apperror.Wrap(apperror.CodeInternal, i18n.InternalError, fmt.Errorf("panic: %v", rec)), i18n.InternalError)
+		c.Abort()
+	})
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/metrics"
+)
+
+// Metrics mencatat durasi setiap request ke histogram HTTP. Route memakai pola
+// gin (mis. /api/v1/work-locations/:id) supaya label tidak meledak; request ke
+// path yang tidak terdaftar dikelompokkan sebagai "unmatched".
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.HTTPDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
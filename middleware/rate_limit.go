@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+	"mojo-autotech/metrics"
+	"mojo-autotech/ratelimit"
+)
+
+var ErrRateLimited = apperror.New(apperror.CodeRateLimited, i18n.RateLimited)
+
+// KeyFunc menentukan pemilik bucket untuk satu request.
+type KeyFunc func(c *gin.Context) string
+
+// ByIP: satu bucket per IP klien (sesuai TrustedProxies).
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByUser: satu bucket per user dari Auth(), jadi wajib dipasang setelah Auth.
+// Request tanpa user (mis. Auth dilewati) jatuh ke bucket per IP.
+func ByUser(c *gin.Context) string {
+	if uid, ok := CurrentUserID(c); ok {
+		return "user:" + strconv.FormatUint(uint64(uid), 10)
+	}
+	return ByIP(c)
+}
+
+// RateLimit mengambil satu token dari bucket policy+key; kalau habis request
+// ditolak 429 dengan header Retry-After (detik). Backend yang error tidak
+// menggagalkan request: lebih baik sementara tanpa batas daripada absensi
+// seluruh kantor gagal karena Redis mati.
+func RateLimit(store ratelimit.Store, policy string, rule ratelimit.Rule, key KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		res, err := store.Take(c.Request.Context(), policy+":"+key(c), rule)
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "rate limiter gagal, request diloloskan", "policy", policy, "err", err)
+			c.Next()
+			return
+		}
+		if !res.Allowed {
+			secs := max(1, int(math.Ceil(res.RetryAfter.Seconds())))
+			c.Header("Retry-After", strconv.Itoa(secs))
+			metrics.RateLimited.WithLabelValues(policy).Inc()
+			Fail(c, i18n.TooManyRequests, ErrRateLimited.With(secs))
+			return
+		}
+		c.Next()
+	}
+}
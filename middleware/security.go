@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/config"
+)
+
+// SecurityHeaders mengirim header keamanan di setiap response. Response API
+// hanya JSON/file, jadi CSP default menolak semua sumber; halaman HTML (mis.
+// /docs) menimpa Content-Security-Policy dengan kebijakannya sendiri.
+func SecurityHeaders(cfg config.Security) gin.HandlerFunc {
+	ancestors := "'none'"
+	if cfg.FrameOptions == "SAMEORIGIN" {
+		ancestors = "'self'"
+	}
+	csp := "default-src 'none'; frame-ancestors " + ancestors
+
+	var hsts string
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.FormatInt(int64(cfg.HSTSMaxAge.Seconds()), 10)
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("X-Frame-Options", cfg.FrameOptions)
+		h.Set("Referrer-Policy", "no-referrer")
+		h.Set("Content-Security-Policy", csp)
+		if hsts != "" {
+			h.Set("Strict-Transport-Security", hsts)
+		}
+		c.Next()
+	}
+}
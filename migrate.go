@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"mojo-autotech/config"
+	"mojo-autotech/migration"
+)
+
+const migrateUsage = `usage: mojo-autotech migrate <command>
+
+commands:
+  up             terapkan semua migrasi yang belum jalan
+  down [n]       rollback n migrasi terakhir (default 1)
+  status         tampilkan migrasi dan waktu diterapkan
+  create <name>  buat file up/down baru di ` + migration.Dir
+
+// runMigrate menjalankan subcommand `migrate`.
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+
+	// create tidak butuh koneksi database
+	if args[0] == "create" {
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			os.Exit(2)
+		}
+		up, down, err := migration.Create(migration.Dir, args[1])
+		if err != nil {
+			log.Fatalf("migrate create: %v", err)
+		}
+		fmt.Println("created", up)
+		fmt.Println("created", down)
+		return
+	}
+
+	switch args[0] {
+	case "up", "down", "status":
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+
+	cfg := loadConfig(config.Flags{})
+	db, err := config.NewDB(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect database: %v", err)
+	}
+	m, err := migration.New(db)
+	if err != nil {
+		log.Fatalf("migrate: %v", err)
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		done, err := m.Up(ctx)
+		for _, d := range done {
+			fmt.Printf("applied %04d_%s\n", d.Version, d.Name)
+		}
+		if err != nil {
+			log.Fatalf("migrate up: %v", err)
+		}
+		if len(done) == 0 {
+			fmt.Println("schema is up to date")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				log.Fatalf("migrate down: jumlah langkah tidak valid: %q", args[1])
+			}
+		}
+		done, err := m.Down(ctx, steps)
+		for _, d := range done {
+			fmt.Printf("rolled back %04d_%s\n", d.Version, d.Name)
+		}
+		if err != nil {
+			log.Fatalf("migrate down: %v", err)
+		}
+	case "status":
+		st, err := m.Status(ctx)
+		if err != nil {
+			log.Fatalf("migrate status: %v", err)
+		}
+		for _, s := range st {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Printf("%04d  %-30s %s\n", s.Version, s.Name, applied)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+// Package migration menjalankan migrasi SQL berversi yang di-embed ke binary.
+// File ada di migration/sql dengan pola NNNN_nama.up.sql / NNNN_nama.down.sql.
+package migration
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// Dir adalah lokasi file migrasi di source tree, dipakai oleh Create.
+const Dir = "migration/sql"
+
+var namePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+type applied struct {
+	Version   int64
+	Name      string
+	AppliedAt time.Time
+}
+
+const (
+	qCreateTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+  version    bigint PRIMARY KEY,
+  name       text NOT NULL,
+  applied_at timestamptz NOT NULL DEFAULT NOW()
+);
+`
+	qApplied    = `SELECT version, name, applied_at FROM schema_migrations ORDER BY version;`
+	qInsert     = `INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, NOW());`
+	qDelete     = `DELETE FROM schema_migrations WHERE version = ?;`
+	qAdvisoryLk = `SELECT pg_advisory_xact_lock(727001);` // cegah dua instance migrasi bersamaan
+)
+
+// Load membaca semua migrasi yang di-embed, urut berdasarkan versi.
+func Load() ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "sql")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		m := namePattern.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("nama file migrasi tidak valid: %s", e.Name())
+		}
+		v, _ := strconv.ParseInt(m[1], 10, 64)
+		body, err := files.ReadFile("sql/" + e.Name())
+		if err != nil {
+			return nil, err
+		}
+		mig, ok := byVersion[v]
+		if !ok {
+			mig = &Migration{Version: v, Name: m[2]}
+			byVersion[v] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("versi %d dipakai dua nama: %s dan %s", v, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	out := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migrasi %04d_%s tidak punya file up", m.Version, m.Name)
+		}
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+func New(db *gorm.DB) (*Migrator, error) {
+	ms, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: ms}, nil
+}
+
+// Latest adalah versi tertinggi yang dibawa binary ini.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+func (m *Migrator) applied(ctx context.Context, tx *gorm.DB) (map[int64]applied, error) {
+	if err := tx.WithContext(ctx).Exec(qCreateTable).Error; err != nil {
+		return nil, err
+	}
+	var rows []applied
+	if err := tx.WithContext(ctx).Raw(qApplied).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[int64]applied, len(rows))
+	for _, r := range rows {
+		out[r.Version] = r
+	}
+	return out, nil
+}
+
+// Current adalah versi tertinggi yang sudah diterapkan di database (0 = belum ada).
+func (m *Migrator) Current(ctx context.Context) (int64, error) {
+	done, err := m.applied(ctx, m.db)
+	if err != nil {
+		return 0, err
+	}
+	var cur int64
+	for v := range done {
+		if v > cur {
+			cur = v
+		}
+	}
+	return cur, nil
+}
+
+// Pending adalah migrasi yang belum diterapkan.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	done, err := m.applied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+	var out []Migration
+	for _, mig := range m.migrations {
+		if _, ok := done[mig.Version]; !ok {
+			out = append(out, mig)
+		}
+	}
+	return out, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	done, err := m.applied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := Status{Version: mig.Version, Name: mig.Name}
+		if a, ok := done[mig.Version]; ok {
+			at := a.AppliedAt
+			s.AppliedAt = &at
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// Up menerapkan semua migrasi yang belum jalan, masing-masing dalam transaksi sendiri.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	for _, mig := range m.migrations {
+		ran := false
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(qAdvisoryLk).Error; err != nil {
+				return err
+			}
+			already, err := m.applied(ctx, tx)
+			if err != nil {
+				return err
+			}
+			if _, ok := already[mig.Version]; ok {
+				return nil
+			}
+			if err := tx.Exec(mig.Up).Error; err != nil {
+				return fmt.Errorf("migrasi %04d_%s: %w", mig.Version, mig.Name, err)
+			}
+			ran = true
+			return tx.Exec(qInsert, mig.Version, mig.Name).Error
+		})
+		if err != nil {
+			return done, err
+		}
+		if ran {
+			done = append(done, mig)
+		}
+	}
+	return done, nil
+}
+
+// Down membatalkan steps migrasi terakhir yang sudah diterapkan.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	for i := 0; i < steps; i++ {
+		var rolled *Migration
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(qAdvisoryLk).Error; err != nil {
+				return err
+			}
+			already, err := m.applied(ctx, tx)
+			if err != nil {
+				return err
+			}
+			for j := len(m.migrations) - 1; j >= 0; j-- {
+				mig := m.migrations[j]
+				if _, ok := already[mig.Version]; !ok {
+					continue
+				}
+				if mig.Down == "" {
+					return fmt.Errorf("migrasi %04d_%s tidak punya file down", mig.Version, mig.Name)
+				}
+				if err := tx.Exec(mig.Down).Error; err != nil {
+					return fmt.Errorf("rollback %04d_%s: %w", mig.Version, mig.Name, err)
+				}
+				rolled = &mig
+				return tx.Exec(qDelete, mig.Version).Error
+			}
+			return nil
+		})
+		if err != nil {
+			return done, err
+		}
+		if rolled == nil {
+			break // tidak ada lagi yang bisa di-rollback
+		}
+		done = append(done, *rolled)
+	}
+	return done, nil
+}
+
+// ErrBehind dikembalikan EnsureCurrent kalau masih ada migrasi yang belum jalan.
+var ErrBehind = errors.New("skema database tertinggal, jalankan `migrate up`")
+
+// EnsureCurrent dipakai saat start server: menolak jalan kalau skema belum terbaru.
+func (m *Migrator) EnsureCurrent(ctx context.Context) error {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		names := make([]string, 0, len(pending))
+		for _, p := range pending {
+			names = append(names, fmt.Sprintf("%04d_%s", p.Version, p.Name))
+		}
+		return fmt.Errorf("%w (pending: %s)", ErrBehind, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// Create membuat pasangan file up/down kosong dengan versi berikutnya di dir.
+// Binary harus di-build ulang supaya migrasi baru ikut di-embed.
+func Create(dir, name string) (up, down string, err error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(name, "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return "", "", errors.New("nama migrasi kosong")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", "", err
+	}
+	var next int64 = 1
+	for _, e := range entries {
+		if m := namePattern.FindStringSubmatch(e.Name()); m != nil {
+			if v, _ := strconv.ParseInt(m[1], 10, 64); v >= next {
+				next = v + 1
+			}
+		}
+	}
+
+	base := fmt.Sprintf("%04d_%s", next, name)
+	up = filepath.Join(dir, base+".up.sql")
+	down = filepath.Join(dir, base+".down.sql")
+	if err := os.WriteFile(up, []byte("-- "+base+" up\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(down, []byte("-- "+base+" down\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	return up, down, nil
+}
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS "anomalies";
+DROP TABLE IF EXISTS "punch_logs";
+DROP TABLE IF EXISTS "work_locations";
+DROP TABLE IF EXISTS "sync_punches";
+DROP TABLE IF EXISTS "sync_devices";
+DROP TABLE IF EXISTS "kiosks";
+DROP TABLE IF EXISTS "payroll_periods";
+DROP TABLE IF EXISTS "attendances";
+DROP TABLE IF EXISTS "users";
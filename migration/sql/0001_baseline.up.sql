@@ -0,0 +1,183 @@
+-- Baseline: skema yang sebelumnya dibuat db.AutoMigrate.
+-- Memakai IF NOT EXISTS supaya database lama yang sudah di-AutoMigrate bisa
+-- langsung diadopsi tanpa kehilangan data.
+
+CREATE TABLE IF NOT EXISTS "users" (
+  "id"            bigserial,
+  "user_id"       bigint,
+  "username"      varchar(50) NOT NULL,
+  "email"         varchar(120) NOT NULL,
+  "full_name"     varchar(120),
+  "phone"         varchar(20),
+  "password_hash" text NOT NULL,
+  "badge_id"      varchar(40),
+  "pin_hash"      text,
+  "role"          varchar(20) DEFAULT 'EMPLOYEE',
+  "is_active"     boolean DEFAULT true,
+  "last_login_at" timestamptz,
+  "failed_login"  bigint DEFAULT 0,
+  "created_at"    timestamptz,
+  "updated_at"    timestamptz,
+  "deleted_at"    timestamptz,
+  PRIMARY KEY ("id", "user_id")
+);
+-- kolom kiosk ditambahkan setelah sebagian database sudah dibuat AutoMigrate
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "badge_id" varchar(40);
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "pin_hash" text;
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_users_role" ON "users" ("role");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_badge_id" ON "users" ("badge_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_username" ON "users" ("username");
+
+CREATE TABLE IF NOT EXISTS "attendances" (
+  "id"                 bigserial,
+  "user_id"            bigint,
+  "work_location_id"   bigint,
+  "shift_id"           bigint,
+  "date"               date,
+  "check_in_at"        timestamptz,
+  "check_in_lat"       decimal,
+  "check_in_lng"       decimal,
+  "check_in_photo_url" text,
+  "check_in_ip"        inet,
+  "check_in_kiosk_id"  bigint,
+  "check_out_at"       timestamptz,
+  "check_out_ip"       inet,
+  "check_out_kiosk_id" bigint,
+  "total_minutes"      bigint,
+  "status"             text,
+  "activity"           text,
+  "created_at"         timestamptz,
+  "updated_at"         timestamptz,
+  PRIMARY KEY ("id")
+);
+ALTER TABLE "attendances" ADD COLUMN IF NOT EXISTS "check_in_kiosk_id" bigint;
+ALTER TABLE "attendances" ADD COLUMN IF NOT EXISTS "check_out_kiosk_id" bigint;
+CREATE INDEX IF NOT EXISTS "idx_attendances_check_in_kiosk_id" ON "attendances" ("check_in_kiosk_id");
+-- dibutuhkan ON CONFLICT (user_id, date) di qUpsertCheckIn
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_date" ON "attendances" ("user_id", "date");
+
+CREATE TABLE IF NOT EXISTS "payroll_periods" (
+  "id"              bigserial,
+  "period_start"    date NOT NULL,
+  "period_end"      date NOT NULL,
+  "status"          varchar(20) DEFAULT 'OPEN',
+  "export_format"   varchar(20),
+  "export_checksum" varchar(64),
+  "exported_at"     timestamptz,
+  "locked_at"       timestamptz,
+  "locked_by"       bigint,
+  "created_at"      timestamptz,
+  "updated_at"      timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_payroll_periods_status" ON "payroll_periods" ("status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_payroll_period" ON "payroll_periods" ("period_start", "period_end");
+
+CREATE TABLE IF NOT EXISTS "kiosks" (
+  "id"               bigserial,
+  "code"             varchar(40) NOT NULL,
+  "name"             varchar(120),
+  "location"         varchar(120),
+  "work_location_id" bigint,
+  "lat"              decimal,
+  "lng"              decimal,
+  "key_hash"         varchar(64) NOT NULL,
+  "is_active"        boolean DEFAULT true,
+  "last_seen_at"     timestamptz,
+  "created_at"       timestamptz,
+  "updated_at"       timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_kiosks_key_hash" ON "kiosks" ("key_hash");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_kiosks_code" ON "kiosks" ("code");
+
+CREATE TABLE IF NOT EXISTS "sync_devices" (
+  "id"         bigserial,
+  "user_id"    bigint NOT NULL,
+  "device_id"  varchar(64) NOT NULL,
+  "secret"     varchar(64) NOT NULL,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_sync_device" ON "sync_devices" ("user_id", "device_id");
+
+CREATE TABLE IF NOT EXISTS "sync_punches" (
+  "id"            bigserial,
+  "user_id"       bigint NOT NULL,
+  "punch_id"      varchar(64) NOT NULL,
+  "device_id"     varchar(64),
+  "type"          varchar(3),
+  "device_time"   timestamptz,
+  "estimated_at"  timestamptz,
+  "skew_seconds"  bigint,
+  "monotonic_ms"  bigint,
+  "lat"           decimal,
+  "lng"           decimal,
+  "activity"      text,
+  "status"        varchar(20),
+  "reason"        text,
+  "attendance_id" bigint,
+  "reviewed_by"   bigint,
+  "reviewed_at"   timestamptz,
+  "received_at"   timestamptz,
+  "created_at"    timestamptz,
+  "updated_at"    timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sync_punches_status" ON "sync_punches" ("status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_sync_punch" ON "sync_punches" ("user_id", "punch_id");
+
+CREATE TABLE IF NOT EXISTS "work_locations" (
+  "id"         bigserial,
+  "name"       varchar(120) NOT NULL,
+  "address"    text,
+  "lat"        decimal,
+  "lng"        decimal,
+  "radius_m"   bigint DEFAULT 200,
+  "networks"   text,
+  "ip_policy"  varchar(10) DEFAULT 'OFF',
+  "is_active"  boolean DEFAULT true,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+ALTER TABLE "work_locations" ADD COLUMN IF NOT EXISTS "ip_policy" varchar(10) DEFAULT 'OFF';
+
+CREATE TABLE IF NOT EXISTS "punch_logs" (
+  "id"               bigserial,
+  "user_id"          bigint NOT NULL,
+  "attendance_id"    bigint,
+  "work_location_id" bigint,
+  "at"               timestamptz,
+  "lat"              decimal,
+  "lng"              decimal,
+  "ip"               inet,
+  "mock_location"    boolean,
+  "created_at"       timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_punch_log_user_at" ON "punch_logs" ("user_id", "at");
+
+CREATE TABLE IF NOT EXISTS "anomalies" (
+  "id"            bigserial,
+  "user_id"       bigint NOT NULL,
+  "attendance_id" bigint,
+  "punch_log_id"  bigint,
+  "rule"          varchar(40),
+  "severity"      varchar(10),
+  "detail"        text,
+  "status"        varchar(20) DEFAULT 'OPEN',
+  "reviewed_by"   bigint,
+  "reviewed_at"   timestamptz,
+  "review_note"   text,
+  "created_at"    timestamptz,
+  "updated_at"    timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_anomalies_status" ON "anomalies" ("status");
+CREATE INDEX IF NOT EXISTS "idx_anomalies_rule" ON "anomalies" ("rule");
+CREATE INDEX IF NOT EXISTS "idx_anomalies_attendance_id" ON "anomalies" ("attendance_id");
+CREATE INDEX IF NOT EXISTS "idx_anomalies_user_id" ON "anomalies" ("user_id");
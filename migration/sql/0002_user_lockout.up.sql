@@ -0,0 +1,2 @@
+-- Penguncian akun setelah password salah berulang kali (config login.*).
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "locked_until" timestamptz;
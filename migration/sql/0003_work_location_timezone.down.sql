@@ -0,0 +1 @@
+ALTER TABLE "work_locations" DROP COLUMN IF EXISTS "timezone";
@@ -0,0 +1,2 @@
+-- Timezone IANA per lokasi kerja; NULL/kosong = timezone perusahaan (config timezone).
+ALTER TABLE "work_locations" ADD COLUMN IF NOT EXISTS "timezone" varchar(64);
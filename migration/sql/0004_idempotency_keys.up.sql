@@ -0,0 +1,15 @@
+-- Response yang disimpan untuk header Idempotency-Key. status_code 0 = request
+-- masih diproses; expires_at saat itu adalah batas lock, setelah selesai
+-- menjadi batas replay (idempotency.ttl).
+CREATE TABLE IF NOT EXISTS "idempotency_keys" (
+  "scope"        varchar(80)  NOT NULL, -- "user:<id>" atau "ip:<alamat>"
+  "key"          varchar(255) NOT NULL,
+  "request_hash" char(64)     NOT NULL, -- HMAC-SHA256 method, route dan body
+  "status_code"  integer      NOT NULL DEFAULT 0,
+  "content_type" text,
+  "body"         bytea,
+  "created_at"   timestamptz  NOT NULL,
+  "expires_at"   timestamptz  NOT NULL,
+  PRIMARY KEY ("scope", "key")
+);
+CREATE INDEX IF NOT EXISTS "idx_idempotency_keys_expires_at" ON "idempotency_keys" ("expires_at");
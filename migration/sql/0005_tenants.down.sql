@@ -0,0 +1,24 @@
+-- Gagal kalau sudah ada username/email/badge/kode kiosk/periode yang sama di
+-- tenant berbeda; gabungkan atau hapus datanya dulu.
+DROP INDEX IF EXISTS "idx_users_tenant_username";
+DROP INDEX IF EXISTS "idx_users_tenant_email";
+DROP INDEX IF EXISTS "idx_users_tenant_badge_id";
+DROP INDEX IF EXISTS "idx_kiosks_tenant_code";
+DROP INDEX IF EXISTS "idx_payroll_period";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_username" ON "users" ("username");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_badge_id" ON "users" ("badge_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_kiosks_code" ON "kiosks" ("code");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_payroll_period" ON "payroll_periods" ("period_start", "period_end");
+
+ALTER TABLE "users"           DROP COLUMN IF EXISTS "tenant_id";
+ALTER TABLE "attendances"     DROP COLUMN IF EXISTS "tenant_id";
+ALTER TABLE "payroll_periods" DROP COLUMN IF EXISTS "tenant_id";
+ALTER TABLE "kiosks"          DROP COLUMN IF EXISTS "tenant_id";
+ALTER TABLE "sync_devices"    DROP COLUMN IF EXISTS "tenant_id";
+ALTER TABLE "sync_punches"    DROP COLUMN IF EXISTS "tenant_id";
+ALTER TABLE "work_locations"  DROP COLUMN IF EXISTS "tenant_id";
+ALTER TABLE "punch_logs"      DROP COLUMN IF EXISTS "tenant_id";
+ALTER TABLE "anomalies"       DROP COLUMN IF EXISTS "tenant_id";
+
+DROP TABLE IF EXISTS "tenants";
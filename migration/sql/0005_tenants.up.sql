@@ -0,0 +1,56 @@
+-- Multi-tenant: satu database untuk beberapa perusahaan/workshop. Data yang
+-- sudah ada menjadi milik tenant 1 ("default"). tenant_id tidak punya default
+-- setelah backfill, jadi INSERT yang lupa mengisi tenant ditolak database.
+CREATE TABLE IF NOT EXISTS "tenants" (
+  "id"         bigserial,
+  "code"       varchar(40)  NOT NULL, -- dikirim client saat login, mis. "mojo-cikarang"
+  "name"       varchar(120) NOT NULL,
+  "is_active"  boolean      NOT NULL DEFAULT true,
+  "created_at" timestamptz  NOT NULL,
+  "updated_at" timestamptz  NOT NULL,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tenants_code" ON "tenants" ("code");
+
+INSERT INTO "tenants" ("id", "code", "name", "created_at", "updated_at")
+VALUES (1, 'default', 'Mojo Autotech', NOW(), NOW())
+ON CONFLICT ("id") DO NOTHING;
+SELECT setval(pg_get_serial_sequence('tenants', 'id'), GREATEST((SELECT MAX("id") FROM "tenants"), 1));
+
+ALTER TABLE "users"           ADD COLUMN IF NOT EXISTS "tenant_id" bigint NOT NULL DEFAULT 1 REFERENCES "tenants" ("id");
+ALTER TABLE "attendances"     ADD COLUMN IF NOT EXISTS "tenant_id" bigint NOT NULL DEFAULT 1 REFERENCES "tenants" ("id");
+ALTER TABLE "payroll_periods" ADD COLUMN IF NOT EXISTS "tenant_id" bigint NOT NULL DEFAULT 1 REFERENCES "tenants" ("id");
+ALTER TABLE "kiosks"          ADD COLUMN IF NOT EXISTS "tenant_id" bigint NOT NULL DEFAULT 1 REFERENCES "tenants" ("id");
+ALTER TABLE "sync_devices"    ADD COLUMN IF NOT EXISTS "tenant_id" bigint NOT NULL DEFAULT 1 REFERENCES "tenants" ("id");
+ALTER TABLE "sync_punches"    ADD COLUMN IF NOT EXISTS "tenant_id" bigint NOT NULL DEFAULT 1 REFERENCES "tenants" ("id");
+ALTER TABLE "work_locations"  ADD COLUMN IF NOT EXISTS "tenant_id" bigint NOT NULL DEFAULT 1 REFERENCES "tenants" ("id");
+ALTER TABLE "punch_logs"      ADD COLUMN IF NOT EXISTS "tenant_id" bigint NOT NULL DEFAULT 1 REFERENCES "tenants" ("id");
+ALTER TABLE "anomalies"       ADD COLUMN IF NOT EXISTS "tenant_id" bigint NOT NULL DEFAULT 1 REFERENCES "tenants" ("id");
+
+ALTER TABLE "users"           ALTER COLUMN "tenant_id" DROP DEFAULT;
+ALTER TABLE "attendances"     ALTER COLUMN "tenant_id" DROP DEFAULT;
+ALTER TABLE "payroll_periods" ALTER COLUMN "tenant_id" DROP DEFAULT;
+ALTER TABLE "kiosks"          ALTER COLUMN "tenant_id" DROP DEFAULT;
+ALTER TABLE "sync_devices"    ALTER COLUMN "tenant_id" DROP DEFAULT;
+ALTER TABLE "sync_punches"    ALTER COLUMN "tenant_id" DROP DEFAULT;
+ALTER TABLE "work_locations"  ALTER COLUMN "tenant_id" DROP DEFAULT;
+ALTER TABLE "punch_logs"      ALTER COLUMN "tenant_id" DROP DEFAULT;
+ALTER TABLE "anomalies"       ALTER COLUMN "tenant_id" DROP DEFAULT;
+
+CREATE INDEX IF NOT EXISTS "idx_attendances_tenant_date" ON "attendances" ("tenant_id", "date");
+CREATE INDEX IF NOT EXISTS "idx_sync_punches_tenant_status" ON "sync_punches" ("tenant_id", "status");
+CREATE INDEX IF NOT EXISTS "idx_anomalies_tenant_status" ON "anomalies" ("tenant_id", "status");
+CREATE INDEX IF NOT EXISTS "idx_work_locations_tenant_id" ON "work_locations" ("tenant_id");
+
+-- Username, email, badge, kode kiosk dan periode payroll cukup unik per
+-- tenant. Device key kiosk tetap unik global karena dipakai mencari tenant.
+DROP INDEX IF EXISTS "idx_users_username";
+DROP INDEX IF EXISTS "idx_users_email";
+DROP INDEX IF EXISTS "idx_users_badge_id";
+DROP INDEX IF EXISTS "idx_kiosks_code";
+DROP INDEX IF EXISTS "idx_payroll_period";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_tenant_username" ON "users" ("tenant_id", "username");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_tenant_email" ON "users" ("tenant_id", "email");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_tenant_badge_id" ON "users" ("tenant_id", "badge_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_kiosks_tenant_code" ON "kiosks" ("tenant_id", "code");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_payroll_period" ON "payroll_periods" ("tenant_id", "period_start", "period_end");
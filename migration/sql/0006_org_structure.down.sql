@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "reporting_lines";
+DROP INDEX IF EXISTS "idx_users_tenant_team";
+DROP INDEX IF EXISTS "idx_users_tenant_manager";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "manager_id";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "team_id";
+DROP TABLE IF EXISTS "teams";
+DROP TABLE IF EXISTS "departments";
@@ -0,0 +1,47 @@
+-- Struktur organisasi: departemen berisi tim, setiap user punya satu tim dan
+-- satu atasan (manager_id). users menyimpan penempatan yang berlaku sekarang,
+-- reporting_lines menyimpan riwayatnya (valid_to NULL = yang berlaku).
+CREATE TABLE IF NOT EXISTS "departments" (
+  "id"         bigserial,
+  "tenant_id"  bigint       NOT NULL REFERENCES "tenants" ("id"),
+  "name"       varchar(120) NOT NULL,
+  "created_at" timestamptz  NOT NULL,
+  "updated_at" timestamptz  NOT NULL,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_departments_tenant_name" ON "departments" ("tenant_id", "name");
+
+CREATE TABLE IF NOT EXISTS "teams" (
+  "id"            bigserial,
+  "tenant_id"     bigint       NOT NULL REFERENCES "tenants" ("id"),
+  "department_id" bigint       NOT NULL REFERENCES "departments" ("id"),
+  "name"          varchar(120) NOT NULL,
+  "manager_id"    bigint, -- atasan default anggota baru tim ini
+  "created_at"    timestamptz  NOT NULL,
+  "updated_at"    timestamptz  NOT NULL,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_teams_department_name" ON "teams" ("department_id", "name");
+CREATE INDEX IF NOT EXISTS "idx_teams_tenant_id" ON "teams" ("tenant_id");
+
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "team_id" bigint REFERENCES "teams" ("id");
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "manager_id" bigint;
+CREATE INDEX IF NOT EXISTS "idx_users_tenant_manager" ON "users" ("tenant_id", "manager_id");
+CREATE INDEX IF NOT EXISTS "idx_users_tenant_team" ON "users" ("tenant_id", "team_id");
+
+CREATE TABLE IF NOT EXISTS "reporting_lines" (
+  "id"         bigserial,
+  "tenant_id"  bigint      NOT NULL REFERENCES "tenants" ("id"),
+  "user_id"    bigint      NOT NULL,
+  "team_id"    bigint      REFERENCES "teams" ("id"),
+  "manager_id" bigint,
+  "valid_from" timestamptz NOT NULL,
+  "valid_to"   timestamptz,
+  "changed_by" bigint,
+  "note"       text,
+  "created_at" timestamptz NOT NULL,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_reporting_lines_user" ON "reporting_lines" ("tenant_id", "user_id", "valid_from");
+-- hanya satu penempatan yang berlaku per user
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_reporting_lines_open" ON "reporting_lines" ("user_id") WHERE "valid_to" IS NULL;
@@ -0,0 +1,4 @@
+-- Penguncian badge kiosk setelah PIN salah berulang kali (config kiosk.pin_*),
+-- terpisah dari penguncian login supaya badge terkunci tidak memblokir app.
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "pin_failed" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "pin_locked_until" timestamptz;
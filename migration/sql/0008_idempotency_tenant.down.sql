@@ -0,0 +1,6 @@
+-- Key yang sama bisa ada di beberapa tenant; key hanya berlaku sementara,
+-- jadi cukup dikosongkan sebelum primary key lama dipasang lagi.
+DELETE FROM "idempotency_keys";
+ALTER TABLE "idempotency_keys" DROP CONSTRAINT IF EXISTS "idempotency_keys_pkey";
+ALTER TABLE "idempotency_keys" DROP COLUMN IF EXISTS "tenant_id";
+ALTER TABLE "idempotency_keys" ADD PRIMARY KEY ("scope", "key");
@@ -0,0 +1,11 @@
+-- Idempotency-Key dipisah per tenant. Key yang sudah ada (paling lama
+-- idempotency.ttl) diberi tenant user pemiliknya; scope IP milik tenant 1.
+ALTER TABLE "idempotency_keys" ADD COLUMN IF NOT EXISTS "tenant_id" bigint REFERENCES "tenants" ("id");
+UPDATE "idempotency_keys" k SET "tenant_id" = u."tenant_id"
+FROM "users" u
+WHERE k."tenant_id" IS NULL AND k."scope" = 'user:' || u."id";
+UPDATE "idempotency_keys" SET "tenant_id" = 1 WHERE "tenant_id" IS NULL;
+ALTER TABLE "idempotency_keys" ALTER COLUMN "tenant_id" SET NOT NULL;
+
+ALTER TABLE "idempotency_keys" DROP CONSTRAINT IF EXISTS "idempotency_keys_pkey";
+ALTER TABLE "idempotency_keys" ADD PRIMARY KEY ("tenant_id", "scope", "key");
@@ -0,0 +1 @@
+-- Baris yang dihapus tidak dikembalikan; punch tersebut memang tidak pernah sah.
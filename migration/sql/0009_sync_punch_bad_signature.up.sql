@@ -0,0 +1,5 @@
+-- Punch dengan signature salah dulu disimpan sebagai REJECTED dan menahan
+-- punch_id-nya, sehingga kiriman ulang yang benar selalu mendapat REJECTED.
+-- Sekarang tidak disimpan lagi; baris lama yang belum direview dihapus.
+DELETE FROM "sync_punches"
+WHERE "status" = 'REJECTED' AND "reason" = 'signature tidak valid' AND "reviewed_by" IS NULL;
@@ -0,0 +1,58 @@
+package anomaly
+
+import "time"
+
+const (
+	StatusOpen      = "OPEN"
+	StatusConfirmed = "CONFIRMED" // admin menyatakan memang kecurangan/bermasalah
+	StatusDismissed = "DISMISSED" // false positive
+
+	SeverityLow    = "LOW"
+	SeverityMedium = "MEDIUM"
+	SeverityHigh   = "HIGH"
+
+	RuleImpossibleTravel   = "IMPOSSIBLE_TRAVEL"
+	RuleRepeatedCoordinate = "REPEATED_COORDINATES"
+	RuleIPNetworkMismatch  = "IP_NETWORK_MISMATCH"
+	RuleIPOutsideAllowlist = "IP_OUTSIDE_ALLOWLIST"
+	RuleMockLocation       = "MOCK_LOCATION"
+)
+
+// PunchLog mencatat setiap punch yang dianalisis, supaya rule bisa
+// membandingkan dengan punch-punch sebelumnya milik user yang sama.
+type PunchLog struct {
+	ID             uint      `json:"id"               gorm:"primaryKey"`
+	TenantID       uint      `json:"-"                gorm:"not null"`
+	UserID         uint      `json:"user_id"          gorm:"index:idx_punch_log_user_at,priority:1;not null"`
+	AttendanceID   *uint     `json:"attendance_id"`
+	WorkLocationID *uint     `json:"work_location_id"`
+	At             time.Time `json:"at"               gorm:"type:timestamptz;index:idx_punch_log_user_at,priority:2"`
+	Lat            *float64  `json:"lat"`
+	Lng            *float64  `json:"lng"`
+	IP             *string   `json:"ip"               gorm:"type:inet"`
+	MockLocation   bool      `json:"mock_location"`
+	CreatedAt      time.Time `json:"created_at"       gorm:"type:timestamptz"`
+}
+
+// Anomaly adalah satu temuan rule, masuk antrean review admin.
+type Anomaly struct {
+	ID           uint       `json:"id"            gorm:"primaryKey"`
+	TenantID     uint       `json:"-"             gorm:"not null"`
+	UserID       uint       `json:"user_id"       gorm:"index;not null"`
+	AttendanceID *uint      `json:"attendance_id" gorm:"index"`
+	PunchLogID   *uint      `json:"punch_log_id"`
+	Rule         string     `json:"rule"          gorm:"size:40;index"`
+	Severity     string     `json:"severity"      gorm:"size:10"`
+	Detail       string     `json:"detail"`
+	Status       string     `json:"status"        gorm:"size:20;default:OPEN;index"`
+	ReviewedBy   *uint      `json:"reviewed_by"`
+	ReviewedAt   *time.Time `json:"reviewed_at"   gorm:"type:timestamptz"`
+	ReviewNote   string     `json:"review_note"`
+	CreatedAt    time.Time  `json:"created_at"    gorm:"type:timestamptz"`
+	UpdatedAt    time.Time  `json:"updated_at"    gorm:"type:timestamptz"`
+}
+
+type ReviewReq struct {
+	Status string `json:"status" binding:"required,oneof=CONFIRMED DISMISSED"`
+	Note   string `json:"note"`
+}
@@ -0,0 +1,155 @@
+package anomaly
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mojo-autotech/clock"
+	org "mojo-autotech/model/org_structure"
+	"mojo-autotech/tenant"
+)
+
+// Semua method hanya melihat data di tenant dari context.
+type IAnomalyRepository interface {
+	// PunchesAround mengembalikan paling banyak limit punch user tepat sebelum
+	// (atau pada) at dan limit punch sesudahnya, urut dari yang terbaru. Punch
+	// sesudah at ada kalau punch offline dikirim belakangan.
+	PunchesAround(ctx context.Context, userID uint, at time.Time, limit int) ([]PunchLog, error)
+	// SavePunch mencatat punch beserta temuannya dalam satu transaksi;
+	// PunchLogID tiap temuan diisi id punch yang baru dibuat.
+	SavePunch(ctx context.Context, p PunchLog, findings []Anomaly) (PunchLog, []Anomaly, error)
+	// List: status kosong = semua status; hanya anomali user di scope.
+	List(ctx context.Context, status string, scope org.Scope) ([]Anomaly, error)
+	GetByID(ctx context.Context, id uint) (Anomaly, error)
+	Review(ctx context.Context, id uint, status, note string, reviewer uint) (Anomaly, error)
+}
+
+// Timestamp review diambil dari clock, bukan NOW() database.
+type AnomalyRepository struct {
+	db    *gorm.DB
+	clock clock.Clock
+}
+
+func NewAnomalyRepository(db *gorm.DB, clk clock.Clock) IAnomalyRepository {
+	return &AnomalyRepository{db: db, clock: clk}
+}
+
+const (
+	qPunchesAround = `
+(SELECT * FROM punch_logs
+ WHERE tenant_id = @tenant AND user_id = @user AND at <= @at
+ ORDER BY at DESC LIMIT @limit)
+UNION ALL
+(SELECT * FROM punch_logs
+ WHERE tenant_id = @tenant AND user_id = @user AND at > @at
+ ORDER BY at LIMIT @limit)
+ORDER BY at DESC;
+`
+
+	// Review hanya untuk temuan yang masih OPEN
+	qReviewAnomaly = `
+UPDATE anomalies
+SET status = @status, review_note = @note, reviewed_by = @reviewer, reviewed_at = @now, updated_at = @now
+WHERE id = @id AND tenant_id = @tenant AND status = 'OPEN'
+RETURNING *;
+`
+)
+
+func (r *AnomalyRepository) PunchesAround(ctx context.Context, userID uint, at time.Time, limit int) ([]PunchLog, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []PunchLog
+	err = r.db.WithContext(ctx).Raw(qPunchesAround, map[string]any{
+		"tenant": tid,
+		"user":   userID,
+		"at":     at,
+		"limit":  limit,
+	}).Scan(&out).Error
+	return out, err
+}
+
+func (r *AnomalyRepository) SavePunch(ctx context.Context, p PunchLog, findings []Anomaly) (PunchLog, []Anomaly, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return PunchLog{}, nil, err
+	}
+	p.TenantID = tid
+	// punch tanpa temuannya tidak pernah dievaluasi ulang, jadi keduanya
+	// tersimpan bersama atau tidak sama sekali
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&p).Error; err != nil {
+			return err
+		}
+		if len(findings) == 0 {
+			return nil
+		}
+		for i := range findings {
+			findings[i].TenantID = tid
+			findings[i].PunchLogID = &p.ID
+		}
+		return tx.Create(&findings).Error
+	})
+	if err != nil {
+		return PunchLog{}, nil, err
+	}
+	return p, findings, nil
+}
+
+func (r *AnomalyRepository) List(ctx context.Context, status string, scope org.Scope) ([]Anomaly, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.All && len(scope.UserIDs) == 0 {
+		return []Anomaly{}, nil
+	}
+	var out []Anomaly
+	q := r.db.WithContext(ctx).Where("tenant_id = ?", tid).Order("created_at DESC")
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if !scope.All {
+		q = q.Where("user_id IN ?", scope.UserIDs)
+	}
+	err = q.Find(&out).Error
+	return out, err
+}
+
+func (r *AnomalyRepository) GetByID(ctx context.Context, id uint) (Anomaly, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Anomaly{}, err
+	}
+	var out Anomaly
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", tid).First(&out, id).Error; err != nil {
+		return Anomaly{}, err
+	}
+	return out, nil
+}
+
+func (r *AnomalyRepository) Review(ctx context.Context, id uint, status, note string, reviewer uint) (Anomaly, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Anomaly{}, err
+	}
+	var out Anomaly
+	res := r.db.WithContext(ctx).Raw(qReviewAnomaly, map[string]any{
+		"status":   status,
+		"note":     note,
+		"reviewer": reviewer,
+		"now":      r.clock.Now(),
+		"id":       id,
+		"tenant":   tid,
+	}).Scan(&out)
+	if res.Error != nil {
+		return Anomaly{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return Anomaly{}, gorm.ErrRecordNotFound
+	}
+	return out, nil
+}
@@ -2,8 +2,14 @@ package attedance
 
 import "time"
 
+const (
+	StatusPresent = "PRESENT"
+	StatusLate    = "LATE" // check-in pertama melewati jam masuk shift + toleransi
+)
+
 type Attendance struct {
 	ID              uint       `json:"id"                 gorm:"primaryKey"`
+	TenantID        uint       `json:"-"                  gorm:"not null"`
 	UserID          uint       `json:"user_id"            gorm:"index:idx_user_date,unique,priority:1"`
 	WorkLocationID  *uint      `json:"work_location_id"`
 	ShiftID         *uint      `json:"shift_id"`
@@ -13,8 +19,10 @@ type Attendance struct {
 	CheckInLng      *float64   `json:"check_in_lng"`
 	CheckInPhotoURL *string    `json:"check_in_photo_url"`
 	CheckInIP       *string    `json:"check_in_ip"        gorm:"type:inet"`
+	CheckInKioskID  *uint      `json:"check_in_kiosk_id"  gorm:"index"` // diisi kalau check-in lewat kiosk
 	CheckOutAt      *time.Time `json:"check_out_at"       gorm:"type:timestamptz"`
 	CheckOutIP      *string    `json:"check_out_ip"       gorm:"type:inet"`
+	CheckOutKioskID *uint      `json:"check_out_kiosk_id"`
 	TotalMinutes    int        `json:"total_minutes"`
 	Status          string     `json:"status"` // PRESENT/LATE/ABSENT/ON_LEAVE
 	Activity        string     `json:"activity"`
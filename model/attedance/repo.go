@@ -2,24 +2,45 @@ package attedance
 
 import (
 	"context"
-	"mojo-autotech/config"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
+
+	"mojo-autotech/clock"
+	"mojo-autotech/tenant"
+	"mojo-autotech/tracing"
 )
 
+// Span repository membungkus span query GORM supaya terlihat operasi mana yang
+// memicu query tersebut.
+var tracer = tracing.Tracer("model/attendance")
+
+// Semua method hanya membaca/menulis absensi di tenant dari context.
 type IAttendanceRepository interface {
 	UpsertCheckIn(ctx context.Context, a Attendance) (out Attendance, created bool, err error)
 	GetByUserAndDate(ctx context.Context, userID uint, date time.Time) (Attendance, error)
-	CheckOut(ctx context.Context, userID uint, date time.Time, ip *string) (Attendance, error)
+	// ListByUserBetween: absensi user dengan tanggal kerja from..to, terbaru dulu.
+	ListByUserBetween(ctx context.Context, userID uint, from, to time.Time) ([]Attendance, error)
+	CheckOut(ctx context.Context, userID uint, date time.Time, at *time.Time, ip *string, kioskID *uint) (Attendance, error)
+	IsDateLocked(ctx context.Context, date time.Time) (bool, error)
+	RecomputeTotals(ctx context.Context, from, to time.Time) (int64, error)
+	// AutoCheckOut menutup absensi yang tanggal kerjanya sudah lewat pada now,
+	// dihitung di timezone lokasi kerja masing-masing atau defaultTZ.
+	AutoCheckOut(ctx context.Context, now time.Time, at time.Duration, defaultTZ string) (int64, error)
 }
 
+// Semua timestamp (check-in/out default, created_at, updated_at) diambil dari
+// clock dan dikirim sebagai parameter, bukan NOW() database, supaya sama
+// dengan jam yang dipakai service.
 type AttendanceRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	clock clock.Clock
 }
 
-func NewAttendanceRepository() IAttendanceRepository {
-	return &AttendanceRepository{db: config.NewDB()}
+func NewAttendanceRepository(db *gorm.DB, clk clock.Clock) IAttendanceRepository {
+	return &AttendanceRepository{db: db, clock: clk}
 }
 
 const (
@@ -27,34 +48,39 @@ const (
 	qUpsertCheckIn = `
 WITH ins AS (
   INSERT INTO attendances
-    (user_id, date, check_in_at, check_in_lat, check_in_lng, check_in_photo_url, check_in_ip, status, activity, created_at, updated_at)
+    (tenant_id, user_id, work_location_id, date, check_in_at, check_in_lat, check_in_lng, check_in_photo_url, check_in_ip, check_in_kiosk_id, status, activity, created_at, updated_at)
   VALUES
-    (?, ?::date, NOW(), ?, ?, ?, ?, ?, ?, NOW(), NOW())
+    (?, ?, ?, ?::date, CAST(? AS timestamptz), ?, ?, ?, ?, ?, ?, ?, ?, ?)
   ON CONFLICT (user_id, date) DO NOTHING
   RETURNING
-    id, user_id, date,
-    check_in_at, check_in_lat, check_in_lng, check_in_photo_url, check_in_ip,
-    check_out_at, check_out_ip, total_minutes, status, activity,
+    id, user_id, work_location_id, date,
+    check_in_at, check_in_lat, check_in_lng, check_in_photo_url, check_in_ip, check_in_kiosk_id,
+    check_out_at, check_out_ip, check_out_kiosk_id, total_minutes, status, activity,
     created_at, updated_at,
     TRUE AS created
 ), upd AS (
   UPDATE attendances a
   SET
-    check_in_at        = COALESCE(a.check_in_at, NOW()), -- tidak overwrite kalau sudah ada
+    -- tidak overwrite kalau sudah ada, kecuali punch offline yang lebih awal (LEAST mengabaikan NULL)
+    check_in_at        = LEAST(a.check_in_at, CAST(? AS timestamptz)),
     check_in_lat       = COALESCE(?, a.check_in_lat),
     check_in_lng       = COALESCE(?, a.check_in_lng),
     check_in_photo_url = COALESCE(?, a.check_in_photo_url),
     check_in_ip        = COALESCE(?, a.check_in_ip),
-    status             = ?,   -- tetap set PRESENT
+    check_in_kiosk_id  = COALESCE(?, a.check_in_kiosk_id),
+    work_location_id   = COALESCE(?, a.work_location_id),
+    -- status hanya dihitung ulang kalau punch ini menjadi check-in paling awal (punch offline)
+    status             = CASE WHEN CAST(? AS timestamptz) < a.check_in_at THEN ? ELSE a.status END,
     activity           = ?,   -- update activity terakhir
-    updated_at         = NOW()
+    updated_at         = ?
   WHERE NOT EXISTS (SELECT 1 FROM ins)
+    AND a.tenant_id = ?
     AND a.user_id = ?
     AND a.date = ?::date
   RETURNING
-    id, user_id, date,
-    check_in_at, check_in_lat, check_in_lng, check_in_photo_url, check_in_ip,
-    check_out_at, check_out_ip, total_minutes, status, activity,
+    id, user_id, work_location_id, date,
+    check_in_at, check_in_lat, check_in_lng, check_in_photo_url, check_in_ip, check_in_kiosk_id,
+    check_out_at, check_out_ip, check_out_kiosk_id, total_minutes, status, activity,
     created_at, updated_at,
     FALSE AS created
 )
@@ -66,49 +92,125 @@ LIMIT 1;
 
 	qGetByUserAndDate = `
 SELECT
-  id, user_id, date,
-  check_in_at, check_in_lat, check_in_lng, check_in_photo_url, check_in_ip,
-  check_out_at, check_out_ip, total_minutes, status, activity,
+  id, user_id, work_location_id, date,
+  check_in_at, check_in_lat, check_in_lng, check_in_photo_url, check_in_ip, check_in_kiosk_id,
+  check_out_at, check_out_ip, check_out_kiosk_id, total_minutes, status, activity,
   created_at, updated_at
 FROM attendances
-WHERE user_id = ? AND date = ?::date
+WHERE tenant_id = ? AND user_id = ? AND date = ?::date
 LIMIT 1;
+`
+
+	qListByUserBetween = `
+SELECT
+  id, user_id, work_location_id, date,
+  check_in_at, check_in_lat, check_in_lng, check_in_photo_url, check_in_ip, check_in_kiosk_id,
+  check_out_at, check_out_ip, check_out_kiosk_id, total_minutes, status, activity,
+  created_at, updated_at
+FROM attendances
+WHERE tenant_id = ? AND user_id = ? AND date BETWEEN ?::date AND ?::date
+ORDER BY date DESC;
 `
 
 	// Check-out hanya kalau belum pernah check-out (check_out_at IS NULL)
-	// total_minutes dihitung dari (now - check_in_at) dalam menit, dibatasi >= 0.
+	// total_minutes dihitung dari (waktu check-out - check_in_at) dalam menit, dibatasi >= 0.
+	// Waktu check-out = sekarang kecuali dikirim eksplisit (punch offline).
 	qCheckOut = `
 UPDATE attendances a
 SET
-  check_out_at = NOW(),
+  check_out_at = CAST(? AS timestamptz),
   check_out_ip = ?,
+  check_out_kiosk_id = ?,
   total_minutes = CASE
                     WHEN a.check_in_at IS NULL THEN a.total_minutes
-                    ELSE GREATEST(0, a.total_minutes + CAST(EXTRACT(EPOCH FROM (NOW() - a.check_in_at))/60 AS INT))
+                    ELSE GREATEST(0, a.total_minutes + CAST(EXTRACT(EPOCH FROM (CAST(? AS timestamptz) - a.check_in_at))/60 AS INT))
                   END,
-  updated_at = NOW()
-WHERE a.user_id = ? AND a.date = ?::date AND a.check_out_at IS NULL
+  updated_at = ?
+WHERE a.tenant_id = ? AND a.user_id = ? AND a.date = ?::date AND a.check_out_at IS NULL
 RETURNING
-  id, user_id, date,
-  check_in_at, check_in_lat, check_in_lng, check_in_photo_url, check_in_ip,
-  check_out_at, check_out_ip, total_minutes, status, activity,
+  id, user_id, work_location_id, date,
+  check_in_at, check_in_lat, check_in_lng, check_in_photo_url, check_in_ip, check_in_kiosk_id,
+  check_out_at, check_out_ip, check_out_kiosk_id, total_minutes, status, activity,
   created_at, updated_at;
+`
+
+	// Hitung ulang total_minutes dari check_in_at/check_out_at. Tanggal di
+	// periode payroll LOCKED tidak disentuh.
+	qRecomputeTotals = `
+UPDATE attendances a
+SET
+  total_minutes = GREATEST(0, CAST(EXTRACT(EPOCH FROM (a.check_out_at - a.check_in_at))/60 AS INT)),
+  updated_at    = ?
+WHERE a.tenant_id = ?
+  AND a.date BETWEEN ?::date AND ?::date
+  AND a.check_in_at IS NOT NULL
+  AND a.check_out_at IS NOT NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM payroll_periods p
+    WHERE p.tenant_id = a.tenant_id AND p.status = 'LOCKED' AND a.date BETWEEN p.period_start AND p.period_end
+  );
+`
+
+	// Tutup absensi yang lupa check-out sebelum tanggal kerja hari ini. Jam
+	// check-out = tanggal kerja + offset, tidak lebih awal dari check-in.
+	// "Hari ini" dan jam check-out dihitung di timezone lokasi kerja absensi
+	// (atau timezone perusahaan), jadi lokasi di zona berbeda ditutup sesuai jam lokalnya.
+	qAutoCheckOut = `
+UPDATE attendances a
+SET
+  check_out_at  = x.out_at,
+  total_minutes = GREATEST(0, a.total_minutes + CAST(EXTRACT(EPOCH FROM (x.out_at - a.check_in_at))/60 AS INT)),
+  updated_at    = @now
+FROM (
+  SELECT t.id, GREATEST(t.check_in_at, (t.date + make_interval(secs => @at)) AT TIME ZONE z.tz) AS out_at
+  FROM attendances t
+  LEFT JOIN work_locations w ON w.id = t.work_location_id AND w.tenant_id = t.tenant_id
+  CROSS JOIN LATERAL (SELECT COALESCE(NULLIF(w.timezone, ''), @tz) AS tz) z
+  WHERE t.tenant_id = @tenant
+    AND t.date < CAST((CAST(@now AS timestamptz) AT TIME ZONE z.tz) AS date)
+    AND t.check_in_at IS NOT NULL
+    AND t.check_out_at IS NULL
+) x
+WHERE a.id = x.id
+  AND NOT EXISTS (
+    SELECT 1 FROM payroll_periods p
+    WHERE p.tenant_id = a.tenant_id AND p.status = 'LOCKED' AND a.date BETWEEN p.period_start AND p.period_end
+  );
+`
+	// Tanggal yang sudah masuk periode payroll LOCKED tidak boleh diubah lagi
+	qIsDateLocked = `
+SELECT EXISTS (
+  SELECT 1 FROM payroll_periods
+  WHERE tenant_id = ? AND status = 'LOCKED' AND ?::date BETWEEN period_start AND period_end
+);
 `
 )
 
-func (r *AttendanceRepository) UpsertCheckIn(ctx context.Context, a Attendance) (Attendance, bool, error) {
+func (r *AttendanceRepository) UpsertCheckIn(ctx context.Context, a Attendance) (out Attendance, created bool, err error) {
+	ctx, span := tracer.Start(ctx, "AttendanceRepository.UpsertCheckIn", trace.WithAttributes(attribute.Int64("user.id", int64(a.UserID))))
+	defer tracing.End(span, &err)
+
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Attendance{}, false, err
+	}
 	var row struct {
 		Attendance
 		Created bool `gorm:"column:created"`
 	}
 
 	dateStr := a.Date.Format("2006-01-02")
+	now := r.clock.Now()
+	at := now
+	if a.CheckInAt != nil {
+		at = *a.CheckInAt
+	}
 	res := r.db.WithContext(ctx).Raw(
 		qUpsertCheckIn,
 		// INS args
-		a.UserID, dateStr, a.CheckInLat, a.CheckInLng, a.CheckInPhotoURL, a.CheckInIP, a.Status, a.Activity,
-		// UPD args
-		a.CheckInLat, a.CheckInLng, a.CheckInPhotoURL, a.CheckInIP, a.Status, a.Activity, a.UserID, dateStr,
+		tid, a.UserID, a.WorkLocationID, dateStr, at, a.CheckInLat, a.CheckInLng, a.CheckInPhotoURL, a.CheckInIP, a.CheckInKioskID, a.Status, a.Activity, now, now,
+		// UPD args; status memakai a.CheckInAt asli: NULL = bukan punch offline, status lama dipertahankan
+		at, a.CheckInLat, a.CheckInLng, a.CheckInPhotoURL, a.CheckInIP, a.CheckInKioskID, a.WorkLocationID, a.CheckInAt, a.Status, a.Activity, now, tid, a.UserID, dateStr,
 	).Scan(&row)
 
 	if res.Error != nil {
@@ -117,9 +219,15 @@ func (r *AttendanceRepository) UpsertCheckIn(ctx context.Context, a Attendance)
 	return row.Attendance, row.Created, nil
 }
 
-func (r *AttendanceRepository) GetByUserAndDate(ctx context.Context, userID uint, date time.Time) (Attendance, error) {
-	var out Attendance
-	res := r.db.WithContext(ctx).Raw(qGetByUserAndDate, userID, date.Format("2006-01-02")).Scan(&out)
+func (r *AttendanceRepository) GetByUserAndDate(ctx context.Context, userID uint, date time.Time) (out Attendance, err error) {
+	ctx, span := tracer.Start(ctx, "AttendanceRepository.GetByUserAndDate", trace.WithAttributes(attribute.Int64("user.id", int64(userID))))
+	defer tracing.End(span, &err)
+
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Attendance{}, err
+	}
+	res := r.db.WithContext(ctx).Raw(qGetByUserAndDate, tid, userID, date.Format("2006-01-02")).Scan(&out)
 	if res.Error != nil {
 		return Attendance{}, res.Error
 	}
@@ -129,11 +237,34 @@ func (r *AttendanceRepository) GetByUserAndDate(ctx context.Context, userID uint
 	return out, nil
 }
 
-func (r *AttendanceRepository) CheckOut(ctx context.Context, userID uint, date time.Time, ip *string) (Attendance, error) {
-	var out Attendance
+func (r *AttendanceRepository) ListByUserBetween(ctx context.Context, userID uint, from, to time.Time) (out []Attendance, err error) {
+	ctx, span := tracer.Start(ctx, "AttendanceRepository.ListByUserBetween", trace.WithAttributes(attribute.Int64("user.id", int64(userID))))
+	defer tracing.End(span, &err)
+
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.WithContext(ctx).Raw(qListByUserBetween, tid, userID, from.Format("2006-01-02"), to.Format("2006-01-02")).Scan(&out).Error
+	return out, err
+}
+
+func (r *AttendanceRepository) CheckOut(ctx context.Context, userID uint, date time.Time, at *time.Time, ip *string, kioskID *uint) (out Attendance, err error) {
+	ctx, span := tracer.Start(ctx, "AttendanceRepository.CheckOut", trace.WithAttributes(attribute.Int64("user.id", int64(userID))))
+	defer tracing.End(span, &err)
+
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Attendance{}, err
+	}
 	dateStr := date.Format("2006-01-02")
+	now := r.clock.Now()
+	outAt := now
+	if at != nil {
+		outAt = *at
+	}
 
-	res := r.db.WithContext(ctx).Raw(qCheckOut, ip, userID, dateStr).Scan(&out)
+	res := r.db.WithContext(ctx).Raw(qCheckOut, outAt, ip, kioskID, outAt, now, tid, userID, dateStr).Scan(&out)
 	if res.Error != nil {
 		return Attendance{}, res.Error
 	}
@@ -143,3 +274,44 @@ func (r *AttendanceRepository) CheckOut(ctx context.Context, userID uint, date t
 	}
 	return out, nil
 }
+
+func (r *AttendanceRepository) IsDateLocked(ctx context.Context, date time.Time) (locked bool, err error) {
+	ctx, span := tracer.Start(ctx, "AttendanceRepository.IsDateLocked")
+	defer tracing.End(span, &err)
+
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return false, err
+	}
+	err = r.db.WithContext(ctx).Raw(qIsDateLocked, tid, date.Format("2006-01-02")).Scan(&locked).Error
+	return locked, err
+}
+
+func (r *AttendanceRepository) RecomputeTotals(ctx context.Context, from, to time.Time) (n int64, err error) {
+	ctx, span := tracer.Start(ctx, "AttendanceRepository.RecomputeTotals")
+	defer tracing.End(span, &err)
+
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	res := r.db.WithContext(ctx).Exec(qRecomputeTotals, r.clock.Now(), tid, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	return res.RowsAffected, res.Error
+}
+
+func (r *AttendanceRepository) AutoCheckOut(ctx context.Context, now time.Time, at time.Duration, defaultTZ string) (n int64, err error) {
+	ctx, span := tracer.Start(ctx, "AttendanceRepository.AutoCheckOut")
+	defer tracing.End(span, &err)
+
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	res := r.db.WithContext(ctx).Exec(qAutoCheckOut, map[string]any{
+		"now":    now,
+		"at":     at.Seconds(),
+		"tz":     defaultTZ,
+		"tenant": tid,
+	})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,279 @@
+//go:build integration
+
+package attedance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mojo-autotech/clock"
+	"mojo-autotech/tenant"
+	"mojo-autotech/testdb"
+)
+
+var wib = time.FixedZone("WIB", 7*3600)
+
+func at(s string) *time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, wib)
+	if err != nil {
+		panic(err)
+	}
+	return &t
+}
+
+func day(s string) time.Time { return *at(s + " 00:00") }
+
+// newRepo: repository di database baru dengan jam palsu pada 2026-03-02 12:00 WIB.
+func newRepo(t *testing.T) (IAttendanceRepository, *gorm.DB, *clock.Fake) {
+	db := testdb.New(t)
+	clk := clock.NewFake(*at("2026-03-02 12:00"))
+	return NewAttendanceRepository(db, clk), db, clk
+}
+
+func TestUpsertCheckIn(t *testing.T) {
+	repo, _, _ := newRepo(t)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	first, created, err := repo.UpsertCheckIn(ctx, Attendance{
+		UserID: 1, Date: day("2026-03-02"), CheckInAt: at("2026-03-02 08:30"), Status: StatusLate, Activity: "servis",
+	})
+	if err != nil || !created {
+		t.Fatalf("insert: created=%v err=%v", created, err)
+	}
+
+	// check-in ulang yang lebih siang tidak memajukan check_in_at maupun status
+	ip := "10.0.0.7"
+	second, created, err := repo.UpsertCheckIn(ctx, Attendance{
+		UserID: 1, Date: day("2026-03-02"), CheckInAt: at("2026-03-02 10:00"), CheckInIP: &ip, Status: StatusLate, Activity: "spooring",
+	})
+	if err != nil || created {
+		t.Fatalf("update: created=%v err=%v", created, err)
+	}
+	if second.ID != first.ID || !second.CheckInAt.Equal(*first.CheckInAt) {
+		t.Fatalf("check_in_at = %v, mau tetap %v", second.CheckInAt, first.CheckInAt)
+	}
+	if second.Activity != "spooring" || second.CheckInIP == nil {
+		t.Fatalf("activity/ip tidak diperbarui: %+v", second)
+	}
+
+	// punch offline yang lebih awal memundurkan check_in_at dan menghitung ulang status
+	third, _, err := repo.UpsertCheckIn(ctx, Attendance{
+		UserID: 1, Date: day("2026-03-02"), CheckInAt: at("2026-03-02 07:50"), Status: StatusPresent, Activity: "spooring",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !third.CheckInAt.Equal(*at("2026-03-02 07:50")) || third.Status != StatusPresent {
+		t.Fatalf("punch offline lebih awal: check_in_at=%v status=%s", third.CheckInAt, third.Status)
+	}
+	if third.CheckInIP == nil {
+		t.Fatal("COALESCE harus mempertahankan check_in_ip lama")
+	}
+}
+
+// Tanpa waktu punch, check-in/check-out dan updated_at memakai clock repository.
+func TestTimestampsFromClock(t *testing.T) {
+	repo, _, clk := newRepo(t)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	in, _, err := repo.UpsertCheckIn(ctx, Attendance{UserID: 9, Date: day("2026-03-02"), Status: StatusPresent, Activity: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !in.CheckInAt.Equal(clk.Now()) || !in.CreatedAt.Equal(clk.Now()) {
+		t.Fatalf("check_in_at=%v created_at=%v, mau %v", in.CheckInAt, in.CreatedAt, clk.Now())
+	}
+
+	clk.Advance(4 * time.Hour)
+	out, err := repo.CheckOut(ctx, 9, day("2026-03-02"), nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !out.CheckOutAt.Equal(clk.Now()) || !out.UpdatedAt.Equal(clk.Now()) || out.TotalMinutes != 240 {
+		t.Fatalf("check-out = %+v, mau check_out_at %v dan total 240", out, clk.Now())
+	}
+}
+
+func TestCheckOutTotals(t *testing.T) {
+	repo, _, _ := newRepo(t)
+	ctx := tenant.WithID(context.Background(), 1)
+	date := day("2026-03-02")
+
+	if _, err := repo.CheckOut(ctx, 2, date, at("2026-03-02 17:00"), nil, nil); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("check-out tanpa check-in: err = %v", err)
+	}
+	if _, _, err := repo.UpsertCheckIn(ctx, Attendance{UserID: 2, Date: date, CheckInAt: at("2026-03-02 08:00"), Status: StatusPresent, Activity: "x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	ip := "192.168.1.20"
+	out, err := repo.CheckOut(ctx, 2, date, at("2026-03-02 16:30"), &ip, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.TotalMinutes != 510 || out.CheckOutIP == nil {
+		t.Fatalf("check-out = %+v, mau total 510 menit dan ip tersimpan", out)
+	}
+
+	if _, err := repo.CheckOut(ctx, 2, date, at("2026-03-02 18:00"), nil, nil); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("check-out kedua: err = %v, mau ErrRecordNotFound", err)
+	}
+
+	got, err := repo.GetByUserAndDate(ctx, 2, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.CheckOutAt.Equal(*at("2026-03-02 16:30")) {
+		t.Fatalf("check_out_at = %v, tidak boleh tertimpa", got.CheckOutAt)
+	}
+}
+
+func TestLockedPeriod(t *testing.T) {
+	repo, db, _ := newRepo(t)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	if err := db.Exec(`INSERT INTO payroll_periods (tenant_id, period_start, period_end, status) VALUES (1, '2026-02-01', '2026-02-28', 'LOCKED')`).Error; err != nil {
+		t.Fatal(err)
+	}
+	for date, want := range map[string]bool{"2026-01-31": false, "2026-02-01": true, "2026-02-28": true, "2026-03-01": false} {
+		got, err := repo.IsDateLocked(ctx, day(date))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("IsDateLocked(%s) = %v, mau %v", date, got, want)
+		}
+	}
+
+	// absensi di periode LOCKED tidak ikut ditutup AutoCheckOut
+	for _, d := range []string{"2026-02-27", "2026-03-02"} {
+		if _, _, err := repo.UpsertCheckIn(ctx, Attendance{UserID: 3, Date: day(d), CheckInAt: at(d + " 08:00"), Status: StatusPresent, Activity: "x"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	n, err := repo.AutoCheckOut(ctx, *at("2026-03-03 01:00"), 17*time.Hour, "Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("ditutup %d, mau 1 (hanya 2026-03-02)", n)
+	}
+	feb, _ := repo.GetByUserAndDate(ctx, 3, day("2026-02-27"))
+	if feb.CheckOutAt != nil {
+		t.Fatal("absensi di periode LOCKED ikut ditutup")
+	}
+}
+
+// Setiap query dibatasi tenant di ctx; tanpa tenant repository menolak.
+func TestTenantIsolation(t *testing.T) {
+	repo, db, _ := newRepo(t)
+	t1 := tenant.WithID(context.Background(), 1)
+	var tid uint
+	if err := db.Raw(`INSERT INTO tenants (code, name, created_at, updated_at) VALUES ('bengkel-b', 'Bengkel B', NOW(), NOW()) RETURNING id`).Scan(&tid).Error; err != nil {
+		t.Fatal(err)
+	}
+	t2 := tenant.WithID(context.Background(), tid)
+
+	if _, _, err := repo.UpsertCheckIn(t1, Attendance{UserID: 1, Date: day("2026-03-02"), CheckInAt: at("2026-03-02 08:00"), Status: StatusPresent, Activity: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetByUserAndDate(t2, 1, day("2026-03-02")); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("baca lintas tenant: err = %v, mau ErrRecordNotFound", err)
+	}
+	if _, err := repo.CheckOut(t2, 1, day("2026-03-02"), at("2026-03-02 17:00"), nil, nil); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("check-out lintas tenant: err = %v, mau ErrRecordNotFound", err)
+	}
+
+	// periode LOCKED tenant lain tidak mengunci tanggal tenant 1
+	if err := db.Exec(`INSERT INTO payroll_periods (tenant_id, period_start, period_end, status) VALUES (?, '2026-03-01', '2026-03-31', 'LOCKED')`, tid).Error; err != nil {
+		t.Fatal(err)
+	}
+	if locked, err := repo.IsDateLocked(t1, day("2026-03-02")); err != nil || locked {
+		t.Fatalf("IsDateLocked tenant 1 = %v, %v; mau false", locked, err)
+	}
+
+	if _, err := repo.GetByUserAndDate(context.Background(), 1, day("2026-03-02")); !errors.Is(err, tenant.ErrMissing) {
+		t.Fatalf("tanpa tenant: err = %v, mau tenant.ErrMissing", err)
+	}
+}
+
+// Jam check-out otomatis dihitung di timezone kantor: 17:00 WIB = 10:00 UTC.
+func TestAutoCheckOutTimezone(t *testing.T) {
+	repo, _, _ := newRepo(t)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	if _, _, err := repo.UpsertCheckIn(ctx, Attendance{UserID: 4, Date: day("2026-03-02"), CheckInAt: at("2026-03-02 08:00"), Status: StatusPresent, Activity: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	// check-in setelah jam pulang: check-out disamakan dengan check-in
+	if _, _, err := repo.UpsertCheckIn(ctx, Attendance{UserID: 5, Date: day("2026-03-02"), CheckInAt: at("2026-03-02 19:00"), Status: StatusLate, Activity: "x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.AutoCheckOut(ctx, *at("2026-03-03 01:00"), 17*time.Hour, "Asia/Jakarta"); err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := repo.GetByUserAndDate(ctx, 4, day("2026-03-02"))
+	if a.CheckOutAt == nil || !a.CheckOutAt.Equal(time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)) || a.TotalMinutes != 540 {
+		t.Fatalf("user 4: check_out_at=%v total=%d, mau 10:00 UTC / 540", a.CheckOutAt, a.TotalMinutes)
+	}
+	b, _ := repo.GetByUserAndDate(ctx, 5, day("2026-03-02"))
+	if b.CheckOutAt == nil || !b.CheckOutAt.Equal(*b.CheckInAt) || b.TotalMinutes != 0 {
+		t.Fatalf("user 5: check_out_at=%v total=%d, mau sama dengan check-in / 0", b.CheckOutAt, b.TotalMinutes)
+	}
+}
+
+// Lokasi dengan timezone sendiri ditutup begitu tanggal kerjanya lewat di
+// timezone lokasi, walau di timezone perusahaan masih hari yang sama.
+func TestAutoCheckOutLocationTimezone(t *testing.T) {
+	repo, db, _ := newRepo(t)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	var locID uint
+	if err := db.Raw(`INSERT INTO work_locations (tenant_id, name, timezone) VALUES (1, 'Makassar', 'Asia/Makassar') RETURNING id`).Scan(&locID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := repo.UpsertCheckIn(ctx, Attendance{UserID: 6, Date: day("2026-03-02"), CheckInAt: at("2026-03-02 08:00"), Status: StatusPresent, Activity: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := repo.UpsertCheckIn(ctx, Attendance{UserID: 7, WorkLocationID: &locID, Date: day("2026-03-02"), CheckInAt: at("2026-03-02 08:00"), Status: StatusPresent, Activity: "x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 23:30 WIB = 00:30 WITA 2026-03-03
+	n, err := repo.AutoCheckOut(ctx, *at("2026-03-02 23:30"), 17*time.Hour, "Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("ditutup %d, mau 1 (hanya lokasi WITA)", n)
+	}
+	wita, _ := repo.GetByUserAndDate(ctx, 7, day("2026-03-02"))
+	// 17:00 WITA = 09:00 UTC
+	if wita.CheckOutAt == nil || !wita.CheckOutAt.Equal(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)) {
+		t.Fatalf("check_out_at WITA = %v, mau 09:00 UTC", wita.CheckOutAt)
+	}
+}
+
+func TestListByUserBetween(t *testing.T) {
+	repo, _, _ := newRepo(t)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	for _, d := range []string{"2026-03-01", "2026-03-02", "2026-03-03", "2026-03-05"} {
+		if _, _, err := repo.UpsertCheckIn(ctx, Attendance{UserID: 8, Date: day(d), CheckInAt: at(d + " 08:00"), Status: StatusPresent, Activity: "x"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := repo.ListByUserBetween(ctx, 8, day("2026-03-02"), day("2026-03-04"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Date.Format("2006-01-02") != "2026-03-03" || got[1].Date.Format("2006-01-02") != "2026-03-02" {
+		t.Fatalf("absensi = %+v, mau 2026-03-03 lalu 2026-03-02", got)
+	}
+}
@@ -0,0 +1,29 @@
+package idempotency
+
+import "time"
+
+// Record adalah satu Idempotency-Key milik scope (user atau IP) di satu
+// tenant. StatusCode 0 berarti request pertama masih diproses.
+type Record struct {
+	TenantID    uint      `json:"-"            gorm:"primaryKey"`
+	Scope       string    `json:"scope"        gorm:"primaryKey;size:80"`
+	Key         string    `json:"key"          gorm:"primaryKey;size:255"`
+	RequestHash string    `json:"request_hash" gorm:"size:64"`
+	StatusCode  int       `json:"status_code"`
+	ContentType string    `json:"content_type"`
+	Body        []byte    `json:"body"`
+	CreatedAt   time.Time `json:"created_at"   gorm:"type:timestamptz"`
+	ExpiresAt   time.Time `json:"expires_at"   gorm:"type:timestamptz"`
+}
+
+func (Record) TableName() string { return "idempotency_keys" }
+
+// Pending true kalau request pertama belum selesai.
+func (r Record) Pending() bool { return r.StatusCode == 0 }
+
+// Response yang disimpan untuk di-replay.
+type Response struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
@@ -0,0 +1,122 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mojo-autotech/clock"
+	"mojo-autotech/tenant"
+)
+
+// Reserve, Complete dan Release hanya melihat key di tenant dari context;
+// DeleteExpired membersihkan semua tenant.
+type IIdempotencyRepository interface {
+	// Reserve mengklaim key untuk request dengan hash tersebut selama lock.
+	// reserved=false berarti key sudah dipakai dan belum kedaluwarsa; existing
+	// berisi record tersebut (bisa masih pending). existing kosong dengan
+	// reserved=false terjadi kalau record pemenang dilepas di antara dua query;
+	// perlakukan seperti pending.
+	Reserve(ctx context.Context, scope, key, hash string, lock time.Duration) (existing Record, reserved bool, err error)
+	// Complete menyimpan response request yang sudah di-Reserve untuk replay selama ttl.
+	Complete(ctx context.Context, scope, key string, res Response, ttl time.Duration) error
+	// Release menghapus key supaya request boleh diulang dari awal (mis. 5xx).
+	Release(ctx context.Context, scope, key string) error
+	// DeleteExpired menghapus key yang sudah lewat expires_at.
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type IdempotencyRepository struct {
+	db    *gorm.DB
+	clock clock.Clock
+}
+
+func NewIdempotencyRepository(db *gorm.DB, clk clock.Clock) IIdempotencyRepository {
+	return &IdempotencyRepository{db: db, clock: clk}
+}
+
+const (
+	// Baris yang sudah kedaluwarsa (termasuk lock yang ditinggal) diambil alih;
+	// baris yang masih hidup tidak tersentuh sehingga RETURNING kosong.
+	qReserve = `
+INSERT INTO idempotency_keys (tenant_id, scope, key, request_hash, status_code, created_at, expires_at)
+VALUES (@tenant, @scope, @key, @hash, 0, @now, @expires)
+ON CONFLICT (tenant_id, scope, key) DO UPDATE SET
+  request_hash = EXCLUDED.request_hash,
+  status_code  = 0,
+  content_type = NULL,
+  body         = NULL,
+  created_at   = EXCLUDED.created_at,
+  expires_at   = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+RETURNING scope;
+`
+
+	qGet = `
+SELECT tenant_id, scope, key, request_hash, status_code, content_type, body, created_at, expires_at
+FROM idempotency_keys
+WHERE tenant_id = ? AND scope = ? AND key = ?;
+`
+
+	qComplete = `
+UPDATE idempotency_keys
+SET status_code = ?, content_type = ?, body = ?, expires_at = ?
+WHERE tenant_id = ? AND scope = ? AND key = ?;
+`
+
+	qRelease = `DELETE FROM idempotency_keys WHERE tenant_id = ? AND scope = ? AND key = ?;`
+
+	qDeleteExpired = `DELETE FROM idempotency_keys WHERE expires_at <= ?;`
+)
+
+func (r *IdempotencyRepository) Reserve(ctx context.Context, scope, key, hash string, lock time.Duration) (Record, bool, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Record{}, false, err
+	}
+	now := r.clock.Now()
+	var got []string
+	err = r.db.WithContext(ctx).Raw(qReserve, map[string]any{
+		"tenant":  tid,
+		"scope":   scope,
+		"key":     key,
+		"hash":    hash,
+		"now":     now,
+		"expires": now.Add(lock),
+	}).Scan(&got).Error
+	if err != nil {
+		return Record{}, false, err
+	}
+	if len(got) > 0 {
+		return Record{}, true, nil
+	}
+
+	var existing Record
+	if err := r.db.WithContext(ctx).Raw(qGet, tid, scope, key).Scan(&existing).Error; err != nil {
+		return Record{}, false, err
+	}
+	return existing, false, nil
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, scope, key string, res Response, ttl time.Duration) error {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	expires := r.clock.Now().Add(ttl)
+	return r.db.WithContext(ctx).Exec(qComplete, res.StatusCode, res.ContentType, res.Body, expires, tid, scope, key).Error
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, scope, key string) error {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Exec(qRelease, tid, scope, key).Error
+}
+
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	res := r.db.WithContext(ctx).Exec(qDeleteExpired, r.clock.Now())
+	return res.RowsAffected, res.Error
+}
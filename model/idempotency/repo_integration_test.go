@@ -0,0 +1,108 @@
+//go:build integration
+
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mojo-autotech/clock"
+	"mojo-autotech/tenant"
+	"mojo-autotech/testdb"
+)
+
+func TestReserveCompleteReplay(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC))
+	repo := NewIdempotencyRepository(testdb.New(t), clk)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	if _, ok, err := repo.Reserve(ctx, "user:7", "k1", "hash-a", time.Minute); err != nil || !ok {
+		t.Fatalf("reserve pertama: reserved=%v err=%v", ok, err)
+	}
+	got, ok, err := repo.Reserve(ctx, "user:7", "k1", "hash-a", time.Minute)
+	if err != nil || ok || !got.Pending() || got.RequestHash != "hash-a" {
+		t.Fatalf("reserve saat pending: record=%+v reserved=%v err=%v", got, ok, err)
+	}
+	// scope lain tidak bentrok
+	if _, ok, err := repo.Reserve(ctx, "user:8", "k1", "hash-a", time.Minute); err != nil || !ok {
+		t.Fatalf("reserve user lain: reserved=%v err=%v", ok, err)
+	}
+
+	res := Response{StatusCode: 201, ContentType: "application/json; charset=utf-8", Body: []byte(`{"code":201}`)}
+	if err := repo.Complete(ctx, "user:7", "k1", res, 24*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	// lewat lock tapi masih dalam TTL: response tetap di-replay
+	clk.Advance(time.Hour)
+	got, ok, err = repo.Reserve(ctx, "user:7", "k1", "hash-b", time.Minute)
+	if err != nil || ok {
+		t.Fatalf("reserve setelah selesai: reserved=%v err=%v", ok, err)
+	}
+	if got.StatusCode != 201 || got.ContentType != res.ContentType || string(got.Body) != string(res.Body) || got.RequestHash != "hash-a" {
+		t.Fatalf("record tersimpan = %+v", got)
+	}
+}
+
+func TestReserveExpiredAndRelease(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC))
+	repo := NewIdempotencyRepository(testdb.New(t), clk)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	// lock yang ditinggal (mis. proses mati) diambil alih setelah kedaluwarsa
+	if _, ok, _ := repo.Reserve(ctx, "ip:10.0.0.1", "k1", "hash-a", time.Minute); !ok {
+		t.Fatal("reserve pertama gagal")
+	}
+	clk.Advance(time.Minute)
+	if _, ok, err := repo.Reserve(ctx, "ip:10.0.0.1", "k1", "hash-b", time.Minute); err != nil || !ok {
+		t.Fatalf("ambil alih lock kedaluwarsa: reserved=%v err=%v", ok, err)
+	}
+
+	if err := repo.Release(ctx, "ip:10.0.0.1", "k1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := repo.Reserve(ctx, "ip:10.0.0.1", "k1", "hash-c", time.Minute); err != nil || !ok {
+		t.Fatalf("reserve setelah release: reserved=%v err=%v", ok, err)
+	}
+
+	if _, ok, _ := repo.Reserve(ctx, "ip:10.0.0.1", "k2", "hash-a", time.Hour); !ok {
+		t.Fatal("reserve k2 gagal")
+	}
+	clk.Advance(time.Minute)
+	n, err := repo.DeleteExpired(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("DeleteExpired = %d, %v; mau 1 (k1)", n, err)
+	}
+}
+
+func TestReserveIsScopedPerTenant(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC))
+	db := testdb.New(t)
+	repo := NewIdempotencyRepository(db, clk)
+	var other uint
+	if err := db.Raw(`INSERT INTO tenants (code, name, created_at, updated_at) VALUES ('bengkel-b', 'Bengkel B', NOW(), NOW()) RETURNING id`).Scan(&other).Error; err != nil {
+		t.Fatal(err)
+	}
+	ctxA, ctxB := tenant.WithID(context.Background(), 1), tenant.WithID(context.Background(), other)
+
+	if _, ok, err := repo.Reserve(ctxA, "ip:10.0.0.1", "k1", "hash-a", time.Minute); err != nil || !ok {
+		t.Fatalf("reserve tenant 1: reserved=%v err=%v", ok, err)
+	}
+	if err := repo.Complete(ctxA, "ip:10.0.0.1", "k1", Response{StatusCode: 401}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	// scope dan key yang sama di tenant lain tidak melihat response tenant 1
+	if got, ok, err := repo.Reserve(ctxB, "ip:10.0.0.1", "k1", "hash-a", time.Minute); err != nil || !ok {
+		t.Fatalf("reserve tenant lain: record=%+v reserved=%v err=%v", got, ok, err)
+	}
+	if err := repo.Release(ctxB, "ip:10.0.0.1", "k1"); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok, err := repo.Reserve(ctxA, "ip:10.0.0.1", "k1", "hash-a", time.Minute); err != nil || ok || got.StatusCode != 401 || got.TenantID != 1 {
+		t.Fatalf("release tenant lain tidak boleh menghapus key tenant 1: record=%+v reserved=%v err=%v", got, ok, err)
+	}
+	if _, _, err := repo.Reserve(context.Background(), "ip:10.0.0.1", "k1", "hash-a", time.Minute); !errors.Is(err, tenant.ErrMissing) {
+		t.Fatalf("tanpa tenant: err = %v, mau tenant.ErrMissing", err)
+	}
+}
@@ -0,0 +1,64 @@
+package kiosk
+
+import "time"
+
+// Kiosk adalah perangkat terdaftar di lantai workshop yang boleh mencatat
+// absensi atas nama karyawan.
+type Kiosk struct {
+	ID             uint       `json:"id"               gorm:"primaryKey"`
+	TenantID       uint       `json:"-"                gorm:"not null;uniqueIndex:idx_kiosks_tenant_code,priority:1"`
+	Code           string     `json:"code"             gorm:"size:40;uniqueIndex:idx_kiosks_tenant_code,priority:2;not null"`
+	Name           string     `json:"name"             gorm:"size:120"`
+	Location       string     `json:"location"         gorm:"size:120"` // label lokasi, mis. "Bengkel Cikarang - Bay 2"
+	WorkLocationID *uint      `json:"work_location_id"`
+	Lat            *float64   `json:"lat"`
+	Lng            *float64   `json:"lng"`
+	KeyHash        string     `json:"-"                gorm:"size:64;uniqueIndex;not null"` // sha256 hex dari device key
+	IsActive       bool       `json:"is_active"        gorm:"default:true"`
+	LastSeenAt     *time.Time `json:"last_seen_at"     gorm:"type:timestamptz"`
+	CreatedAt      time.Time  `json:"created_at"       gorm:"type:timestamptz"`
+	UpdatedAt      time.Time  `json:"updated_at"       gorm:"type:timestamptz"`
+}
+
+// Badge adalah data minimal karyawan untuk verifikasi badge + PIN di kiosk.
+type Badge struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	FullName string `json:"full_name"`
+	PinHash  string `json:"-"`
+	IsActive bool   `json:"is_active"`
+	// PinLockedUntil diisi saat PIN salah terlalu sering (config kiosk.pin_*)
+	PinLockedUntil *time.Time `json:"-"`
+}
+
+type RegisterKioskReq struct {
+	Code           string   `json:"code"     binding:"required,max=40"`
+	Name           string   `json:"name"     binding:"required"`
+	Location       string   `json:"location" binding:"omitempty,max=120"`
+	WorkLocationID *uint    `json:"work_location_id"`
+	Lat            *float64 `json:"lat"`
+	Lng            *float64 `json:"lng"`
+}
+
+type RegisterKioskRes struct {
+	Kiosk     Kiosk  `json:"kiosk"`
+	DeviceKey string `json:"device_key"` // hanya ditampilkan sekali, simpan di perangkat kiosk
+}
+
+type SetBadgeReq struct {
+	UserID  uint   `json:"user_id"  binding:"required"`
+	BadgeID string `json:"badge_id" binding:"required,max=40"`
+	PIN     string `json:"pin"      binding:"required,numeric,min=4,max=8"`
+}
+
+type PunchReq struct {
+	BadgeID  string `json:"badge_id" binding:"required"`
+	PIN      string `json:"pin"      binding:"required"`
+	Type     string `json:"type"     binding:"required,oneof=IN OUT"`
+	Activity string `json:"activity"`
+}
+
+type QRRes struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
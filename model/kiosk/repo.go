@@ -0,0 +1,184 @@
+package kiosk
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mojo-autotech/clock"
+	"mojo-autotech/tenant"
+)
+
+// Semua method kecuali GetByKeyHash hanya melihat data di tenant dari context.
+type IKioskRepository interface {
+	Create(ctx context.Context, k Kiosk) (Kiosk, error)
+	List(ctx context.Context) ([]Kiosk, error)
+	GetByID(ctx context.Context, id uint) (Kiosk, error)
+	// GetByKeyHash mencari di semua tenant: kiosk belum punya tenant sebelum
+	// device key-nya dikenali, dan key_hash unik global.
+	GetByKeyHash(ctx context.Context, keyHash string) (Kiosk, error)
+	Touch(ctx context.Context, id uint) error
+	GetBadge(ctx context.Context, badgeID string) (Badge, error)
+	// SetBadge juga mereset hitungan PIN salah dan membuka kunci badge.
+	SetBadge(ctx context.Context, userID uint, badgeID, pinHash string) error
+	// RecordFailedPIN menambah hitungan PIN salah; begitu mencapai maxFailed
+	// badge dikunci selama lockout dan hitungan dimulai lagi dari 0.
+	// Mengembalikan pin_locked_until terbaru (nil kalau belum pernah dikunci).
+	RecordFailedPIN(ctx context.Context, userID uint, maxFailed int, lockout time.Duration) (*time.Time, error)
+	// ResetFailedPIN mereset hitungan PIN salah setelah PIN benar.
+	ResetFailedPIN(ctx context.Context, userID uint) error
+}
+
+// Impl. Semua timestamp (last_seen_at, pin_locked_until, updated_at) diambil
+// dari clock seperti AuthRepository, supaya pin_locked_until dibandingkan
+// dengan jam yang sama di service.
+type KioskRepository struct {
+	db    *gorm.DB
+	clock clock.Clock
+}
+
+func NewKioskRepository(db *gorm.DB, clk clock.Clock) IKioskRepository {
+	return &KioskRepository{db: db, clock: clk}
+}
+
+const (
+	qTouchKiosk = `
+UPDATE kiosks SET last_seen_at = ? WHERE id = ? AND tenant_id = ?;
+`
+
+	qGetBadge = `
+SELECT id AS user_id, username, full_name, pin_hash, is_active, pin_locked_until
+FROM users
+WHERE tenant_id = ? AND badge_id = ? AND deleted_at IS NULL
+LIMIT 1;
+`
+
+	qSetBadge = `
+UPDATE users
+SET badge_id = ?, pin_hash = ?, pin_failed = 0, pin_locked_until = NULL, updated_at = ?
+WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL;
+`
+
+	qFailedPIN = `
+UPDATE users SET
+  pin_failed       = CASE WHEN @max > 0 AND pin_failed + 1 >= @max THEN 0 ELSE pin_failed + 1 END,
+  pin_locked_until = CASE WHEN @max > 0 AND pin_failed + 1 >= @max
+                          THEN CAST(@now AS timestamptz) + make_interval(secs => @lockout) ELSE pin_locked_until END,
+  updated_at       = @now
+WHERE id = @id AND tenant_id = @tenant
+RETURNING pin_locked_until;
+`
+
+	qResetFailedPIN = `
+UPDATE users SET pin_failed = 0, pin_locked_until = NULL
+WHERE id = ? AND tenant_id = ? AND (pin_failed <> 0 OR pin_locked_until IS NOT NULL);
+`
+)
+
+func (r *KioskRepository) Create(ctx context.Context, k Kiosk) (Kiosk, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Kiosk{}, err
+	}
+	k.TenantID = tid
+	if err := r.db.WithContext(ctx).Create(&k).Error; err != nil {
+		return Kiosk{}, err
+	}
+	return k, nil
+}
+
+func (r *KioskRepository) List(ctx context.Context) ([]Kiosk, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []Kiosk
+	err = r.db.WithContext(ctx).Where("tenant_id = ?", tid).Order("id").Find(&out).Error
+	return out, err
+}
+
+func (r *KioskRepository) GetByID(ctx context.Context, id uint) (Kiosk, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Kiosk{}, err
+	}
+	var out Kiosk
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", tid).First(&out, id).Error; err != nil {
+		return Kiosk{}, err
+	}
+	return out, nil
+}
+
+func (r *KioskRepository) GetByKeyHash(ctx context.Context, keyHash string) (Kiosk, error) {
+	var out Kiosk
+	if err := r.db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&out).Error; err != nil {
+		return Kiosk{}, err
+	}
+	return out, nil
+}
+
+func (r *KioskRepository) Touch(ctx context.Context, id uint) error {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Exec(qTouchKiosk, r.clock.Now(), id, tid).Error
+}
+
+func (r *KioskRepository) GetBadge(ctx context.Context, badgeID string) (Badge, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Badge{}, err
+	}
+	var out Badge
+	res := r.db.WithContext(ctx).Raw(qGetBadge, tid, badgeID).Scan(&out)
+	if res.Error != nil {
+		return Badge{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return Badge{}, gorm.ErrRecordNotFound
+	}
+	return out, nil
+}
+
+func (r *KioskRepository) SetBadge(ctx context.Context, userID uint, badgeID, pinHash string) error {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	res := r.db.WithContext(ctx).Exec(qSetBadge, badgeID, pinHash, r.clock.Now(), userID, tid)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *KioskRepository) RecordFailedPIN(ctx context.Context, userID uint, maxFailed int, lockout time.Duration) (*time.Time, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var row struct {
+		PinLockedUntil *time.Time
+	}
+	err = r.db.WithContext(ctx).Raw(qFailedPIN, map[string]any{
+		"max":     maxFailed,
+		"lockout": lockout.Seconds(),
+		"now":     r.clock.Now(),
+		"id":      userID,
+		"tenant":  tid,
+	}).Scan(&row).Error
+	return row.PinLockedUntil, err
+}
+
+func (r *KioskRepository) ResetFailedPIN(ctx context.Context, userID uint) error {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Exec(qResetFailedPIN, userID, tid).Error
+}
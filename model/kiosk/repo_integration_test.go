@@ -0,0 +1,68 @@
+//go:build integration
+
+package kiosk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mojo-autotech/clock"
+	"mojo-autotech/tenant"
+	"mojo-autotech/testdb"
+)
+
+func TestRecordFailedPIN(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC))
+	db := testdb.New(t)
+	repo := NewKioskRepository(db, clk)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	var userID uint
+	err := db.Raw(`
+INSERT INTO users (tenant_id, user_id, username, email, password_hash, role, is_active, created_at, updated_at)
+VALUES (1, 1, 'sari', 'sari@mojo.id', 'x', 'EMPLOYEE', TRUE, NOW(), NOW())
+RETURNING id`).Scan(&userID).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SetBadge(ctx, userID, "B-001", "hash"); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		until, err := repo.RecordFailedPIN(ctx, userID, 3, 15*time.Minute)
+		if err != nil || until != nil {
+			t.Fatalf("gagal ke-%d: pin_locked_until=%v err=%v", i, until, err)
+		}
+	}
+	until, err := repo.RecordFailedPIN(ctx, userID, 3, 15*time.Minute)
+	if err != nil || until == nil {
+		t.Fatalf("gagal ke-3 harus mengunci: pin_locked_until=%v err=%v", until, err)
+	}
+	if want := clk.Now().Add(15 * time.Minute); !until.Equal(want) {
+		t.Fatalf("pin_locked_until = %v, mau %v", until, want)
+	}
+	b, err := repo.GetBadge(ctx, "B-001")
+	if err != nil || b.PinLockedUntil == nil || !b.PinLockedUntil.Equal(*until) {
+		t.Fatalf("GetBadge setelah dikunci: %+v err=%v", b, err)
+	}
+
+	if err := repo.ResetFailedPIN(ctx, userID); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := repo.GetBadge(ctx, "B-001"); b.PinLockedUntil != nil {
+		t.Fatalf("ResetFailedPIN harus membuka kunci: %+v", b)
+	}
+
+	// PIN baru dari admin juga membuka kunci
+	if _, err := repo.RecordFailedPIN(ctx, userID, 1, 15*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SetBadge(ctx, userID, "B-001", "hash-baru"); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := repo.GetBadge(ctx, "B-001"); b.PinLockedUntil != nil || b.PinHash != "hash-baru" {
+		t.Fatalf("SetBadge harus membuka kunci: %+v", b)
+	}
+}
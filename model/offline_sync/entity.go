@@ -0,0 +1,102 @@
+package offline_sync
+
+import "time"
+
+const (
+	PunchApplied  = "APPLIED"  // sudah diterapkan ke tabel attendances
+	PunchFlagged  = "FLAGGED"  // skew/umur di luar toleransi, menunggu review admin
+	PunchRejected = "REJECTED" // ditolak (sudah check-out, ditolak admin, dll.); signature salah tidak disimpan
+)
+
+// Device adalah perangkat mobile yang boleh mengirim punch offline.
+// Secret dipakai sebagai kunci HMAC untuk signature tiap punch.
+type Device struct {
+	ID        uint      `json:"id"         gorm:"primaryKey"`
+	TenantID  uint      `json:"-"          gorm:"not null"`
+	UserID    uint      `json:"user_id"    gorm:"uniqueIndex:idx_sync_device,priority:1;not null"`
+	DeviceID  string    `json:"device_id"  gorm:"size:64;uniqueIndex:idx_sync_device,priority:2;not null"`
+	Secret    string    `json:"-"          gorm:"size:64;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamptz"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"type:timestamptz"`
+}
+
+func (Device) TableName() string { return "sync_devices" }
+
+// Punch adalah satu punch yang dicatat aplikasi saat offline. PunchID dibuat
+// client sehingga kiriman ulang tidak tercatat dua kali.
+type Punch struct {
+	ID           uint       `json:"id"            gorm:"primaryKey"`
+	TenantID     uint       `json:"-"             gorm:"not null"`
+	UserID       uint       `json:"user_id"       gorm:"uniqueIndex:idx_sync_punch,priority:1;not null"`
+	PunchID      string     `json:"punch_id"      gorm:"size:64;uniqueIndex:idx_sync_punch,priority:2;not null"`
+	DeviceID     string     `json:"device_id"     gorm:"size:64"`
+	Type         string     `json:"type"          gorm:"size:3"` // IN / OUT
+	DeviceTime   time.Time  `json:"device_time"   gorm:"type:timestamptz"`
+	EstimatedAt  *time.Time `json:"estimated_at"  gorm:"type:timestamptz"` // waktu punch menurut jam server (dari monotonic delta)
+	SkewSeconds  int64      `json:"skew_seconds"`
+	MonotonicMs  int64      `json:"monotonic_ms"`
+	Lat          *float64   `json:"lat"`
+	Lng          *float64   `json:"lng"`
+	Activity     string     `json:"activity"`
+	Status       string     `json:"status"        gorm:"size:20;index"`
+	Reason       string     `json:"reason"`
+	AttendanceID *uint      `json:"attendance_id"`
+	ReviewedBy   *uint      `json:"reviewed_by"`
+	ReviewedAt   *time.Time `json:"reviewed_at"   gorm:"type:timestamptz"`
+	ReceivedAt   time.Time  `json:"received_at"   gorm:"type:timestamptz"`
+	CreatedAt    time.Time  `json:"created_at"    gorm:"type:timestamptz"`
+	UpdatedAt    time.Time  `json:"updated_at"    gorm:"type:timestamptz"`
+}
+
+func (Punch) TableName() string { return "sync_punches" }
+
+type RegisterDeviceReq struct {
+	DeviceID string `json:"device_id" binding:"required,max=64"`
+}
+
+type RegisterDeviceRes struct {
+	DeviceID string `json:"device_id"`
+	Secret   string `json:"secret"` // hanya dikirim sekali, simpan di keystore perangkat
+}
+
+// SyncReq dikirim saat perangkat online kembali. SentMonotonicMs adalah jam
+// monotonic perangkat saat request dikirim; dipakai bersama MonotonicMs tiap
+// punch untuk mengestimasi waktu punch tanpa percaya jam dinding perangkat.
+type SyncReq struct {
+	DeviceID        string     `json:"device_id"         binding:"required"`
+	SentMonotonicMs int64      `json:"sent_monotonic_ms" binding:"required"`
+	Punches         []PunchReq `json:"punches"           binding:"required,min=1,max=200,dive"`
+}
+
+// PunchReq.Signature = hex(HMAC-SHA256(secret, canonical)) dengan canonical:
+//
+//	punch_id|type|device_time_unix_ms|monotonic_ms|lat|lng
+//
+// lat/lng ditulis dengan 6 desimal, atau string kosong kalau tidak ada.
+type PunchReq struct {
+	PunchID     string    `json:"punch_id"     binding:"required,max=64"`
+	Type        string    `json:"type"         binding:"required,oneof=IN OUT"`
+	DeviceTime  time.Time `json:"device_time"  binding:"required"`
+	MonotonicMs int64     `json:"monotonic_ms" binding:"required"`
+	Lat         *float64  `json:"lat"`
+	Lng         *float64  `json:"lng"`
+	Activity    string    `json:"activity"`
+	Signature   string    `json:"signature"    binding:"required"`
+}
+
+type PunchResult struct {
+	PunchID      string `json:"punch_id"`
+	Status       string `json:"status"`
+	Reason       string `json:"reason,omitempty"`
+	AttendanceID *uint  `json:"attendance_id,omitempty"`
+	Duplicate    bool   `json:"duplicate"` // true kalau punch_id sudah pernah diterima
+}
+
+type SyncRes struct {
+	Results []PunchResult `json:"results"`
+}
+
+type ReviewReq struct {
+	Approve bool   `json:"approve"`
+	Note    string `json:"note"`
+}
@@ -0,0 +1,153 @@
+package offline_sync
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"mojo-autotech/clock"
+	org "mojo-autotech/model/org_structure"
+	"mojo-autotech/tenant"
+)
+
+// Semua method hanya melihat data di tenant dari context.
+type IOfflineSyncRepository interface {
+	UpsertDevice(ctx context.Context, userID uint, deviceID, secret string) (Device, error)
+	GetDevice(ctx context.Context, userID uint, deviceID string) (Device, error)
+	InsertPunch(ctx context.Context, p Punch) (out Punch, inserted bool, err error)
+	GetPunch(ctx context.Context, userID uint, punchID string) (Punch, error)
+	GetPunchByID(ctx context.Context, id uint) (Punch, error)
+	// ListByStatus hanya mengembalikan punch milik user di scope.
+	ListByStatus(ctx context.Context, status string, scope org.Scope) ([]Punch, error)
+	SetResult(ctx context.Context, id uint, status, reason string, attendanceID *uint, reviewedBy *uint) error
+}
+
+// Timestamp (created_at, updated_at, reviewed_at) diambil dari clock yang
+// sama dengan service, bukan NOW() database.
+type OfflineSyncRepository struct {
+	db    *gorm.DB
+	clock clock.Clock
+}
+
+func NewOfflineSyncRepository(db *gorm.DB, clk clock.Clock) IOfflineSyncRepository {
+	return &OfflineSyncRepository{db: db, clock: clk}
+}
+
+const (
+	// Daftar ulang device yang sama = rotasi secret
+	qUpsertDevice = `
+INSERT INTO sync_devices (tenant_id, user_id, device_id, secret, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (user_id, device_id) DO UPDATE
+SET secret = EXCLUDED.secret, updated_at = EXCLUDED.updated_at
+WHERE sync_devices.tenant_id = EXCLUDED.tenant_id
+RETURNING *;
+`
+
+	// Idempoten per (user_id, punch_id): kiriman ulang tidak menghasilkan baris baru
+	qInsertPunch = `
+INSERT INTO sync_punches
+  (tenant_id, user_id, punch_id, device_id, type, device_time, estimated_at, skew_seconds, monotonic_ms,
+   lat, lng, activity, status, reason, received_at, created_at, updated_at)
+VALUES
+  (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (user_id, punch_id) DO NOTHING
+RETURNING *;
+`
+
+	qSetPunchResult = `
+UPDATE sync_punches
+SET
+  status        = ?,
+  reason        = ?,
+  attendance_id = COALESCE(?, attendance_id),
+  reviewed_by   = COALESCE(?, reviewed_by),
+  reviewed_at   = CASE WHEN ?::bigint IS NULL THEN reviewed_at ELSE CAST(? AS timestamptz) END,
+  updated_at    = ?
+WHERE id = ? AND tenant_id = ?;
+`
+)
+
+func (r *OfflineSyncRepository) UpsertDevice(ctx context.Context, userID uint, deviceID, secret string) (Device, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Device{}, err
+	}
+	now := r.clock.Now()
+	var out Device
+	err = r.db.WithContext(ctx).Raw(qUpsertDevice, tid, userID, deviceID, secret, now, now).Scan(&out).Error
+	return out, err
+}
+
+func (r *OfflineSyncRepository) GetDevice(ctx context.Context, userID uint, deviceID string) (Device, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Device{}, err
+	}
+	var out Device
+	err = r.db.WithContext(ctx).Where("tenant_id = ? AND user_id = ? AND device_id = ?", tid, userID, deviceID).First(&out).Error
+	return out, err
+}
+
+func (r *OfflineSyncRepository) InsertPunch(ctx context.Context, p Punch) (Punch, bool, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Punch{}, false, err
+	}
+	now := r.clock.Now()
+	var out Punch
+	res := r.db.WithContext(ctx).Raw(qInsertPunch,
+		tid, p.UserID, p.PunchID, p.DeviceID, p.Type, p.DeviceTime, p.EstimatedAt, p.SkewSeconds, p.MonotonicMs,
+		p.Lat, p.Lng, p.Activity, p.Status, p.Reason, p.ReceivedAt, now, now,
+	).Scan(&out)
+	if res.Error != nil {
+		return Punch{}, false, res.Error
+	}
+	return out, res.RowsAffected > 0, nil
+}
+
+func (r *OfflineSyncRepository) GetPunch(ctx context.Context, userID uint, punchID string) (Punch, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Punch{}, err
+	}
+	var out Punch
+	err = r.db.WithContext(ctx).Where("tenant_id = ? AND user_id = ? AND punch_id = ?", tid, userID, punchID).First(&out).Error
+	return out, err
+}
+
+func (r *OfflineSyncRepository) GetPunchByID(ctx context.Context, id uint) (Punch, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Punch{}, err
+	}
+	var out Punch
+	err = r.db.WithContext(ctx).Where("tenant_id = ?", tid).First(&out, id).Error
+	return out, err
+}
+
+func (r *OfflineSyncRepository) ListByStatus(ctx context.Context, status string, scope org.Scope) ([]Punch, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.All && len(scope.UserIDs) == 0 {
+		return []Punch{}, nil
+	}
+	var out []Punch
+	q := r.db.WithContext(ctx).Where("tenant_id = ? AND status = ?", tid, status).Order("device_time")
+	if !scope.All {
+		q = q.Where("user_id IN ?", scope.UserIDs)
+	}
+	err = q.Find(&out).Error
+	return out, err
+}
+
+func (r *OfflineSyncRepository) SetResult(ctx context.Context, id uint, status, reason string, attendanceID *uint, reviewedBy *uint) error {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	now := r.clock.Now()
+	return r.db.WithContext(ctx).Exec(qSetPunchResult, status, reason, attendanceID, reviewedBy, reviewedBy, now, now, id, tid).Error
+}
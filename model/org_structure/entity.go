@@ -0,0 +1,90 @@
+package org_structure
+
+import (
+	"slices"
+	"time"
+)
+
+// Department mengelompokkan beberapa tim, mis. "Bengkel" atau "Front Office".
+type Department struct {
+	ID        uint      `json:"id"         gorm:"primaryKey"`
+	TenantID  uint      `json:"-"          gorm:"not null;uniqueIndex:idx_departments_tenant_name,priority:1"`
+	Name      string    `json:"name"       gorm:"size:120;not null;uniqueIndex:idx_departments_tenant_name,priority:2"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamptz"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"type:timestamptz"`
+}
+
+// Team berada di satu departemen. ManagerID adalah atasan default untuk
+// anggota yang dipindah ke tim ini tanpa atasan eksplisit.
+type Team struct {
+	ID           uint      `json:"id"            gorm:"primaryKey"`
+	TenantID     uint      `json:"-"             gorm:"not null;index"`
+	DepartmentID uint      `json:"department_id" gorm:"not null;uniqueIndex:idx_teams_department_name,priority:1"`
+	Name         string    `json:"name"          gorm:"size:120;not null;uniqueIndex:idx_teams_department_name,priority:2"`
+	ManagerID    *uint     `json:"manager_id"`
+	CreatedAt    time.Time `json:"created_at"    gorm:"type:timestamptz"`
+	UpdatedAt    time.Time `json:"updated_at"    gorm:"type:timestamptz"`
+}
+
+// ReportingLine adalah satu periode penempatan user: tim dan atasan yang
+// berlaku dari ValidFrom sampai ValidTo (nil = masih berlaku).
+type ReportingLine struct {
+	ID        uint       `json:"id"         gorm:"primaryKey"`
+	TenantID  uint       `json:"-"          gorm:"not null"`
+	UserID    uint       `json:"user_id"    gorm:"not null"`
+	TeamID    *uint      `json:"team_id"`
+	ManagerID *uint      `json:"manager_id"`
+	ValidFrom time.Time  `json:"valid_from" gorm:"type:timestamptz;not null"`
+	ValidTo   *time.Time `json:"valid_to"   gorm:"type:timestamptz"`
+	ChangedBy *uint      `json:"changed_by"`
+	Note      string     `json:"note"`
+	CreatedAt time.Time  `json:"created_at" gorm:"type:timestamptz"`
+}
+
+// Member adalah ringkasan user untuk tampilan struktur organisasi. Depth
+// hanya diisi di daftar bawahan: 1 = bawahan langsung.
+type Member struct {
+	ID        uint   `json:"id"`
+	UserId    uint   `json:"user_id"`
+	Username  string `json:"username"`
+	FullName  string `json:"full_name"`
+	Role      string `json:"role"`
+	TeamID    *uint  `json:"team_id"`
+	ManagerID *uint  `json:"manager_id"`
+	IsActive  bool   `json:"is_active"`
+	Depth     int    `json:"depth,omitempty"`
+}
+
+// Scope membatasi data yang boleh dilihat dan di-approve: ADMIN melihat
+// semua user di tenant, MANAGER hanya bawahannya (langsung maupun tidak).
+type Scope struct {
+	All     bool
+	UserIDs []uint
+}
+
+// Allows true kalau data milik userID termasuk scope.
+func (s Scope) Allows(userID uint) bool {
+	return s.All || slices.Contains(s.UserIDs, userID)
+}
+
+type DepartmentReq struct {
+	Name string `json:"name" binding:"required,max=120"`
+}
+
+type TeamReq struct {
+	DepartmentID uint   `json:"department_id" binding:"required"`
+	Name         string `json:"name"          binding:"required,max=120"`
+	ManagerID    *uint  `json:"manager_id"`
+}
+
+// AssignReq menetapkan penempatan baru seorang user. TeamID nil = tanpa tim;
+// ManagerID nil = manager tim tujuan (kalau ada).
+type AssignReq struct {
+	TeamID    *uint  `json:"team_id"`
+	ManagerID *uint  `json:"manager_id"`
+	Note      string `json:"note" binding:"max=255"`
+}
+
+type RoleReq struct {
+	Role string `json:"role" binding:"required,oneof=ADMIN MANAGER EMPLOYEE"`
+}
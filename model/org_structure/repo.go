@@ -0,0 +1,286 @@
+package org_structure
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"mojo-autotech/clock"
+	"mojo-autotech/tenant"
+)
+
+// Semua method hanya melihat data di tenant dari context.
+type IOrgRepository interface {
+	CreateDepartment(ctx context.Context, d Department) (Department, error)
+	UpdateDepartment(ctx context.Context, d Department) (Department, error)
+	GetDepartment(ctx context.Context, id uint) (Department, error)
+	ListDepartments(ctx context.Context) ([]Department, error)
+
+	CreateTeam(ctx context.Context, t Team) (Team, error)
+	UpdateTeam(ctx context.Context, t Team) (Team, error)
+	GetTeam(ctx context.Context, id uint) (Team, error)
+	// ListTeams: departmentID 0 = semua departemen.
+	ListTeams(ctx context.Context, departmentID uint) ([]Team, error)
+
+	GetMember(ctx context.Context, userID uint) (Member, error)
+	ListMembers(ctx context.Context, teamID uint) ([]Member, error)
+	// Reports mengembalikan semua bawahan managerID, langsung maupun tidak,
+	// urut dari yang paling dekat.
+	Reports(ctx context.Context, managerID uint) ([]Member, error)
+	// Assign menutup penempatan yang berlaku pada line.ValidFrom, mencatat
+	// line sebagai penempatan baru, dan memperbarui users.team_id/manager_id
+	// dalam satu transaksi.
+	Assign(ctx context.Context, line ReportingLine) (ReportingLine, error)
+	// History: riwayat penempatan user, terbaru dulu.
+	History(ctx context.Context, userID uint) ([]ReportingLine, error)
+	SetRole(ctx context.Context, userID uint, role string) error
+}
+
+// updated_at pada users diambil dari clock, bukan NOW() database.
+type OrgRepository struct {
+	db    *gorm.DB
+	clock clock.Clock
+}
+
+func NewOrgRepository(db *gorm.DB, clk clock.Clock) IOrgRepository {
+	return &OrgRepository{db: db, clock: clk}
+}
+
+const (
+	selectMember = `
+SELECT id, user_id, username, full_name, role, team_id, manager_id, is_active
+FROM users
+`
+
+	// path mencegah loop kalau data lama ternyata membentuk siklus
+	qReports = `
+WITH RECURSIVE tree (id, depth, path) AS (
+  SELECT id, 1, ARRAY[id]
+  FROM users
+  WHERE tenant_id = @tenant AND manager_id = @manager AND deleted_at IS NULL
+  UNION ALL
+  SELECT u.id, t.depth + 1, t.path || u.id
+  FROM users u
+  JOIN tree t ON u.manager_id = t.id
+  WHERE u.tenant_id = @tenant AND u.deleted_at IS NULL
+    AND u.id <> @manager AND u.id <> ALL (t.path)
+)
+SELECT u.id, u.user_id, u.username, u.full_name, u.role, u.team_id, u.manager_id, u.is_active, t.depth
+FROM tree t
+JOIN users u ON u.id = t.id
+ORDER BY t.depth, u.full_name, u.id;
+`
+
+	// users dikunci lebih dulu supaya dua pemindahan user yang sama berurutan
+	qUpdatePlacement = `
+UPDATE users SET team_id = ?, manager_id = ?, updated_at = ?
+WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL;
+`
+
+	qCloseReportingLine = `
+UPDATE reporting_lines SET valid_to = ?
+WHERE user_id = ? AND tenant_id = ? AND valid_to IS NULL;
+`
+
+	qSetRole = `
+UPDATE users SET role = ?, updated_at = ?
+WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL;
+`
+)
+
+func (r *OrgRepository) CreateDepartment(ctx context.Context, d Department) (Department, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Department{}, err
+	}
+	d.TenantID = tid
+	if err := r.db.WithContext(ctx).Create(&d).Error; err != nil {
+		return Department{}, err
+	}
+	return d, nil
+}
+
+func (r *OrgRepository) UpdateDepartment(ctx context.Context, d Department) (Department, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Department{}, err
+	}
+	d.TenantID = tid
+	res := r.db.WithContext(ctx).Model(&d).Where("tenant_id = ?", tid).Select("name", "updated_at").Updates(&d)
+	if res.Error != nil {
+		return Department{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return Department{}, gorm.ErrRecordNotFound
+	}
+	return d, nil
+}
+
+func (r *OrgRepository) GetDepartment(ctx context.Context, id uint) (Department, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Department{}, err
+	}
+	var out Department
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", tid).First(&out, id).Error; err != nil {
+		return Department{}, err
+	}
+	return out, nil
+}
+
+func (r *OrgRepository) ListDepartments(ctx context.Context) ([]Department, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []Department
+	err = r.db.WithContext(ctx).Where("tenant_id = ?", tid).Order("name").Find(&out).Error
+	return out, err
+}
+
+func (r *OrgRepository) CreateTeam(ctx context.Context, t Team) (Team, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Team{}, err
+	}
+	t.TenantID = tid
+	if err := r.db.WithContext(ctx).Create(&t).Error; err != nil {
+		return Team{}, err
+	}
+	return t, nil
+}
+
+func (r *OrgRepository) UpdateTeam(ctx context.Context, t Team) (Team, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Team{}, err
+	}
+	t.TenantID = tid
+	// manager_id nil ikut tersimpan (tim tanpa manager)
+	res := r.db.WithContext(ctx).Model(&t).Where("tenant_id = ?", tid).
+		Select("department_id", "name", "manager_id", "updated_at").Updates(&t)
+	if res.Error != nil {
+		return Team{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return Team{}, gorm.ErrRecordNotFound
+	}
+	return t, nil
+}
+
+func (r *OrgRepository) GetTeam(ctx context.Context, id uint) (Team, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Team{}, err
+	}
+	var out Team
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", tid).First(&out, id).Error; err != nil {
+		return Team{}, err
+	}
+	return out, nil
+}
+
+func (r *OrgRepository) ListTeams(ctx context.Context, departmentID uint) ([]Team, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []Team
+	q := r.db.WithContext(ctx).Where("tenant_id = ?", tid).Order("department_id, name")
+	if departmentID != 0 {
+		q = q.Where("department_id = ?", departmentID)
+	}
+	err = q.Find(&out).Error
+	return out, err
+}
+
+func (r *OrgRepository) GetMember(ctx context.Context, userID uint) (Member, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Member{}, err
+	}
+	var out Member
+	tx := r.db.WithContext(ctx).Raw(selectMember+"WHERE tenant_id = ? AND id = ? AND deleted_at IS NULL LIMIT 1;", tid, userID).Scan(&out)
+	if tx.Error != nil {
+		return Member{}, tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return Member{}, gorm.ErrRecordNotFound
+	}
+	return out, nil
+}
+
+func (r *OrgRepository) ListMembers(ctx context.Context, teamID uint) ([]Member, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []Member
+	err = r.db.WithContext(ctx).
+		Raw(selectMember+"WHERE tenant_id = ? AND team_id = ? AND deleted_at IS NULL ORDER BY full_name, id;", tid, teamID).
+		Scan(&out).Error
+	return out, err
+}
+
+func (r *OrgRepository) Reports(ctx context.Context, managerID uint) ([]Member, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []Member
+	err = r.db.WithContext(ctx).Raw(qReports, map[string]any{"tenant": tid, "manager": managerID}).Scan(&out).Error
+	return out, err
+}
+
+func (r *OrgRepository) Assign(ctx context.Context, line ReportingLine) (ReportingLine, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return ReportingLine{}, err
+	}
+	line.TenantID = tid
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Exec(qUpdatePlacement, line.TeamID, line.ManagerID, line.ValidFrom, line.UserID, tid)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Exec(qCloseReportingLine, line.ValidFrom, line.UserID, tid).Error; err != nil {
+			return err
+		}
+		return tx.Create(&line).Error
+	})
+	if err != nil {
+		return ReportingLine{}, err
+	}
+	return line, nil
+}
+
+func (r *OrgRepository) History(ctx context.Context, userID uint) ([]ReportingLine, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []ReportingLine
+	err = r.db.WithContext(ctx).
+		Where("tenant_id = ? AND user_id = ?", tid, userID).
+		Order("valid_from DESC, id DESC").
+		Find(&out).Error
+	return out, err
+}
+
+func (r *OrgRepository) SetRole(ctx context.Context, userID uint, role string) error {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	res := r.db.WithContext(ctx).Exec(qSetRole, role, r.clock.Now(), userID, tid)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
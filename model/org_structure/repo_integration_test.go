@@ -0,0 +1,122 @@
+//go:build integration
+
+package org_structure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mojo-autotech/clock"
+	"mojo-autotech/tenant"
+	"mojo-autotech/testdb"
+)
+
+// addUser membuat user di tenant dan mengembalikan id-nya.
+func addUser(t *testing.T, db *gorm.DB, tenantID uint, username string, managerID *uint) uint {
+	t.Helper()
+	var id uint
+	err := db.Raw(`
+INSERT INTO users (tenant_id, user_id, username, email, password_hash, role, is_active, manager_id, created_at, updated_at)
+VALUES (?, 1, ?, ?, 'x', 'EMPLOYEE', TRUE, ?, NOW(), NOW())
+RETURNING id`, tenantID, username, username+"@mojo.id", managerID).Scan(&id).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestReportsRecursive(t *testing.T) {
+	db := testdb.New(t)
+	repo := NewOrgRepository(db, clock.System)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	boss := addUser(t, db, 1, "boss", nil)
+	lead := addUser(t, db, 1, "lead", &boss)
+	tech := addUser(t, db, 1, "tech", &lead)
+	addUser(t, db, 1, "lain", nil)
+
+	got, err := repo.Reports(ctx, boss)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[uint]int{lead: 1, tech: 2}
+	if len(got) != len(want) {
+		t.Fatalf("bawahan = %+v, mau %v", got, want)
+	}
+	for _, m := range got {
+		if want[m.ID] != m.Depth {
+			t.Errorf("user %d depth %d, mau %d", m.ID, m.Depth, want[m.ID])
+		}
+	}
+
+	// data lama yang membentuk siklus tidak membuat query berputar terus
+	if err := db.Exec(`UPDATE users SET manager_id = ? WHERE id = ?`, tech, boss).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got, err := repo.Reports(ctx, boss); err != nil || len(got) != 2 {
+		t.Fatalf("siklus: %d bawahan, err %v; mau 2", len(got), err)
+	}
+
+	// tenant lain tidak melihat apa pun
+	var other uint
+	if err := db.Raw(`INSERT INTO tenants (code, name, created_at, updated_at) VALUES ('bengkel-b', 'Bengkel B', NOW(), NOW()) RETURNING id`).Scan(&other).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got, err := repo.Reports(tenant.WithID(context.Background(), other), boss); err != nil || len(got) != 0 {
+		t.Fatalf("tenant lain: %d bawahan, err %v; mau 0", len(got), err)
+	}
+}
+
+func TestAssignHistory(t *testing.T) {
+	db := testdb.New(t)
+	repo := NewOrgRepository(db, clock.System)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	boss := addUser(t, db, 1, "boss", nil)
+	tech := addUser(t, db, 1, "tech", nil)
+	dept, err := repo.CreateDepartment(ctx, Department{Name: "Bengkel"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	teams := make([]Team, 2)
+	for i := range teams {
+		if teams[i], err = repo.CreateTeam(ctx, Team{DepartmentID: dept.ID, Name: fmt.Sprintf("Tim %d", i+1), ManagerID: &boss}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start := time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC)
+	for i, team := range teams {
+		_, err := repo.Assign(ctx, ReportingLine{UserID: tech, TeamID: &team.ID, ManagerID: &boss, ValidFrom: start.AddDate(0, 0, i)})
+		if err != nil {
+			t.Fatalf("assign %d: %v", i, err)
+		}
+	}
+
+	m, err := repo.GetMember(ctx, tech)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.TeamID == nil || *m.TeamID != teams[1].ID || m.ManagerID == nil || *m.ManagerID != boss {
+		t.Fatalf("penempatan di users = %+v", m)
+	}
+	history, err := repo.History(ctx, tech)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].ValidTo != nil || history[1].ValidTo == nil || !history[1].ValidTo.Equal(history[0].ValidFrom) {
+		t.Fatalf("riwayat = %+v", history)
+	}
+
+	if _, err := repo.Assign(ctx, ReportingLine{UserID: 999999, ValidFrom: start}); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("user tidak ada: err = %v, mau ErrRecordNotFound", err)
+	}
+	if _, err := repo.History(context.Background(), tech); !errors.Is(err, tenant.ErrMissing) {
+		t.Fatalf("tanpa tenant: err = %v, mau tenant.ErrMissing", err)
+	}
+}
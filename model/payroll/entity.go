@@ -0,0 +1,46 @@
+package payroll
+
+import "time"
+
+const (
+	StatusOpen   = "OPEN"
+	StatusLocked = "LOCKED"
+)
+
+// Period adalah satu periode penggajian. Setelah LOCKED, absensi di dalam
+// rentang tanggalnya tidak boleh diubah lagi.
+type Period struct {
+	ID             uint       `json:"id"              gorm:"primaryKey"`
+	TenantID       uint       `json:"-"               gorm:"not null;uniqueIndex:idx_payroll_period,priority:1"`
+	PeriodStart    time.Time  `json:"period_start"    gorm:"type:date;not null;uniqueIndex:idx_payroll_period,priority:2"`
+	PeriodEnd      time.Time  `json:"period_end"      gorm:"type:date;not null;uniqueIndex:idx_payroll_period,priority:3"`
+	Status         string     `json:"status"          gorm:"size:20;default:OPEN;index"` // OPEN / LOCKED
+	ExportFormat   *string    `json:"export_format"   gorm:"size:20"`
+	ExportChecksum *string    `json:"export_checksum" gorm:"size:64"` // sha256 hex dari file yang dikirim ke payroll
+	ExportedAt     *time.Time `json:"exported_at"     gorm:"type:timestamptz"`
+	LockedAt       *time.Time `json:"locked_at"       gorm:"type:timestamptz"`
+	LockedBy       *uint      `json:"locked_by"`
+	CreatedAt      time.Time  `json:"created_at"      gorm:"type:timestamptz"`
+	UpdatedAt      time.Time  `json:"updated_at"      gorm:"type:timestamptz"`
+}
+
+func (Period) TableName() string { return "payroll_periods" }
+
+// Summary adalah rekap absensi satu karyawan dalam satu periode.
+type Summary struct {
+	UserID                   uint   `json:"user_id"`
+	EmployeeID               uint   `json:"employee_id"` // users.user_id (nomor karyawan)
+	Username                 string `json:"username"`
+	FullName                 string `json:"full_name"`
+	WorkedDays               int    `json:"worked_days"`
+	TotalMinutes             int    `json:"total_minutes"`
+	RegularMinutes           int    `json:"regular_minutes"`
+	OvertimeFirstHourMinutes int    `json:"overtime_first_hour_minutes"` // lembur jam pertama
+	OvertimeNextHoursMinutes int    `json:"overtime_next_hours_minutes"` // lembur jam berikutnya
+	UnpaidLeaveDays          int    `json:"unpaid_leave_days"`
+}
+
+type CreatePeriodReq struct {
+	PeriodStart string `json:"period_start" binding:"required"` // YYYY-MM-DD
+	PeriodEnd   string `json:"period_end"   binding:"required"` // YYYY-MM-DD
+}
@@ -0,0 +1,199 @@
+package payroll
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mojo-autotech/clock"
+	"mojo-autotech/tenant"
+)
+
+// Semua method hanya melihat periode dan absensi di tenant dari context.
+type IPayrollRepository interface {
+	CreatePeriod(ctx context.Context, p Period) (Period, error)
+	ListPeriods(ctx context.Context) ([]Period, error)
+	GetPeriod(ctx context.Context, id uint) (Period, error)
+	CountOverlapping(ctx context.Context, start, end time.Time) (int64, error)
+	Summaries(ctx context.Context, start, end time.Time, standardMinutes, firstHourMinutes int) ([]Summary, error)
+	LockPeriod(ctx context.Context, id uint, lockedBy uint, format, checksum string) (Period, error)
+}
+
+// Timestamp export/lock diambil dari clock, bukan NOW() database.
+type PayrollRepository struct {
+	db    *gorm.DB
+	clock clock.Clock
+}
+
+func NewPayrollRepository(db *gorm.DB, clk clock.Clock) IPayrollRepository {
+	return &PayrollRepository{db: db, clock: clk}
+}
+
+const (
+	qCountOverlappingPeriods = `
+SELECT COUNT(1)
+FROM payroll_periods
+WHERE tenant_id = ? AND period_start <= ?::date AND period_end >= ?::date;
+`
+
+	// Rekap per karyawan. Menit kerja harian dipecah menjadi:
+	//   regular      = min(total, standar)
+	//   lembur jam 1 = min(max(total - standar, 0), 60)
+	//   lembur jam 2+= max(total - standar - 60, 0)
+	// Cuti tidak dibayar (potongan) adalah hari kerja Senin-Jumat dalam periode,
+	// sejak akun dibuat, yang tidak punya baris absensi selain ABSENT; ON_LEAVE
+	// adalah cuti yang disetujui. Akun ADMIN tidak wajib absen, dan akun
+	// nonaktif hanya muncul kalau punya absensi di periode ini.
+	qSummaries = `
+WITH workdays AS (
+  SELECT CAST(d AS date) AS day
+  FROM generate_series(CAST(@start AS date), CAST(@end AS date), interval '1 day') AS d
+  WHERE EXTRACT(ISODOW FROM d) < 6
+),
+worked AS (
+  SELECT
+    a.user_id,
+    COUNT(*) FILTER (WHERE a.check_in_at IS NOT NULL)                AS worked_days,
+    COALESCE(SUM(a.total_minutes), 0)                                AS total_minutes,
+    COALESCE(SUM(LEAST(a.total_minutes, @std)), 0)                   AS regular_minutes,
+    COALESCE(SUM(LEAST(GREATEST(a.total_minutes - @std, 0), @first)), 0) AS overtime_first_hour_minutes,
+    COALESCE(SUM(GREATEST(a.total_minutes - @std - @first, 0)), 0)   AS overtime_next_hours_minutes
+  FROM attendances a
+  WHERE a.tenant_id = @tenant
+    AND a.date BETWEEN CAST(@start AS date) AND CAST(@end AS date)
+  GROUP BY a.user_id
+),
+unpaid AS (
+  SELECT u.id AS user_id, COUNT(*) AS days
+  FROM users u
+  JOIN workdays w ON w.day >= CAST(u.created_at AS date)
+  WHERE u.tenant_id = @tenant
+    AND u.deleted_at IS NULL
+    AND u.is_active
+    AND u.role IS DISTINCT FROM 'ADMIN'
+    AND NOT EXISTS (
+      SELECT 1 FROM attendances a
+      WHERE a.tenant_id = u.tenant_id AND a.user_id = u.id AND a.date = w.day AND a.status <> 'ABSENT'
+    )
+  GROUP BY u.id
+)
+SELECT
+  u.id                                          AS user_id,
+  u.user_id                                     AS employee_id,
+  u.username,
+  u.full_name,
+  COALESCE(wk.worked_days, 0)                   AS worked_days,
+  COALESCE(wk.total_minutes, 0)                 AS total_minutes,
+  COALESCE(wk.regular_minutes, 0)               AS regular_minutes,
+  COALESCE(wk.overtime_first_hour_minutes, 0)   AS overtime_first_hour_minutes,
+  COALESCE(wk.overtime_next_hours_minutes, 0)   AS overtime_next_hours_minutes,
+  COALESCE(up.days, 0)                          AS unpaid_leave_days
+FROM users u
+LEFT JOIN worked wk ON wk.user_id = u.id
+LEFT JOIN unpaid up ON up.user_id = u.id
+WHERE u.tenant_id = @tenant
+  AND u.deleted_at IS NULL
+  AND (wk.user_id IS NOT NULL OR up.user_id IS NOT NULL)
+ORDER BY u.user_id, u.id;
+`
+
+	// Kunci hanya kalau masih OPEN, supaya export yang sudah dikirim tidak tertimpa
+	qLockPeriod = `
+UPDATE payroll_periods
+SET
+  status          = 'LOCKED',
+  export_format   = @format,
+  export_checksum = @checksum,
+  exported_at     = @now,
+  locked_at       = @now,
+  locked_by       = @by,
+  updated_at      = @now
+WHERE id = @id AND tenant_id = @tenant AND status = 'OPEN'
+RETURNING *;
+`
+)
+
+func (r *PayrollRepository) CreatePeriod(ctx context.Context, p Period) (Period, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Period{}, err
+	}
+	p.TenantID = tid
+	if err := r.db.WithContext(ctx).Create(&p).Error; err != nil {
+		return Period{}, err
+	}
+	return p, nil
+}
+
+func (r *PayrollRepository) ListPeriods(ctx context.Context) ([]Period, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []Period
+	err = r.db.WithContext(ctx).Where("tenant_id = ?", tid).Order("period_start DESC").Find(&out).Error
+	return out, err
+}
+
+func (r *PayrollRepository) GetPeriod(ctx context.Context, id uint) (Period, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Period{}, err
+	}
+	var out Period
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", tid).First(&out, id).Error; err != nil {
+		return Period{}, err
+	}
+	return out, nil
+}
+
+func (r *PayrollRepository) CountOverlapping(ctx context.Context, start, end time.Time) (int64, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	err = r.db.WithContext(ctx).Raw(qCountOverlappingPeriods, tid, end.Format("2006-01-02"), start.Format("2006-01-02")).Scan(&n).Error
+	return n, err
+}
+
+func (r *PayrollRepository) Summaries(ctx context.Context, start, end time.Time, standardMinutes, firstHourMinutes int) ([]Summary, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []Summary
+	err = r.db.WithContext(ctx).Raw(qSummaries, map[string]any{
+		"start":  start.Format("2006-01-02"),
+		"end":    end.Format("2006-01-02"),
+		"std":    standardMinutes,
+		"first":  firstHourMinutes,
+		"tenant": tid,
+	}).Scan(&out).Error
+	return out, err
+}
+
+func (r *PayrollRepository) LockPeriod(ctx context.Context, id uint, lockedBy uint, format, checksum string) (Period, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return Period{}, err
+	}
+	var out Period
+	res := r.db.WithContext(ctx).Raw(qLockPeriod, map[string]any{
+		"format":   format,
+		"checksum": checksum,
+		"now":      r.clock.Now(),
+		"by":       lockedBy,
+		"id":       id,
+		"tenant":   tid,
+	}).Scan(&out)
+	if res.Error != nil {
+		return Period{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		// periode tidak ada atau sudah LOCKED
+		return Period{}, gorm.ErrRecordNotFound
+	}
+	return out, nil
+}
@@ -0,0 +1,79 @@
+//go:build integration
+
+package payroll
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mojo-autotech/clock"
+	"mojo-autotech/tenant"
+	"mojo-autotech/testdb"
+)
+
+// addUser membuat user tenant 1 yang dibuat pada tanggal createdAt.
+func addUser(t *testing.T, db *gorm.DB, userID uint, username, role string, active bool, createdAt string) uint {
+	t.Helper()
+	var id uint
+	err := db.Raw(`
+INSERT INTO users (tenant_id, user_id, username, email, full_name, password_hash, role, is_active, created_at, updated_at)
+VALUES (1, ?, ?, ?, ?, 'x', ?, ?, CAST(? AS timestamptz), NOW())
+RETURNING id`, userID, username, username+"@mojo.id", username, role, active, createdAt).Scan(&id).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func addAttendance(t *testing.T, db *gorm.DB, userID uint, date, status string, minutes int) {
+	t.Helper()
+	err := db.Exec(`
+INSERT INTO attendances (tenant_id, user_id, date, check_in_at, total_minutes, status, created_at, updated_at)
+VALUES (1, ?, CAST(? AS date), CASE WHEN ? > 0 THEN CAST(? AS timestamptz) END, ?, ?, NOW(), NOW())`,
+		userID, date, minutes, date+" 08:00:00+07", minutes, status).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSummariesUnpaidLeave(t *testing.T) {
+	db := testdb.New(t)
+	repo := NewPayrollRepository(db, clock.System)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	budi := addUser(t, db, 1, "budi", "EMPLOYEE", true, "2026-02-01 09:00:00+07")
+	sari := addUser(t, db, 2, "sari", "EMPLOYEE", true, "2026-03-05 09:00:00+07") // masuk hari Kamis
+	addUser(t, db, 3, "admin", "ADMIN", true, "2026-01-01 09:00:00+07")
+	addUser(t, db, 4, "lama", "EMPLOYEE", false, "2026-01-01 09:00:00+07")
+
+	// Senin masuk dengan lembur 90 menit, Selasa cuti disetujui, Rabu ABSENT,
+	// Kamis-Jumat tanpa baris, Sabtu masuk (akhir pekan bukan potongan)
+	addAttendance(t, db, budi, "2026-03-02", "PRESENT", 570)
+	addAttendance(t, db, budi, "2026-03-03", "ON_LEAVE", 0)
+	addAttendance(t, db, budi, "2026-03-04", "ABSENT", 0)
+	addAttendance(t, db, budi, "2026-03-07", "PRESENT", 240)
+
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	got, err := repo.Summaries(ctx, start, start.AddDate(0, 0, 6), 480, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("rekap = %+v, mau budi dan sari saja (admin dan akun nonaktif tanpa absensi dilewati)", got)
+	}
+
+	b, s := got[0], got[1]
+	if b.UserID != budi || b.WorkedDays != 2 || b.TotalMinutes != 810 || b.RegularMinutes != 720 ||
+		b.OvertimeFirstHourMinutes != 60 || b.OvertimeNextHoursMinutes != 30 {
+		t.Fatalf("rekap budi = %+v", b)
+	}
+	if b.UnpaidLeaveDays != 3 {
+		t.Fatalf("cuti tidak dibayar budi = %d, mau 3 (Rabu ABSENT, Kamis, Jumat)", b.UnpaidLeaveDays)
+	}
+	if s.UserID != sari || s.WorkedDays != 0 || s.UnpaidLeaveDays != 2 {
+		t.Fatalf("rekap sari = %+v, mau 0 hari kerja dan 2 hari potongan sejak Kamis", s)
+	}
+}
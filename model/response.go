@@ -1,8 +1,13 @@
 package model
 
+// Response adalah envelope semua endpoint. Msg/Err berisi teks sesuai
+// Accept-Language; MsgCode/ErrMsgCode adalah key katalog i18n yang stabil.
 type Response struct {
-	Code int         `json:"code"`
-	Msg  string      `json:"msg"`
-	Err  string      `json:"err"`
-	Data interface{} `json:"data"`
+	Code       int         `json:"code"`
+	Msg        string      `json:"msg"`
+	MsgCode    string      `json:"msg_code,omitempty"`
+	Err        string      `json:"err"`
+	ErrCode    string      `json:"err_code,omitempty"`     // apperror.Code, kategori error
+	ErrMsgCode string      `json:"err_msg_code,omitempty"` // key pesan error
+	Data       interface{} `json:"data"`
 }
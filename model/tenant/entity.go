@@ -0,0 +1,19 @@
+package tenant
+
+import "time"
+
+// Tenant adalah satu perusahaan/workshop. Semua data bisnis (user, absensi,
+// lokasi, kiosk, payroll, ...) punya tenant_id dan hanya terlihat di tenant-nya.
+type Tenant struct {
+	ID        uint      `json:"id"         gorm:"primaryKey"`
+	Code      string    `json:"code"       gorm:"size:40;uniqueIndex;not null"` // dikirim client saat login
+	Name      string    `json:"name"       gorm:"size:120;not null"`
+	IsActive  bool      `json:"is_active"  gorm:"default:true"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamptz"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"type:timestamptz"`
+}
+
+type CreateTenantReq struct {
+	Code string `json:"code" binding:"required,max=40"`
+	Name string `json:"name" binding:"required,max=120"`
+}
@@ -0,0 +1,43 @@
+package tenant
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Tabel tenants adalah akar data, jadi repository ini tidak di-scope per tenant.
+type ITenantRepository interface {
+	Create(ctx context.Context, t Tenant) (Tenant, error)
+	GetByCode(ctx context.Context, code string) (Tenant, error)
+	List(ctx context.Context) ([]Tenant, error)
+}
+
+type TenantRepository struct {
+	db *gorm.DB
+}
+
+func NewTenantRepository(db *gorm.DB) ITenantRepository {
+	return &TenantRepository{db: db}
+}
+
+func (r *TenantRepository) Create(ctx context.Context, t Tenant) (Tenant, error) {
+	if err := r.db.WithContext(ctx).Create(&t).Error; err != nil {
+		return Tenant{}, err
+	}
+	return t, nil
+}
+
+func (r *TenantRepository) GetByCode(ctx context.Context, code string) (Tenant, error) {
+	var out Tenant
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&out).Error; err != nil {
+		return Tenant{}, err
+	}
+	return out, nil
+}
+
+func (r *TenantRepository) List(ctx context.Context) ([]Tenant, error) {
+	var out []Tenant
+	err := r.db.WithContext(ctx).Order("id").Find(&out).Error
+	return out, err
+}
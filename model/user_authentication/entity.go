@@ -7,20 +7,28 @@ import (
 )
 
 type User struct {
-	ID           uint           `gorm:"primaryKey" json:"id"`
-	UserId       uint           `gorm:"primaryKey" json:"user_id"`
-	Username     string         `gorm:"size:50;uniqueIndex;not null" json:"username"`
-	Email        string         `gorm:"size:120;uniqueIndex;not null" json:"email"`
-	FullName     string         `gorm:"size:120" json:"full_name"`
-	Phone        string         `gorm:"size:20" json:"phone"`
-	PasswordHash string         `gorm:"not null" json:"-"`
-	Role         string         `gorm:"size:20;default:EMPLOYEE;index" json:"role"` // ADMIN / EMPLOYEE
-	IsActive     bool           `gorm:"default:true" json:"is_active"`
-	LastLoginAt  *time.Time     `json:"last_login_at,omitempty"`
-	FailedLogin  uint           `gorm:"default:0" json:"-"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	UserId         uint           `gorm:"primaryKey" json:"user_id"`
+	TenantID       uint           `gorm:"not null;uniqueIndex:idx_users_tenant_username,priority:1;uniqueIndex:idx_users_tenant_email,priority:1;uniqueIndex:idx_users_tenant_badge_id,priority:1" json:"-"`
+	Username       string         `gorm:"size:50;uniqueIndex:idx_users_tenant_username,priority:2;not null" json:"username"`
+	Email          string         `gorm:"size:120;uniqueIndex:idx_users_tenant_email,priority:2;not null" json:"email"`
+	FullName       string         `gorm:"size:120" json:"full_name"`
+	Phone          string         `gorm:"size:20" json:"phone"`
+	PasswordHash   string         `gorm:"not null" json:"-"`
+	BadgeID        *string        `gorm:"size:40;uniqueIndex:idx_users_tenant_badge_id,priority:2" json:"badge_id,omitempty"` // kartu karyawan untuk kiosk
+	PinHash        string         `json:"-"`
+	Role           string         `gorm:"size:20;default:EMPLOYEE;index" json:"role"` // ADMIN / MANAGER / EMPLOYEE
+	TeamID         *uint          `json:"team_id,omitempty"`
+	ManagerID      *uint          `json:"manager_id,omitempty"` // atasan langsung, lihat model/org_structure
+	IsActive       bool           `gorm:"default:true" json:"is_active"`
+	LastLoginAt    *time.Time     `json:"last_login_at,omitempty"`
+	FailedLogin    uint           `gorm:"default:0" json:"-"`
+	LockedUntil    *time.Time     `json:"locked_until,omitempty"` // diisi saat password salah terlalu sering
+	PinFailed      uint           `gorm:"default:0" json:"-"`
+	PinLockedUntil *time.Time     `json:"pin_locked_until,omitempty"` // diisi saat PIN kiosk salah terlalu sering
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 type RegisterReq struct {
@@ -30,16 +38,20 @@ type RegisterReq struct {
 	FullName string `json:"full_name" binding:"required"`
 	Phone    string `json:"phone"     binding:"omitempty"`
 	Password string `json:"password"  binding:"required,min=8"`
-	Role     string `json:"role"      binding:"omitempty,oneof=ADMIN EMPLOYEE"`
+	// Role tidak dibaca dari body: akun dari /create selalu EMPLOYEE dan
+	// dinaikkan lewat PUT /org/users/:id/role. Diisi CLI dan seed saja.
+	Role string `json:"-"`
 }
 
 type LoginReq struct {
+	Tenant   string `json:"tenant"   binding:"omitempty,max=40"` // kode tenant; kosong = tenant default
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
 }
 
 type LoginRes struct {
 	ID           uint   `json:"id"`
+	TenantID     uint   `json:"tenant_id"`
 	Username     string `json:"username"`
 	Role         string `json:"role"`
 	Email        string `json:"email"`
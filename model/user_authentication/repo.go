@@ -3,54 +3,94 @@ package user_authentication
 import (
 	"context"
 	"errors"
-	"mojo-autotech/config"
+	"strings"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+
+	"mojo-autotech/clock"
+	"mojo-autotech/tenant"
 )
 
-// Interface
+// ErrPasswordMismatch dikembalikan Login kalau password tidak cocok dengan hash.
+var ErrPasswordMismatch = errors.New("password salah")
+
+// Interface. Semua method hanya melihat user di tenant dari context.
 type IAuthRepository interface {
 	Login(ctx context.Context, req LoginReq) (user User, err error)
 	CreateUser(ctx context.Context, req RegisterReq) (User, error)
+	CountDuplicate(ctx context.Context, username, email string) (int64, error)
+	UpdatePassword(ctx context.Context, username, passwordHash string) error
+	SetActive(ctx context.Context, username string, active bool) error
+	// RecordFailedLogin menambah hitungan password salah; begitu mencapai
+	// maxFailed akun dikunci selama lockout dan hitungan dimulai lagi dari 0.
+	// Mengembalikan locked_until terbaru (nil kalau belum pernah dikunci).
+	RecordFailedLogin(ctx context.Context, userID uint, maxFailed int, lockout time.Duration) (*time.Time, error)
+	// RecordLogin mereset hitungan gagal dan mencatat last_login_at.
+	RecordLogin(ctx context.Context, userID uint) error
 }
 
-// Impl
+// Impl. Timestamp diambil dari clock, bukan NOW() database, supaya
+// locked_until dibandingkan dengan jam yang sama di service.
 type AuthRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	clock clock.Clock
 }
 
-func NewAuthRepository() IAuthRepository {
-	return &AuthRepository{db: config.NewDB()}
+func NewAuthRepository(db *gorm.DB, clk clock.Clock) IAuthRepository {
+	return &AuthRepository{db: db, clock: clk}
 }
 
 const (
 	SelectUserByUsername = `
 SELECT id, username, email, full_name, phone, password_hash, role, is_active,
-       last_login_at, failed_login, created_at, updated_at
+       last_login_at, failed_login, locked_until, created_at, updated_at
 FROM users
-WHERE LOWER(username) = ? 
+WHERE tenant_id = ? AND LOWER(username) = ?
 LIMIT 1;
 `
 
+	// maxFailed 0 berarti tidak pernah dikunci; ruas kanan SET membaca nilai lama
 	FailedLogin = `
-UPDATE users SET failed_login = failed_login + 1
-WHERE id = ?;
+UPDATE users SET
+  failed_login = CASE WHEN @max > 0 AND failed_login + 1 >= @max THEN 0 ELSE failed_login + 1 END,
+  locked_until = CASE WHEN @max > 0 AND failed_login + 1 >= @max
+                      THEN CAST(@now AS timestamptz) + make_interval(secs => @lockout) ELSE locked_until END,
+  updated_at   = @now
+WHERE id = @id AND tenant_id = @tenant
+RETURNING locked_until;
+`
+
+	SuccessfulLogin = `
+UPDATE users SET failed_login = 0, locked_until = NULL, last_login_at = ?
+WHERE id = ? AND tenant_id = ?;
 `
 
 	CheckDuplicateUser = `
 SELECT COUNT(1)
 FROM users
-WHERE LOWER(username) = ? OR LOWER(email) = ?;
+WHERE tenant_id = ? AND (LOWER(username) = ? OR LOWER(email) = ?);
+`
+	UpdatePasswordByUsername = `
+UPDATE users SET password_hash = ?, failed_login = 0, locked_until = NULL, updated_at = ?
+WHERE tenant_id = ? AND LOWER(username) = LOWER(?) AND deleted_at IS NULL;
 `
+
+	SetActiveByUsername = `
+UPDATE users SET is_active = ?, updated_at = ?
+WHERE tenant_id = ? AND LOWER(username) = LOWER(?) AND deleted_at IS NULL;
+`
+
 	InsertUser = `
 INSERT INTO users
-  (user_id,username, email, full_name, phone, password_hash, role, is_active, created_at, updated_at)
+  (tenant_id, user_id,username, email, full_name, phone, password_hash, role, is_active, created_at, updated_at)
 VALUES
-  (?,?, ?, ?, ?, ?, COALESCE(NULLIF(?, ''), 'EMPLOYEE'), TRUE, NOW(), NOW())
+  (?, ?,?, ?, ?, ?, ?, COALESCE(NULLIF(?, ''), 'EMPLOYEE'), TRUE, ?, ?)
 RETURNING
   id,
   user_id,
+  tenant_id,
   username,
   email,
   full_name,
@@ -63,26 +103,36 @@ RETURNING
 )
 
 func (r *AuthRepository) Login(ctx context.Context, req LoginReq) (user User, err error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return User{}, err
+	}
 
-	tx := r.db.WithContext(ctx).Raw(SelectUserByUsername, req.Username).Scan(&user)
+	tx := r.db.WithContext(ctx).Raw(SelectUserByUsername, tid, strings.ToLower(req.Username)).Scan(&user)
 	if tx.Error != nil {
-		return User{}, errors.New("gagal mengambil data user")
+		return User{}, tx.Error
 	}
 	if tx.RowsAffected == 0 {
-		return User{}, errors.New("username tidak ditemukan")
+		return User{}, gorm.ErrRecordNotFound
 	}
 
-	// Verifikasi password (bcrypt)
+	// Verifikasi password (bcrypt). User tetap dikembalikan supaya service bisa
+	// memeriksa kunci akun dan mencatat kegagalan.
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
-		_ = r.db.WithContext(ctx).Exec(FailedLogin, user.ID).Error
-		return User{}, errors.New("password salah")
+		return user, ErrPasswordMismatch
 	}
 
 	return
 }
 
 func (a *AuthRepository) CreateUser(ctx context.Context, req RegisterReq) (res User, err error) {
-	err = a.db.Raw(InsertUser,
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return User{}, err
+	}
+	now := a.clock.Now()
+	err = a.db.WithContext(ctx).Raw(InsertUser,
+		tid,
 		req.UserId,
 		req.Username,
 		req.Email,
@@ -90,6 +140,72 @@ func (a *AuthRepository) CreateUser(ctx context.Context, req RegisterReq) (res U
 		req.Phone,
 		req.Password,
 		req.Role,
+		now, now,
 	).Scan(&res).Error
 	return
 }
+
+func (r *AuthRepository) CountDuplicate(ctx context.Context, username, email string) (n int64, err error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	err = r.db.WithContext(ctx).Raw(CheckDuplicateUser, tid, strings.ToLower(username), strings.ToLower(email)).Scan(&n).Error
+	return
+}
+
+func (r *AuthRepository) UpdatePassword(ctx context.Context, username, passwordHash string) error {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	tx := r.db.WithContext(ctx).Exec(UpdatePasswordByUsername, passwordHash, r.clock.Now(), tid, username)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *AuthRepository) SetActive(ctx context.Context, username string, active bool) error {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	tx := r.db.WithContext(ctx).Exec(SetActiveByUsername, active, r.clock.Now(), tid, username)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *AuthRepository) RecordFailedLogin(ctx context.Context, userID uint, maxFailed int, lockout time.Duration) (*time.Time, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var row struct {
+		LockedUntil *time.Time
+	}
+	err = r.db.WithContext(ctx).Raw(FailedLogin, map[string]any{
+		"max":     maxFailed,
+		"lockout": lockout.Seconds(),
+		"now":     r.clock.Now(),
+		"id":      userID,
+		"tenant":  tid,
+	}).Scan(&row).Error
+	return row.LockedUntil, err
+}
+
+func (r *AuthRepository) RecordLogin(ctx context.Context, userID uint) error {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Exec(SuccessfulLogin, r.clock.Now(), userID, tid).Error
+}
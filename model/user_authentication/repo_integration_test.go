@@ -0,0 +1,93 @@
+//go:build integration
+
+package user_authentication
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"mojo-autotech/clock"
+	"mojo-autotech/tenant"
+	"mojo-autotech/testdb"
+)
+
+func createUser(t *testing.T, repo IAuthRepository, username, password string) User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := repo.CreateUser(tenant.WithID(context.Background(), 1), RegisterReq{
+		UserId: 1, Username: username, Email: username + "@mojo.id", FullName: username, Password: string(hash),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestCreateUserAndLogin(t *testing.T) {
+	repo := NewAuthRepository(testdb.New(t), clock.System)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	u := createUser(t, repo, "budi", "rahasia123")
+	if u.ID == 0 || u.Role != "EMPLOYEE" || !u.IsActive {
+		t.Fatalf("user baru = %+v, mau role default EMPLOYEE dan aktif", u)
+	}
+
+	// username tidak peka huruf besar/kecil
+	got, err := repo.Login(ctx, LoginReq{Username: "BuDi", Password: "rahasia123"})
+	if err != nil || got.ID != u.ID {
+		t.Fatalf("login: user=%+v err=%v", got, err)
+	}
+	if _, err := repo.Login(ctx, LoginReq{Username: "budi", Password: "salah"}); !errors.Is(err, ErrPasswordMismatch) {
+		t.Fatalf("password salah: err = %v", err)
+	}
+	if _, err := repo.Login(ctx, LoginReq{Username: "siapa", Password: "x"}); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("user tidak ada: err = %v", err)
+	}
+
+	n, err := repo.CountDuplicate(ctx, "BUDI", "lain@mojo.id")
+	if err != nil || n != 1 {
+		t.Fatalf("CountDuplicate = %d, %v; mau 1", n, err)
+	}
+}
+
+func TestRecordFailedLogin(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC))
+	repo := NewAuthRepository(testdb.New(t), clk)
+	ctx := tenant.WithID(context.Background(), 1)
+	u := createUser(t, repo, "sari", "rahasia123")
+
+	for i := 1; i <= 2; i++ {
+		until, err := repo.RecordFailedLogin(ctx, u.ID, 3, 15*time.Minute)
+		if err != nil || until != nil {
+			t.Fatalf("gagal ke-%d: locked_until=%v err=%v", i, until, err)
+		}
+	}
+	until, err := repo.RecordFailedLogin(ctx, u.ID, 3, 15*time.Minute)
+	if err != nil || until == nil {
+		t.Fatalf("gagal ke-3 harus mengunci: locked_until=%v err=%v", until, err)
+	}
+	if want := clk.Now().Add(15 * time.Minute); !until.Equal(want) {
+		t.Fatalf("locked_until = %v, mau %v", until, want)
+	}
+
+	got, _ := repo.Login(ctx, LoginReq{Username: "sari", Password: "rahasia123"})
+	if got.FailedLogin != 0 || got.LockedUntil == nil {
+		t.Fatalf("setelah dikunci: failed_login=%d locked_until=%v", got.FailedLogin, got.LockedUntil)
+	}
+
+	if err := repo.RecordLogin(ctx, u.ID); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = repo.Login(ctx, LoginReq{Username: "sari", Password: "rahasia123"})
+	if got.LockedUntil != nil || got.LastLoginAt == nil || !got.LastLoginAt.Equal(clk.Now()) {
+		t.Fatalf("RecordLogin harus membuka kunci dan mencatat last_login_at: %+v", got)
+	}
+}
@@ -0,0 +1,115 @@
+package work_location
+
+import (
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	IPPolicyOff    = "OFF"    // IP tidak diperiksa
+	IPPolicyFlag   = "FLAG"   // check-in diterima, tapi masuk antrean review anomali
+	IPPolicyReject = "REJECT" // check-in ditolak
+)
+
+// WorkLocation adalah lokasi kerja (bengkel/workshop). Networks berisi CIDR
+// jaringan yang dipakai di lokasi tersebut (mis. Wi-Fi workshop), dipisah koma,
+// dan menjadi allowlist IP kalau IPPolicy bukan OFF.
+type WorkLocation struct {
+	ID        uint      `json:"id"         gorm:"primaryKey"`
+	TenantID  uint      `json:"-"          gorm:"not null;index"`
+	Name      string    `json:"name"       gorm:"size:120;not null"`
+	Address   string    `json:"address"`
+	Lat       *float64  `json:"lat"`
+	Lng       *float64  `json:"lng"`
+	RadiusM   int       `json:"radius_m"   gorm:"default:200"` // radius geofence dalam meter
+	Networks  string    `json:"networks"`                      // "10.10.0.0/16,203.0.113.0/24"
+	IPPolicy  string    `json:"ip_policy"  gorm:"size:10;default:OFF"`
+	Timezone  string    `json:"timezone"   gorm:"size:64"` // IANA, kosong = timezone perusahaan
+	IsActive  bool      `json:"is_active"  gorm:"default:true"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamptz"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"type:timestamptz"`
+}
+
+// NetworkList memecah Networks menjadi daftar CIDR; entri yang tidak valid dilewati.
+func (w WorkLocation) NetworkList() []*net.IPNet {
+	var out []*net.IPNet
+	for _, s := range strings.Split(w.Networks, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if !strings.Contains(s, "/") {
+			// IP tunggal → /32 atau /128
+			if ip := net.ParseIP(s); ip != nil && ip.To4() != nil {
+				s += "/32"
+			} else {
+				s += "/128"
+			}
+		}
+		if _, n, err := net.ParseCIDR(s); err == nil {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// Location mengembalikan timezone lokasi, atau fallback kalau Timezone kosong
+// atau tidak dikenal.
+func (w WorkLocation) Location(fallback *time.Location) *time.Location {
+	if w.Timezone == "" {
+		return fallback
+	}
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return fallback
+	}
+	return loc
+}
+
+// HasNetworks true kalau lokasi punya daftar jaringan.
+func (w WorkLocation) HasNetworks() bool {
+	return len(w.NetworkList()) > 0
+}
+
+// ContainsIP true kalau ip berada di salah satu jaringan lokasi.
+func (w WorkLocation) ContainsIP(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, n := range w.NetworkList() {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// RestrictsIP true kalau policy bukan OFF dan lokasi punya daftar jaringan.
+// Lokasi tanpa daftar jaringan dianggap tidak membatasi.
+func (w WorkLocation) RestrictsIP() bool {
+	return w.IPPolicy != "" && w.IPPolicy != IPPolicyOff && w.HasNetworks()
+}
+
+// IPAllowed false kalau policy aktif dan ip berada di luar allowlist.
+func (w WorkLocation) IPAllowed(ip string) bool {
+	return !w.RestrictsIP() || w.ContainsIP(ip)
+}
+
+type WorkLocationReq struct {
+	Name     string   `json:"name"     binding:"required,max=120"`
+	Address  string   `json:"address"`
+	Lat      *float64 `json:"lat"      binding:"omitempty,latitude"`
+	Lng      *float64 `json:"lng"      binding:"omitempty,longitude"`
+	RadiusM  int      `json:"radius_m" binding:"omitempty,min=10"`
+	Networks []string `json:"networks" binding:"omitempty,dive,cidr|ip"`
+	IPPolicy string   `json:"ip_policy" binding:"omitempty,oneof=OFF FLAG REJECT"`
+	Timezone string   `json:"timezone"  binding:"omitempty,timezone"`
+	IsActive *bool    `json:"is_active"`
+}
+
+type IPPolicyReq struct {
+	Networks []string `json:"networks"  binding:"omitempty,dive,cidr|ip"`
+	Policy   string   `json:"ip_policy" binding:"required,oneof=OFF FLAG REJECT"`
+}
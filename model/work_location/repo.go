@@ -0,0 +1,76 @@
+package work_location
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"mojo-autotech/tenant"
+)
+
+// Semua method hanya melihat lokasi di tenant dari context.
+type IWorkLocationRepository interface {
+	Create(ctx context.Context, w WorkLocation) (WorkLocation, error)
+	Update(ctx context.Context, w WorkLocation) (WorkLocation, error)
+	GetByID(ctx context.Context, id uint) (WorkLocation, error)
+	List(ctx context.Context) ([]WorkLocation, error)
+}
+
+type WorkLocationRepository struct {
+	db *gorm.DB
+}
+
+func NewWorkLocationRepository(db *gorm.DB) IWorkLocationRepository {
+	return &WorkLocationRepository{db: db}
+}
+
+func (r *WorkLocationRepository) Create(ctx context.Context, w WorkLocation) (WorkLocation, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return WorkLocation{}, err
+	}
+	w.TenantID = tid
+	if err := r.db.WithContext(ctx).Create(&w).Error; err != nil {
+		return WorkLocation{}, err
+	}
+	return w, nil
+}
+
+func (r *WorkLocationRepository) Update(ctx context.Context, w WorkLocation) (WorkLocation, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return WorkLocation{}, err
+	}
+	w.TenantID = tid
+	// Select("*") supaya nilai nol (is_active=false, networks="") ikut tersimpan
+	res := r.db.WithContext(ctx).Model(&w).Where("tenant_id = ?", tid).Select("*").Omit("created_at").Updates(&w)
+	if res.Error != nil {
+		return WorkLocation{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return WorkLocation{}, gorm.ErrRecordNotFound
+	}
+	return w, nil
+}
+
+func (r *WorkLocationRepository) GetByID(ctx context.Context, id uint) (WorkLocation, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return WorkLocation{}, err
+	}
+	var out WorkLocation
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", tid).First(&out, id).Error; err != nil {
+		return WorkLocation{}, err
+	}
+	return out, nil
+}
+
+func (r *WorkLocationRepository) List(ctx context.Context) ([]WorkLocation, error) {
+	tid, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []WorkLocation
+	err = r.db.WithContext(ctx).Where("tenant_id = ?", tid).Order("id").Find(&out).Error
+	return out, err
+}
@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery: seberapa sering bucket yang sudah penuh kembali dibuang supaya
+// map tidak tumbuh terus oleh IP yang hanya lewat sekali.
+const sweepEvery = time.Minute
+
+// MemoryStore menyimpan bucket di memori proses. Hanya akurat untuk satu
+// instance; dengan beberapa replika setiap instance punya bucket sendiri.
+type MemoryStore struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tats: map[string]time.Time{}, now: time.Now}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, rule Rule) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= sweepEvery {
+		s.sweep(now)
+	}
+
+	tat, wait := gcra(s.tats[key], now, rule)
+	if wait > 0 {
+		return Result{RetryAfter: wait}, nil
+	}
+	s.tats[key] = tat
+	return Result{Allowed: true}, nil
+}
+
+// sweep membuang bucket yang sudah terisi penuh lagi; tidak ada bedanya
+// dengan bucket baru.
+func (s *MemoryStore) sweep(now time.Time) {
+	for k, tat := range s.tats {
+		if !tat.After(now) {
+			delete(s.tats, k)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,58 @@
+// Package ratelimit menyediakan token bucket per key (IP atau user) dengan
+// backend memori untuk satu instance dan Redis kalau API dijalankan lebih
+// dari satu replika.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mojo-autotech/config"
+)
+
+// Rule: bucket berisi paling banyak Burst token dan bertambah satu token
+// setiap Refill. Burst 5 + Refill 1m = 5 request beruntun, lalu 1 per menit.
+type Rule = config.Rule
+
+// Result hasil satu pengambilan token.
+type Result struct {
+	Allowed bool
+	// RetryAfter: tunggu sampai token berikutnya tersedia; 0 kalau Allowed
+	RetryAfter time.Duration
+}
+
+// Store mengambil satu token dari bucket key. Bucket yang belum ada dianggap penuh.
+type Store interface {
+	Take(ctx context.Context, key string, rule Rule) (Result, error)
+}
+
+// New memilih backend sesuai config. Fungsi close menutup koneksi Redis;
+// untuk backend memori tidak melakukan apa-apa.
+func New(cfg config.RateLimit) (Store, func() error, error) {
+	switch cfg.Backend {
+	case "memory":
+		return NewMemoryStore(), func() error { return nil }, nil
+	case "redis":
+		s, err := NewRedisStoreFromURL(cfg.RedisURL.Value())
+		if err != nil {
+			return nil, nil, err
+		}
+		return s, s.Close, nil
+	}
+	return nil, nil, fmt.Errorf("backend rate limit %q tidak dikenal", cfg.Backend)
+}
+
+// gcra menghitung satu pengambilan token dengan GCRA, setara token bucket
+// tapi cukup menyimpan satu waktu: tat (theoretical arrival time), kapan
+// bucket terisi penuh lagi. Mengembalikan tat baru, atau wait > 0 kalau ditolak.
+func gcra(tat, now time.Time, rule Rule) (newTAT time.Time, wait time.Duration) {
+	if tat.Before(now) {
+		tat = now
+	}
+	newTAT = tat.Add(rule.Refill)
+	if over := newTAT.Sub(now) - time.Duration(rule.Burst)*rule.Refill; over > 0 {
+		return tat, over
+	}
+	return newTAT, 0
+}
@@ -0,0 +1,175 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// clock waktu palsu yang dimajukan manual oleh test.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+func newClock() *clock                   { return &clock{t: time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC)} }
+func rule(burst int, refill string) Rule {
+	d, _ := time.ParseDuration(refill)
+	return Rule{Burst: burst, Refill: d}
+}
+
+// stores menjalankan skenario yang sama untuk semua backend.
+func stores(t *testing.T) map[string]func(*clock) Store {
+	return map[string]func(*clock) Store{
+		"memory": func(c *clock) Store {
+			s := NewMemoryStore()
+			s.now = c.now
+			return s
+		},
+		"redis": func(c *clock) Store {
+			mr := miniredis.RunT(t)
+			client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+			t.Cleanup(func() { _ = client.Close() })
+			s := NewRedisStore(client)
+			s.now = c.now
+			return s
+		},
+	}
+}
+
+func take(t *testing.T, s Store, key string, r Rule) Result {
+	t.Helper()
+	res, err := s.Take(context.Background(), key, r)
+	if err != nil {
+		t.Fatalf("Take(%q): %v", key, err)
+	}
+	return res
+}
+
+func TestBurstThenRetryAfter(t *testing.T) {
+	for name, mk := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			c := newClock()
+			s := mk(c)
+			r := rule(3, "1m")
+
+			for i := 0; i < 3; i++ {
+				if res := take(t, s, "login:ip:10.0.0.1", r); !res.Allowed {
+					t.Fatalf("request ke-%d ditolak, burst belum habis", i+1)
+				}
+			}
+			res := take(t, s, "login:ip:10.0.0.1", r)
+			if res.Allowed {
+				t.Fatal("request ke-4 lolos padahal burst 3")
+			}
+			if res.RetryAfter != time.Minute {
+				t.Fatalf("RetryAfter = %v, mau 1m", res.RetryAfter)
+			}
+
+			c.advance(20 * time.Second)
+			res = take(t, s, "login:ip:10.0.0.1", r)
+			if res.Allowed || res.RetryAfter != 40*time.Second {
+				t.Fatalf("setelah 20s: %+v, mau ditolak dengan RetryAfter 40s", res)
+			}
+		})
+	}
+}
+
+func TestRefill(t *testing.T) {
+	for name, mk := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			c := newClock()
+			s := mk(c)
+			r := rule(2, "10s")
+
+			take(t, s, "k", r)
+			take(t, s, "k", r)
+			c.advance(10 * time.Second)
+			if !take(t, s, "k", r).Allowed {
+				t.Fatal("satu token harus terisi setelah 10s")
+			}
+			if take(t, s, "k", r).Allowed {
+				t.Fatal("hanya satu token yang terisi")
+			}
+
+			// isi ulang tidak melebihi burst walau lama tidak dipakai
+			c.advance(time.Hour)
+			for i := 0; i < 2; i++ {
+				if !take(t, s, "k", r).Allowed {
+					t.Fatalf("request ke-%d setelah bucket penuh ditolak", i+1)
+				}
+			}
+			if take(t, s, "k", r).Allowed {
+				t.Fatal("bucket terisi melebihi burst")
+			}
+		})
+	}
+}
+
+func TestKeysIndependent(t *testing.T) {
+	for name, mk := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			s := mk(newClock())
+			r := rule(1, "1m")
+
+			if !take(t, s, "check_in:user:1", r).Allowed {
+				t.Fatal("user 1 pertama kali harus lolos")
+			}
+			if take(t, s, "check_in:user:1", r).Allowed {
+				t.Fatal("user 1 kedua kali harus ditolak")
+			}
+			if !take(t, s, "check_in:user:2", r).Allowed {
+				t.Fatal("bucket user 2 tidak boleh ikut habis")
+			}
+		})
+	}
+}
+
+func TestMemorySweep(t *testing.T) {
+	c := newClock()
+	s := NewMemoryStore()
+	s.now = c.now
+	r := rule(2, "1s")
+
+	take(t, s, "a", r)
+	c.advance(sweepEvery)
+	take(t, s, "b", r) // memicu sweep; "a" sudah penuh lagi
+
+	if _, ok := s.tats["a"]; ok {
+		t.Fatal("bucket penuh tidak dibuang saat sweep")
+	}
+	if _, ok := s.tats["b"]; !ok {
+		t.Fatal("bucket yang baru dipakai ikut terbuang")
+	}
+}
+
+func TestRedisKeyExpires(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	s := NewRedisStore(client)
+
+	for i := 0; i < 3; i++ {
+		take(t, s, "api:ip:10.0.0.1", rule(5, "2s"))
+	}
+
+	// key hilang tepat saat bucket penuh lagi (3 token x 2s) supaya tidak menumpuk
+	// miniredis menghitung TTL dengan jam asli, beri toleransi sedikit
+	if ttl := mr.TTL(keyPrefix + "api:ip:10.0.0.1"); ttl <= 5*time.Second || ttl > 6*time.Second {
+		t.Fatalf("TTL = %v, mau 6s", ttl)
+	}
+}
+
+func TestRedisDownReturnsError(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	defer client.Close()
+	s := NewRedisStore(client)
+	mr.Close()
+
+	if _, err := s.Take(context.Background(), "k", rule(1, "1s")); err == nil {
+		t.Fatal("Redis mati harus mengembalikan error supaya middleware bisa meloloskan request")
+	}
+}
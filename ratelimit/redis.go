@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "mojo:rl:"
+
+// GCRA (lihat gcra) dihitung atomik di Redis supaya replika tidak saling
+// menimpa. Waktu dikirim dari aplikasi dalam ms agar sama dengan MemoryStore;
+// selisih jam antar instance beberapa detik hanya menggeser refill. Key
+// kedaluwarsa tepat saat bucket penuh lagi, jadi tidak menumpuk.
+var takeScript = redis.NewScript(`
+local burst  = tonumber(ARGV[1])
+local refill = tonumber(ARGV[2])
+local now    = tonumber(ARGV[3])
+
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if tat == nil or tat < now then
+  tat = now
+end
+local new = tat + refill
+local over = new - now - burst * refill
+if over > 0 then
+  return {0, over}
+end
+redis.call('SET', KEYS[1], string.format('%d', new), 'PX', string.format('%d', new - now))
+return {1, 0}
+`)
+
+// RedisStore berbagi bucket antar replika lewat Redis.
+type RedisStore struct {
+	client redis.UniversalClient
+	now    func() time.Time
+}
+
+func NewRedisStore(client redis.UniversalClient) *RedisStore {
+	return &RedisStore{client: client, now: time.Now}
+}
+
+// NewRedisStoreFromURL menerima format redis://[:password@]host:port/db.
+func NewRedisStoreFromURL(url string) (*RedisStore, error) {
+	opt, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	return NewRedisStore(redis.NewClient(opt)), nil
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, rule Rule) (Result, error) {
+	res, err := takeScript.Run(ctx, s.client, []string{keyPrefix + key},
+		rule.Burst, rule.Refill.Milliseconds(), s.now().UnixMilli(),
+	).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	if res[0] == 1 {
+		return Result{Allowed: true}, nil
+	}
+	return Result{RetryAfter: time.Duration(res[1]) * time.Millisecond}, nil
+}
+
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/config"
+	an "mojo-autotech/handler/anomaly"
+	doc "mojo-autotech/handler/apidoc"
+	a "mojo-autotech/handler/attedance"
+	hc "mojo-autotech/handler/health"
+	k "mojo-autotech/handler/kiosk"
+	m "mojo-autotech/handler/metrics"
+	o "mojo-autotech/handler/offline_sync"
+	org "mojo-autotech/handler/org_structure"
+	p "mojo-autotech/handler/payroll"
+	h "mojo-autotech/handler/user_authentication"
+	w "mojo-autotech/handler/work_location"
+	mid "mojo-autotech/middleware"
+	"mojo-autotech/ratelimit"
+	healthSvc "mojo-autotech/service/health"
+)
+
+// legacyDeprecatedAt adalah tanggal route tanpa prefix versi (/login,
+// /attendance/...) dinyatakan deprecated, dikirim di header Deprecation.
+var legacyDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// registerRoutes memasang semua handler ke router. Dipisah dari runServe supaya
+// test bisa menyusun router yang sama tanpa database (lihat routes_test.go).
+//
+// API bisnis ada di /api/v1. Kalau payload perlu berubah secara tidak
+// kompatibel, buat registerV2 untuk group /api/v2 di sebelahnya: endpoint yang
+// tidak berubah cukup memakai handler v1 lagi, app lama tetap di /api/v1.
+//
+// limiter nil = tanpa rate limit (dipakai test).
+func registerRoutes(router *gin.Engine, svc *services, health healthSvc.IHealthService, limiter ratelimit.Store, cfg *config.Config) {
+	// Probe, dokumentasi dan metrics bukan bagian kontrak API, tetap di root
+	hc.HttpHealthHandler(router, health)
+	if cfg.Features.APIDocs {
+		doc.HttpDocsHandler(router)
+	}
+	// listener terpisah dipasang di runServe
+	if cfg.Metrics.Enabled && cfg.Metrics.Listen == "" {
+		m.HttpMetricsHandler(router, cfg.Metrics.Token.Value())
+	}
+
+	lim := newLimits(limiter, cfg.RateLimit)
+	idem := newIdempotency(svc, cfg)
+	registerV1(router.Group("/api/v1"), svc, lim, idem, cfg)
+
+	// Alias sementara untuk app mobile yang sudah terpasang; dihapus setelah
+	// semua client pindah ke /api/v1
+	if cfg.Features.LegacyRoutes {
+		registerV1(router.Group("", mid.Deprecated(legacyDeprecatedAt, "/api/v1")), svc, lim, idem, cfg)
+	}
+}
+
+func registerV1(router gin.IRouter, svc *services, lim limits, idem idempotency, cfg *config.Config) {
+	router.Use(lim.api)
+	auth := mid.Auth(svc.jwt)
+	scope := mid.ReportScope(svc.org.Scope)
+	// group dibuat setelah Use supaya ikut membawa limiter api
+	group := func(name string) gin.IRouter {
+		if n := cfg.Srv.BodyLimits[name]; n > 0 {
+			return router.Group("", mid.BodyLimit(n))
+		}
+		return router
+	}
+	h.HttpHandler(group("auth"), svc.auth, auth, lim.login, idem.login, idem.create)
+	a.HttpAttendanceHandler(group("attendance"), svc.attendance, auth, lim.checkIn, idem.attendance)
+	p.HttpPayrollHandler(group("payroll"), svc.payroll, auth)
+	w.HttpWorkLocationHandler(group("work_location"), svc.workLocation, auth)
+	an.HttpAnomalyHandler(group("anomaly"), svc.anomaly, auth, scope)
+	org.HttpOrgHandler(group("org"), svc.org, auth)
+	if cfg.Features.Kiosk {
+		k.HttpKioskHandler(group("kiosk"), svc.kiosk, auth, lim.badge)
+	}
+	if cfg.Features.OfflineSync {
+		o.HttpOfflineSyncHandler(group("offline_sync"), svc.offlineSync, auth, scope)
+	}
+}
+
+// limits berisi middleware rate limit per policy. Bucket dipakai bersama oleh
+// /api/v1 dan alias legacy, jadi pindah prefix tidak menggandakan kuota.
+type limits struct {
+	api, login, checkIn, badge gin.HandlerFunc
+}
+
+func newLimits(store ratelimit.Store, cfg config.RateLimit) limits {
+	if store == nil || !cfg.Enabled {
+		pass := func(c *gin.Context) { c.Next() }
+		return limits{api: pass, login: pass, checkIn: pass, badge: pass}
+	}
+	return limits{
+		api:     mid.RateLimit(store, "api", cfg.API, mid.ByIP),
+		login:   mid.RateLimit(store, "login", cfg.Login, mid.ByIP),
+		checkIn: mid.RateLimit(store, "check_in", cfg.CheckIn, mid.ByUser),
+		badge:   mid.RateLimit(store, "badge", cfg.Badge, k.ByBadge),
+	}
+}
+
+// idempotency berisi middleware Idempotency-Key. Seperti limits, key yang
+// sama berlaku di /api/v1 maupun alias legacy.
+type idempotency struct {
+	login, create, attendance gin.HandlerFunc
+}
+
+func newIdempotency(svc *services, cfg *config.Config) idempotency {
+	if svc.idempotency == nil || !cfg.Idempotency.Enabled {
+		pass := func(c *gin.Context) { c.Next() }
+		return idempotency{login: pass, create: pass, attendance: pass}
+	}
+	policy := mid.IdempotencyPolicy{
+		TTL:    cfg.Idempotency.TTL,
+		Lock:   cfg.Idempotency.LockTimeout,
+		Secret: []byte(cfg.JWT.Secret.Value()),
+	}
+	login, create, attendance := policy, policy, policy
+	// login belum punya user dan tenant; token hasil login tidak disimpan
+	login.Scope, login.SkipSuccess = mid.ByIP, true
+	login.Tenant = h.LoginTenant(svc.tenants)
+	create.Scope = mid.ByUser
+	attendance.Scope = mid.ByUser
+	return idempotency{
+		login:      mid.Idempotency(svc.idempotency, login),
+		create:     mid.Idempotency(svc.idempotency, create),
+		attendance: mid.Idempotency(svc.idempotency, attendance),
+	}
+}
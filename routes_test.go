@@ -0,0 +1,167 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"mojo-autotech/apidoc"
+	"mojo-autotech/config"
+	mid "mojo-autotech/middleware"
+)
+
+var ginParam = regexp.MustCompile(`:([A-Za-z_]+)`)
+
+// TestRoutesMatchOpenAPI gagal kalau ada route yang tidak terdokumentasi di
+// apidoc/openapi.yaml atau path di spec yang tidak punya route.
+func TestRoutesMatchOpenAPI(t *testing.T) {
+	// alias lama sengaja tidak didokumentasikan
+	router := testRouter(false)
+
+	routes := map[string]bool{}
+	for _, r := range router.Routes() {
+		if r.Path == "/docs" || strings.HasPrefix(r.Path, "/docs/") {
+			continue // halaman dokumentasi itu sendiri
+		}
+		routes[r.Method+" "+ginParam.ReplaceAllString(r.Path, "{$1}")] = true
+	}
+
+	paths, err := apidoc.Paths()
+	if err != nil {
+		t.Fatalf("parse openapi.yaml: %v", err)
+	}
+	documented := map[string]bool{}
+	for path, methods := range paths {
+		for _, m := range methods {
+			documented[m+" "+path] = true
+		}
+	}
+
+	var missing, stale []string
+	for r := range routes {
+		if !documented[r] {
+			missing = append(missing, r)
+		}
+	}
+	for d := range documented {
+		if !routes[d] {
+			stale = append(stale, d)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(stale)
+	for _, r := range missing {
+		t.Errorf("route belum ada di openapi.yaml: %s", r)
+	}
+	for _, d := range stale {
+		t.Errorf("openapi.yaml mendokumentasikan route yang tidak ada: %s", d)
+	}
+}
+
+// TestLegacyRoutesMirrorV1 memastikan setiap route /api/v1 punya alias lama
+// (dan sebaliknya) selama FEATURE_LEGACY_ROUTES menyala.
+func TestLegacyRoutesMirrorV1(t *testing.T) {
+	router := testRouter(true)
+
+	v1, legacy := map[string]bool{}, map[string]bool{}
+	for _, r := range router.Routes() {
+		switch {
+		case strings.HasPrefix(r.Path, "/api/v1/"):
+			v1[r.Method+" "+strings.TrimPrefix(r.Path, "/api/v1")] = true
+		case strings.HasPrefix(r.Path, "/api/"), r.Path == "/docs", strings.HasPrefix(r.Path, "/docs/"),
+			r.Path == "/healthz", r.Path == "/readyz", r.Path == "/version":
+		default:
+			legacy[r.Method+" "+r.Path] = true
+		}
+	}
+	for r := range v1 {
+		if !legacy[r] {
+			t.Errorf("alias lama hilang untuk /api/v1: %s", r)
+		}
+	}
+	for r := range legacy {
+		if !v1[r] {
+			t.Errorf("route tanpa prefix tidak ada di /api/v1: %s", r)
+		}
+	}
+}
+
+func TestLegacyRouteDeprecationHeaders(t *testing.T) {
+	router := testRouter(true)
+
+	// body kosong gagal validasi sebelum menyentuh service
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("{}")))
+	if got := w.Header().Get("Deprecation"); got == "" {
+		t.Errorf("Deprecation header kosong di /login")
+	}
+	if got, want := w.Header().Get("Link"), `</api/v1/login>; rel="successor-version"`; got != want {
+		t.Errorf("Link = %q, want %q", got, want)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/login", strings.NewReader("{}")))
+	if got := w.Header().Get("Deprecation"); got != "" {
+		t.Errorf("/api/v1/login tidak boleh deprecated, Deprecation = %q", got)
+	}
+}
+
+// TestCORSIdempotencyHeaders: web admin mengirim Idempotency-Key ke /create
+// dan perlu membaca Idempotent-Replayed dengan CORS default.
+func TestCORSIdempotencyHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := config.Default().CORS
+	cfg.AllowOrigins = []string{"https://admin.mojo.id"}
+	router := gin.New()
+	router.Use(mid.CORS(cfg))
+	router.POST("/api/v1/create", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/create", nil)
+	req.Header.Set("Origin", "https://admin.mojo.id")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "authorization,content-type,idempotency-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code >= 300 || !strings.Contains(strings.ToLower(w.Header().Get("Access-Control-Allow-Headers")), "idempotency-key") {
+		t.Fatalf("preflight: status %d, Allow-Headers %q", w.Code, w.Header().Get("Access-Control-Allow-Headers"))
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/create", nil)
+	req.Header.Set("Origin", "https://admin.mojo.id")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if got := w.Header().Get("Access-Control-Expose-Headers"); !strings.Contains(strings.ToLower(got), "idempotent-replayed") {
+		t.Fatalf("Expose-Headers = %q, mau berisi Idempotent-Replayed", got)
+	}
+}
+
+func TestOpenAPIJSON(t *testing.T) {
+	b, err := apidoc.JSON()
+	if err != nil {
+		t.Fatalf("convert openapi.yaml: %v", err)
+	}
+	if !strings.Contains(string(b), `"openapi":"3.`) {
+		t.Fatalf("JSON spec tidak berisi versi openapi: %.80s", b)
+	}
+}
+
+// testRouter menyusun router lengkap (semua modul opsional menyala) tanpa
+// database; service tidak dipanggil selama test tidak melewati validasi.
+func testRouter(legacy bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Default()
+	cfg.Features.Kiosk = true
+	cfg.Features.OfflineSync = true
+	cfg.Features.APIDocs = true
+	cfg.Features.LegacyRoutes = legacy
+
+	router := gin.New()
+	registerRoutes(router, newServices(nil, cfg), nil, nil, cfg)
+	return router
+}
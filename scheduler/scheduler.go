@@ -0,0 +1,114 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Job dijalankan berulang setiap Interval. Run menerima context yang
+// dibatalkan saat Stop, jadi job panjang harus memeriksa ctx.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler menjalankan job latar belakang di goroutine masing-masing dan
+// menunggu semuanya selesai saat shutdown.
+type Scheduler struct {
+	jobs    []Job
+	beats   []atomic.Int64 // unix nano putaran terakhir tiap job, untuk Alive
+	running atomic.Bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Add mendaftarkan job; harus dipanggil sebelum Start.
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start menjalankan semua job. Putaran pertama langsung jalan, berikutnya per Interval.
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.beats = make([]atomic.Int64, len(s.jobs))
+	s.running.Store(true)
+	for i, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, i, job)
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, i int, job Job) {
+	defer s.wg.Done()
+	t := time.NewTicker(job.Interval)
+	defer t.Stop()
+
+	for {
+		s.beats[i].Store(time.Now().UnixNano())
+		s.runOnce(ctx, job)
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.ErrorContext(ctx, "job panic", "job", job.Name, "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+		}
+	}()
+	if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+		slog.ErrorContext(ctx, "job gagal", "job", job.Name, "err", err)
+	}
+}
+
+// Alive melaporkan scheduler sudah Start, belum Stop, dan tidak ada job yang
+// macet (putaran terakhir lebih lama dari dua kali interval + 1 menit).
+func (s *Scheduler) Alive() bool {
+	if !s.running.Load() {
+		return false
+	}
+	now := time.Now()
+	for i, job := range s.jobs {
+		last := time.Unix(0, s.beats[i].Load())
+		if now.Sub(last) > 2*job.Interval+time.Minute {
+			return false
+		}
+	}
+	return true
+}
+
+// Stop membatalkan semua job lalu menunggu yang sedang jalan selesai, paling
+// lama sampai ctx habis.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.running.Store(false)
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
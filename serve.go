@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+
+	"mojo-autotech/config"
+	mh "mojo-autotech/handler/metrics"
+	"mojo-autotech/i18n"
+	"mojo-autotech/logging"
+	"mojo-autotech/metrics"
+	mid "mojo-autotech/middleware"
+
+	"mojo-autotech/migration"
+	"mojo-autotech/ratelimit"
+	healthSvc "mojo-autotech/service/health"
+	"mojo-autotech/tracing"
+)
+
+// runServe menjalankan HTTP server (subcommand default).
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	var flags config.Flags
+	flags.Register(fs)
+	_ = fs.Parse(args)
+
+	cfg := loadConfig(flags)
+	logger, err := logging.New(os.Stdout, cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// juga mengalihkan package log standar (dipakai library) ke slog
+	slog.SetDefault(logger)
+	slog.Info("config dimuat", "summary", cfg.Summary())
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg)
+	if err != nil {
+		fatal("inisialisasi tracing gagal", err)
+	}
+
+	if cfg.Env == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	router := gin.New()
+	// Tanpa ini gin mempercayai X-Forwarded-For dari siapa saja dan IP absensi bisa dipalsukan
+	if err := router.SetTrustedProxies(cfg.Srv.TrustedProxies); err != nil {
+		fatal("TRUSTED_PROXIES tidak valid", err)
+	}
+	// otelgin paling depan: span server dibuat dari traceparent upstream dan
+	// trace_id-nya ikut tercatat di access log
+	router.Use(otelgin.Middleware(tracing.ServiceName, otelgin.WithFilter(traced)))
+	router.Use(mid.RequestID(), mid.AccessLog(), mid.Recovery())
+	if cfg.Metrics.Enabled {
+		router.Use(mid.Metrics())
+	}
+
+	router.Use(mid.SecurityHeaders(cfg.Security))
+	if len(cfg.CORS.AllowOrigins) > 0 {
+		router.Use(mid.CORS(cfg.CORS))
+	}
+	if err := i18n.RegisterValidator(); err != nil {
+		fatal("registrasi terjemahan validator gagal", err)
+	}
+	router.Use(mid.Language())
+	router.Use(mid.Errors())
+	router.Use(mid.BodyLimit(cfg.Srv.MaxBodyBytes))
+
+	db, err := config.NewDB(cfg)
+	if err != nil {
+		fatal("koneksi database gagal", err)
+	}
+	migrator, err := migration.New(db)
+	if err != nil {
+		fatal("memuat migrasi gagal", err)
+	}
+	if err := migrator.EnsureCurrent(context.Background()); err != nil {
+		fatal("menolak start", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		fatal("mengambil pool database gagal", err)
+	}
+
+	if cfg.Metrics.Enabled {
+		metrics.RegisterDB(sqlDB, cfg.Db.Name)
+	}
+
+	svc := newServices(db, cfg)
+	sched := newScheduler(svc, cfg)
+	health := healthSvc.NewHealthService(sqlDB, migrator, sched)
+
+	var limiter ratelimit.Store
+	closeLimiter := func() error { return nil }
+	if cfg.RateLimit.Enabled {
+		if limiter, closeLimiter, err = ratelimit.New(cfg.RateLimit); err != nil {
+			fatal("inisialisasi rate limit gagal", err)
+		}
+	}
+	registerRoutes(router, svc, health, limiter, cfg)
+
+	sched.Start(context.Background())
+
+	srv := &http.Server{
+		Addr:              cfg.Srv.Host + ":" + cfg.Srv.Port,
+		Handler:           router,
+		ReadTimeout:       cfg.Srv.ReadTimeout,
+		ReadHeaderTimeout: cfg.Srv.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Srv.WriteTimeout,
+		IdleTimeout:       cfg.Srv.IdleTimeout,
+		MaxHeaderBytes:    cfg.Srv.MaxHeaderBytes,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 2)
+	go func() {
+		slog.Info("server berjalan", "addr", "http://"+srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+	}()
+
+	// /metrics di port sendiri supaya tidak ikut terekspos lewat ingress publik
+	var metricsSrv *http.Server
+	if cfg.Metrics.Enabled && cfg.Metrics.Listen != "" {
+		mr := gin.New()
+		mr.Use(mid.Recovery(), mid.Errors())
+		mh.HttpMetricsHandler(mr, cfg.Metrics.Token.Value())
+		metricsSrv = &http.Server{
+			Addr:              cfg.Metrics.Listen,
+			Handler:           mr,
+			ReadHeaderTimeout: cfg.Srv.ReadHeaderTimeout,
+			WriteTimeout:      cfg.Srv.WriteTimeout,
+		}
+		go func() {
+			slog.Info("metrics berjalan", "addr", "http://"+metricsSrv.Addr+"/metrics")
+			if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serveErr <- err
+			}
+		}()
+	}
+
+	exitCode := 0
+	select {
+	case err := <-serveErr:
+		slog.Error("server berhenti karena error", "err", err)
+		exitCode = 1
+	case <-ctx.Done():
+		// sinyal kedua langsung mematikan proses tanpa menunggu drain
+		stop()
+		// /readyz gagal dulu dan server tetap melayani selama DrainDelay supaya
+		// load balancer sempat mencabut instance ini sebelum listener ditutup
+		health.SetDraining()
+		slog.Info("sinyal shutdown diterima, draining", "drain_delay", cfg.Srv.DrainDelay.String())
+		time.Sleep(cfg.Srv.DrainDelay)
+	}
+
+	// Urutan: berhenti terima request & tunggu yang jalan → hentikan job → tutup pool DB
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Srv.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("shutdown HTTP gagal", "err", err)
+		exitCode = 1
+	}
+	if metricsSrv != nil {
+		// dimatikan setelah server utama supaya scrape terakhir masih sempat
+		_ = metricsSrv.Shutdown(shutdownCtx)
+	}
+	if err := sched.Stop(shutdownCtx); err != nil {
+		slog.Error("shutdown scheduler gagal", "err", err)
+		exitCode = 1
+	}
+	if err := sqlDB.Close(); err != nil {
+		slog.Error("menutup database gagal", "err", err)
+	}
+	if err := closeLimiter(); err != nil {
+		slog.Error("menutup backend rate limit gagal", "err", err)
+	}
+	// terakhir supaya span dari request dan job yang baru selesai ikut terkirim
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("flush tracing gagal", "err", err)
+	}
+	slog.Info("server berhenti")
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+}
+
+// loadConfig memuat config berlapis dan berhenti dengan daftar error yang jelas
+// kalau tidak valid. Secret tidak pernah ikut tercetak.
+func loadConfig(flags config.Flags) *config.Config {
+	cfg, err := config.Load(flags)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return cfg
+}
+
+// traced: probe dan scrape tidak perlu span, hanya menambah noise di backend trace.
+func traced(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/healthz", "/readyz", "/metrics":
+		return false
+	}
+	return true
+}
+
+// fatal mencatat error startup lalu keluar; dipakai setelah logger siap.
+func fatal(msg string, err error) {
+	slog.Error(msg, "err", err)
+	os.Exit(1)
+}
@@ -0,0 +1,131 @@
+package anomaly
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+	entity "mojo-autotech/model/anomaly"
+	org "mojo-autotech/model/org_structure"
+	wl "mojo-autotech/model/work_location"
+
+	"gorm.io/gorm"
+)
+
+type (
+	Anomaly   = entity.Anomaly
+	PunchLog  = entity.PunchLog
+	ReviewReq = entity.ReviewReq
+)
+
+// historySize: jumlah punch sebelum dan sesudah punch baru yang dibandingkan rule.
+const historySize = 10
+
+// Punch adalah data check-in yang dikirim service absensi untuk dianalisis.
+type Punch struct {
+	UserID         uint
+	AttendanceID   *uint
+	WorkLocationID *uint
+	At             time.Time
+	Lat            *float64
+	Lng            *float64
+	IP             *string
+	MockLocation   bool
+}
+
+type IAnomalyService interface {
+	Inspect(ctx context.Context, p Punch) ([]Anomaly, error)
+	// List dan Review hanya untuk anomali milik user di scope reviewer.
+	List(ctx context.Context, status string, scope org.Scope) ([]Anomaly, error)
+	Review(ctx context.Context, id uint, reviewerID uint, scope org.Scope, req ReviewReq) (Anomaly, error)
+}
+
+type AnomalyService struct {
+	anomaly  entity.IAnomalyRepository
+	location wl.IWorkLocationRepository
+	engine   *Engine
+}
+
+func NewAnomalyService(repo entity.IAnomalyRepository, location wl.IWorkLocationRepository) *AnomalyService {
+	return &AnomalyService{
+		anomaly:  repo,
+		location: location,
+		engine:   DefaultEngine(),
+	}
+}
+
+// Inspect mencatat punch ke riwayat, menjalankan rule, dan memasukkan temuan ke antrean review.
+func (s *AnomalyService) Inspect(ctx context.Context, p Punch) ([]Anomaly, error) {
+	prev, err := s.anomaly.PunchesAround(ctx, p.UserID, p.At, historySize)
+	if err != nil {
+		return nil, err
+	}
+
+	var loc *wl.WorkLocation
+	if p.WorkLocationID != nil {
+		l, err := s.location.GetByID(ctx, *p.WorkLocationID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			loc = &l
+		}
+	}
+
+	cur := PunchLog{
+		UserID:         p.UserID,
+		AttendanceID:   p.AttendanceID,
+		WorkLocationID: p.WorkLocationID,
+		At:             p.At,
+		Lat:            p.Lat,
+		Lng:            p.Lng,
+		IP:             p.IP,
+		MockLocation:   p.MockLocation,
+	}
+	findings := s.engine.Evaluate(Input{Current: cur, Previous: prev, Location: loc})
+
+	items := make([]Anomaly, 0, len(findings))
+	for _, f := range findings {
+		items = append(items, Anomaly{
+			UserID:       p.UserID,
+			AttendanceID: p.AttendanceID,
+			Rule:         f.Rule,
+			Severity:     f.Severity,
+			Detail:       f.Detail,
+			Status:       entity.StatusOpen,
+		})
+	}
+	_, items, err = s.anomaly.SavePunch(ctx, cur, items)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *AnomalyService) List(ctx context.Context, status string, scope org.Scope) ([]Anomaly, error) {
+	return s.anomaly.List(ctx, status, scope)
+}
+
+func (s *AnomalyService) Review(ctx context.Context, id uint, reviewerID uint, scope org.Scope, req ReviewReq) (Anomaly, error) {
+	a, err := s.anomaly.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Anomaly{}, apperror.NotFound(i18n.AnomalyNotFound)
+		}
+		return Anomaly{}, err
+	}
+	if !scope.Allows(a.UserID) {
+		return Anomaly{}, apperror.Forbidden(i18n.OutsideReports)
+	}
+
+	out, err := s.anomaly.Review(ctx, id, req.Status, req.Note, reviewerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Anomaly{}, apperror.NotFound(i18n.AnomalyNotFound)
+		}
+		return Anomaly{}, err
+	}
+	return out, nil
+}
@@ -0,0 +1,216 @@
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	entity "mojo-autotech/model/anomaly"
+	wl "mojo-autotech/model/work_location"
+)
+
+// Input adalah punch yang sedang dianalisis beserta riwayat punch di sekitar
+// waktunya (urut dari yang terbaru) dan lokasi kerja yang diklaim. Punch
+// offline bisa dikirim belakangan, jadi Previous bisa berisi punch yang
+// waktunya sesudah Current.
+type Input struct {
+	Current  PunchLog
+	Previous []PunchLog
+	Location *wl.WorkLocation
+}
+
+type Finding struct {
+	Rule     string
+	Severity string
+	Detail   string
+}
+
+// Rule adalah satu pemeriksaan; nil berarti tidak ada temuan.
+type Rule interface {
+	Check(in Input) *Finding
+}
+
+// Engine menjalankan semua rule terhadap satu punch.
+type Engine struct {
+	rules []Rule
+}
+
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{rules: rules}
+}
+
+// DefaultEngine berisi rule bawaan dengan ambang yang cukup longgar untuk
+// menghindari false positive dari GPS yang melompat-lompat.
+func DefaultEngine() *Engine {
+	return NewEngine(
+		ImpossibleTravel{MaxSpeedKmh: 150, MinDistanceKm: 1},
+		RepeatedCoordinates{Threshold: 3},
+		IPNetworkMismatch{},
+		IPOutsideAllowlist{},
+		MockLocation{},
+	)
+}
+
+func (e *Engine) Evaluate(in Input) []Finding {
+	var out []Finding
+	for _, r := range e.rules {
+		if f := r.Check(in); f != nil {
+			out = append(out, *f)
+		}
+	}
+	return out
+}
+
+// ImpossibleTravel: jarak ke punch tepat sebelum atau sesudahnya (menurut
+// waktu punch, bukan urutan masuk) tidak mungkin ditempuh dalam selang waktunya.
+type ImpossibleTravel struct {
+	MaxSpeedKmh   float64
+	MinDistanceKm float64
+}
+
+func (r ImpossibleTravel) Check(in Input) *Finding {
+	cur := in.Current
+	if !hasCoord(cur) {
+		return nil
+	}
+	var before, after *PunchLog
+	for i := range in.Previous {
+		p := &in.Previous[i]
+		if !hasCoord(*p) {
+			continue
+		}
+		if !p.At.After(cur.At) {
+			if before == nil || p.At.After(before.At) {
+				before = p
+			}
+		} else if after == nil || p.At.Before(after.At) {
+			after = p
+		}
+	}
+	for _, other := range []*PunchLog{before, after} {
+		if other == nil {
+			continue
+		}
+		if f := r.compare(cur, *other); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+func (r ImpossibleTravel) compare(cur, other PunchLog) *Finding {
+	dist := haversineKm(*other.Lat, *other.Lng, *cur.Lat, *cur.Lng)
+	if dist < r.MinDistanceKm {
+		return nil
+	}
+	gap := cur.At.Sub(other.At).Abs()
+	hours := max(gap.Hours(), 1.0/60) // minimal 1 menit, hindari pembagian nol
+	speed := dist / hours
+	if speed <= r.MaxSpeedKmh {
+		return nil
+	}
+	which := "sebelumnya"
+	if other.At.After(cur.At) {
+		which = "sesudahnya"
+	}
+	return &Finding{
+		Rule:     entity.RuleImpossibleTravel,
+		Severity: entity.SeverityHigh,
+		Detail: fmt.Sprintf("%.1f km dari punch %s dalam %s (%.0f km/jam)",
+			dist, which, gap.Round(time.Second), speed),
+	}
+}
+
+// RepeatedCoordinates: GPS asli selalu bergeser sedikit; koordinat yang persis
+// sama berkali-kali biasanya berasal dari aplikasi pemalsu lokasi.
+type RepeatedCoordinates struct {
+	Threshold int
+}
+
+func (r RepeatedCoordinates) Check(in Input) *Finding {
+	cur := in.Current
+	if !hasCoord(cur) {
+		return nil
+	}
+	same := 1
+	for _, prev := range in.Previous {
+		if hasCoord(prev) && *prev.Lat == *cur.Lat && *prev.Lng == *cur.Lng {
+			same++
+		}
+	}
+	if same < r.Threshold {
+		return nil
+	}
+	return &Finding{
+		Rule:     entity.RuleRepeatedCoordinate,
+		Severity: entity.SeverityMedium,
+		Detail:   fmt.Sprintf("koordinat %.7f,%.7f identik pada %d punch terakhir", *cur.Lat, *cur.Lng, same),
+	}
+}
+
+// IPNetworkMismatch: koordinat mengaku berada di dalam geofence lokasi kerja,
+// tetapi IP tidak berasal dari jaringan lokasi tersebut.
+type IPNetworkMismatch struct{}
+
+func (IPNetworkMismatch) Check(in Input) *Finding {
+	loc, cur := in.Location, in.Current
+	if loc == nil || cur.IP == nil || !hasCoord(cur) || loc.Lat == nil || loc.Lng == nil || !loc.HasNetworks() {
+		return nil
+	}
+	if loc.IPPolicy == wl.IPPolicyFlag {
+		return nil // sudah dilaporkan IPOutsideAllowlist
+	}
+	distM := haversineKm(*loc.Lat, *loc.Lng, *cur.Lat, *cur.Lng) * 1000
+	if distM > float64(loc.RadiusM) || loc.ContainsIP(*cur.IP) {
+		return nil
+	}
+	return &Finding{
+		Rule:     entity.RuleIPNetworkMismatch,
+		Severity: entity.SeverityMedium,
+		Detail:   fmt.Sprintf("GPS di dalam lokasi %q tetapi IP %s bukan jaringan lokasi", loc.Name, *cur.IP),
+	}
+}
+
+// IPOutsideAllowlist: lokasi memakai policy FLAG dan IP check-in di luar allowlist.
+// Policy REJECT ditangani service absensi sebelum data disimpan.
+type IPOutsideAllowlist struct{}
+
+func (IPOutsideAllowlist) Check(in Input) *Finding {
+	loc, cur := in.Location, in.Current
+	if loc == nil || cur.IP == nil || loc.IPPolicy != wl.IPPolicyFlag || loc.IPAllowed(*cur.IP) {
+		return nil
+	}
+	return &Finding{
+		Rule:     entity.RuleIPOutsideAllowlist,
+		Severity: entity.SeverityMedium,
+		Detail:   fmt.Sprintf("IP %s di luar allowlist lokasi %q", *cur.IP, loc.Name),
+	}
+}
+
+// MockLocation: client melaporkan bahwa lokasi berasal dari mock provider.
+type MockLocation struct{}
+
+func (MockLocation) Check(in Input) *Finding {
+	if !in.Current.MockLocation {
+		return nil
+	}
+	return &Finding{
+		Rule:     entity.RuleMockLocation,
+		Severity: entity.SeverityHigh,
+		Detail:   "perangkat melaporkan mock location aktif",
+	}
+}
+
+func hasCoord(p PunchLog) bool {
+	return p.Lat != nil && p.Lng != nil
+}
+
+func haversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	const earthRadiusKm = 6371.0
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLng := (lng2 - lng1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,62 @@
+package anomaly
+
+import (
+	"testing"
+	"time"
+
+	entity "mojo-autotech/model/anomaly"
+)
+
+var wib = time.FixedZone("WIB", 7*3600)
+
+func punchAt(hhmm string, lat, lng float64) PunchLog {
+	t, err := time.ParseInLocation("2006-01-02 15:04", "2026-03-02 "+hhmm, wib)
+	if err != nil {
+		panic(err)
+	}
+	return PunchLog{UserID: 7, At: t, Lat: &lat, Lng: &lng}
+}
+
+// Bengkel Jakarta dan Bekasi berjarak sekitar 18 km.
+const (
+	jktLat, jktLng = -6.2000, 106.8166
+	bksLat, bksLng = -6.2383, 106.9756
+)
+
+func TestImpossibleTravelOutOfOrderOfflinePunch(t *testing.T) {
+	rule := ImpossibleTravel{MaxSpeedKmh: 150, MinDistanceKm: 1}
+
+	// check-out Bekasi 17:00 sudah masuk, lalu check-in offline Jakarta 08:00
+	// baru dikirim: selisihnya 9 jam, bukan "negatif" yang dibulatkan ke 1 menit
+	offline := Input{
+		Current:  punchAt("08:00", jktLat, jktLng),
+		Previous: []PunchLog{punchAt("17:00", bksLat, bksLng)},
+	}
+	if f := rule.Check(offline); f != nil {
+		t.Fatalf("punch offline yang datang belakangan ditandai: %+v", f)
+	}
+
+	// punch offline dibandingkan dengan tetangga terdekatnya dalam waktu,
+	// walau punch yang lebih baru ada di urutan pertama
+	between := Input{
+		Current: punchAt("12:00", bksLat, bksLng),
+		Previous: []PunchLog{
+			punchAt("17:00", bksLat, bksLng),
+			punchAt("12:05", jktLat, jktLng),
+			punchAt("08:00", jktLat, jktLng),
+		},
+	}
+	f := rule.Check(between)
+	if f == nil || f.Rule != entity.RuleImpossibleTravel {
+		t.Fatalf("18 km dalam 5 menit ke punch sesudahnya tidak ditandai: %+v", f)
+	}
+
+	// urutan normal tetap diperiksa seperti sebelumnya
+	online := Input{
+		Current:  punchAt("08:05", bksLat, bksLng),
+		Previous: []PunchLog{punchAt("08:00", jktLat, jktLng)},
+	}
+	if f := rule.Check(online); f == nil {
+		t.Fatal("18 km dalam 5 menit tidak ditandai")
+	}
+}
@@ -3,18 +3,52 @@ package attedance
 import (
 	"context"
 	"errors"
-	"fmt"
+	"log/slog"
 	"time"
 
+	"mojo-autotech/apperror"
+	"mojo-autotech/clock"
+	"mojo-autotech/config"
+	"mojo-autotech/i18n"
+	"mojo-autotech/metrics"
 	entity "mojo-autotech/model/attedance"
+	kioskEntity "mojo-autotech/model/kiosk"
+	wlEntity "mojo-autotech/model/work_location"
+	anomalySvc "mojo-autotech/service/anomaly"
+	"mojo-autotech/tracing"
+	"mojo-autotech/utils"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
 )
 
+var tracer = tracing.Tracer("service/attendance")
+
 type (
 	Attendance = entity.Attendance
 )
 
+const (
+	StatusPresent = entity.StatusPresent
+	StatusLate    = entity.StatusLate
+
+	dateLayout = "2006-01-02"
+)
+
+var (
+	ErrUnauthorized      = apperror.Unauthorized(i18n.Unauthorized)
+	ErrPeriodLocked      = apperror.Locked(i18n.PeriodLocked)
+	ErrNotCheckedIn      = apperror.Conflict(i18n.NotCheckedIn)
+	ErrAlreadyCheckedOut = apperror.Conflict(i18n.AlreadyCheckedOut)
+	ErrKioskQRInvalid    = apperror.Forbidden(i18n.KioskQRInvalid)
+	ErrKioskUnknown      = apperror.Forbidden(i18n.KioskUnknown)
+	ErrKioskInactive     = apperror.Forbidden(i18n.KioskInactive)
+	ErrIPNotAllowed      = apperror.Forbidden(i18n.IPNotAllowed)
+	ErrLocationUnknown   = apperror.Validation(i18n.LocationNotFound)
+	ErrLocationRequired  = apperror.Validation(i18n.LocationRequired)
+)
+
 // Request DTO (kamu boleh pindah ke package model jika mau samakan gaya dengan auth)
 type CheckInReq struct {
 	Activity string `json:"activity" binding:"required"`
@@ -22,59 +56,173 @@ type CheckInReq struct {
 	Lat      *float64 `json:"lat"`
 	Lng      *float64 `json:"lng"`
 	PhotoURL *string  `json:"photo_url"`
-	IP       string
+	KioskQR  *string  `json:"kiosk_qr"` // token QR yang di-scan dari layar kiosk
+	// WorkLocationID: lokasi kerja yang dipilih user; diabaikan kalau lewat kiosk
+	WorkLocationID *uint `json:"work_location_id"`
+	// MockLocation: dilaporkan aplikasi kalau OS mendeteksi mock location provider
+	MockLocation bool `json:"mock_location"`
+	IP           string
+	KioskID      *uint      `json:"-"` // diisi service kiosk saat punch atas nama karyawan
+	At           *time.Time `json:"-"` // waktu punch dari perangkat (sync offline); nil = sekarang
 }
 
+// CheckOutReq tidak membawa lokasi: tanggal kerja dihitung dari lokasi yang
+// tersimpan saat check-in.
 type CheckOutReq struct {
-	UserId uint
-	IP     string
+	UserId  uint
+	KioskQR *string `json:"kiosk_qr"`
+	IP      string
+	KioskID *uint      `json:"-"`
+	At      *time.Time `json:"-"`
 }
 
 type IAttendanceService interface {
 	CheckIn(ctx context.Context, req CheckInReq) (Attendance, bool, error) // (record, created?, err)
 	CheckOut(ctx context.Context, req CheckOutReq) (Attendance, error)
 	GetToday(ctx context.Context, userID uint) (Attendance, error)
+	Recompute(ctx context.Context, from, to time.Time) (int64, error)
+	AutoCheckOut(ctx context.Context, at time.Duration) (int64, error)
 }
 
 type AttendanceService struct {
 	// penamaan mirip auth: s.user_authentication → s.attedance
 	attedance entity.IAttendanceRepository
-	loc       *time.Location
+	kiosk     kioskEntity.IKioskRepository
+	location  wlEntity.IWorkLocationRepository
+	anomaly   anomalySvc.IAnomalyService // nil = deteksi anomali dimatikan
+	jwt       *utils.JWT
+	loc       *time.Location // timezone perusahaan; lokasi kerja boleh punya timezone sendiri
+	shift     config.Shift
+	clock     clock.Clock
 }
 
-func NewAttendanceService() *AttendanceService {
-	loc, _ := time.LoadLocation("Asia/Jakarta")
-	if loc == nil {
-		loc = time.FixedZone("WIB", 7*3600)
-	}
+func NewAttendanceService(
+	repo entity.IAttendanceRepository,
+	kiosk kioskEntity.IKioskRepository,
+	location wlEntity.IWorkLocationRepository,
+	anomaly anomalySvc.IAnomalyService,
+	jwt *utils.JWT,
+	loc *time.Location,
+	shift config.Shift,
+	clk clock.Clock,
+) *AttendanceService {
 	return &AttendanceService{
-		attedance: entity.NewAttendanceRepository(),
+		attedance: repo,
+		kiosk:     kiosk,
+		location:  location,
+		anomaly:   anomaly,
+		jwt:       jwt,
 		loc:       loc,
+		shift:     shift,
+		clock:     clk,
+	}
+}
+
+// workDate: tanggal kerja (tengah malam di loc) untuk punch pada at, atau
+// sekarang kalau at nil; punch offline memakai waktu perangkat.
+func (s *AttendanceService) workDate(at *time.Time, loc *time.Location) time.Time {
+	t := s.clock.Now()
+	if at != nil {
+		t = *at
+	}
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// zone: timezone lokasi kerja w, atau timezone perusahaan kalau w nil/tanpa timezone.
+func (s *AttendanceService) zone(w *wlEntity.WorkLocation) *time.Location {
+	if w == nil {
+		return s.loc
+	}
+	return w.Location(s.loc)
+}
+
+// workLocation mengambil lokasi kerja untuk punch baru; nil kalau id nil.
+// Lokasi nonaktif ditolak seperti lokasi yang tidak ada, supaya id-nya tidak
+// bisa dipakai melewati allowlist IP tenant.
+func (s *AttendanceService) workLocation(ctx context.Context, id *uint) (*wlEntity.WorkLocation, error) {
+	w, err := s.storedLocation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if w != nil && !w.IsActive {
+		return nil, ErrLocationUnknown
 	}
+	return w, nil
 }
 
-func (s *AttendanceService) workDateNow() time.Time {
-	now := time.Now().In(s.loc)
-	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.loc)
+// storedLocation mengambil lokasi yang tercatat di baris absensi, aktif atau
+// tidak; nil kalau id nil.
+func (s *AttendanceService) storedLocation(ctx context.Context, id *uint) (*wlEntity.WorkLocation, error) {
+	if id == nil {
+		return nil, nil
+	}
+	w, err := s.location.GetByID(ctx, *id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLocationUnknown
+		}
+		return nil, err
+	}
+	return &w, nil
 }
 
-func (s *AttendanceService) CheckIn(ctx context.Context, req CheckInReq) (Attendance, bool, error) {
+// attendanceAt mencari absensi user yang tanggal kerjanya memuat waktu at
+// (nil = sekarang). Tanggal kerja dihitung di timezone lokasi yang tersimpan
+// di baris absensi itu, bukan lokasi kiriman client; karena bisa berbeda satu
+// hari dari timezone perusahaan, dicari dalam rentang ±1 hari. Baris yang
+// belum check-out didahulukan. gorm.ErrRecordNotFound kalau tidak ada.
+func (s *AttendanceService) attendanceAt(ctx context.Context, userID uint, at *time.Time) (Attendance, *wlEntity.WorkLocation, error) {
+	d := s.workDate(at, s.loc)
+	rows, err := s.attedance.ListByUserBetween(ctx, userID, d.AddDate(0, 0, -1), d.AddDate(0, 0, 1))
+	if err != nil {
+		return Attendance{}, nil, err
+	}
+	found := -1
+	var foundWL *wlEntity.WorkLocation
+	for i, a := range rows {
+		wl, err := s.storedLocation(ctx, a.WorkLocationID)
+		// lokasi yang sudah dihapus: pakai timezone perusahaan
+		if err != nil && !errors.Is(err, ErrLocationUnknown) {
+			return Attendance{}, nil, err
+		}
+		if a.Date.Format(dateLayout) != s.workDate(at, s.zone(wl)).Format(dateLayout) {
+			continue
+		}
+		if a.CheckOutAt == nil {
+			return a, wl, nil
+		}
+		if found < 0 {
+			found, foundWL = i, wl
+		}
+	}
+	if found < 0 {
+		return Attendance{}, nil, gorm.ErrRecordNotFound
+	}
+	return rows[found], foundWL, nil
+}
+
+func (s *AttendanceService) CheckIn(ctx context.Context, req CheckInReq) (out Attendance, created bool, err error) {
+	ctx, span := tracer.Start(ctx, "AttendanceService.CheckIn", trace.WithAttributes(
+		attribute.Int64("user.id", int64(req.UserId)),
+		attribute.Bool("attendance.offline", req.At != nil),
+	))
+	defer tracing.End(span, &err)
+
 	if req.UserId == 0 {
-		return Attendance{}, false, errors.New("unauthorized")
+		return Attendance{}, false, ErrUnauthorized
 	}
 	if req.Activity == "" {
-		return Attendance{}, false, errors.New("activity wajib diisi")
+		return Attendance{}, false, apperror.Validation(i18n.ActivityRequired)
 	}
 
-	wd := s.workDateNow()
-
 	a := entity.Attendance{
 		UserID:          req.UserId,
-		Date:            wd,
+		CheckInAt:       req.At,
 		CheckInLat:      req.Lat,
 		CheckInLng:      req.Lng,
 		CheckInPhotoURL: req.PhotoURL,
-		Status:          "PRESENT",
+		WorkLocationID:  req.WorkLocationID,
 		Activity:        req.Activity,
 	}
 	if req.IP != "" {
@@ -82,28 +230,145 @@ func (s *AttendanceService) CheckIn(ctx context.Context, req CheckInReq) (Attend
 		a.CheckInIP = &ip
 	}
 
-	out, created, err := s.attedance.UpsertCheckIn(ctx, a)
+	k, err := s.resolveKiosk(ctx, req.KioskID, req.KioskQR)
 	if err != nil {
 		return Attendance{}, false, err
 	}
+	if k != nil {
+		a.CheckInKioskID = &k.ID
+		a.WorkLocationID = k.WorkLocationID
+		if a.CheckInLat == nil && a.CheckInLng == nil {
+			a.CheckInLat, a.CheckInLng = k.Lat, k.Lng
+		}
+	}
+
+	wl, err := s.workLocation(ctx, a.WorkLocationID)
+	if err != nil {
+		return Attendance{}, false, err
+	}
+	// Punch offline dikirim belakangan dari jaringan lain, IP-nya bukan IP saat punch
+	if req.At == nil {
+		if err := s.checkIP(ctx, wl, req.IP); err != nil {
+			return Attendance{}, false, err
+		}
+	}
+
+	// tanggal kerja dan jam masuk shift mengikuti timezone lokasi
+	a.Date = s.workDate(req.At, s.zone(wl))
+	if err := s.ensureNotLocked(ctx, a.Date); err != nil {
+		return Attendance{}, false, err
+	}
+	a.Status = s.checkInStatus(a.Date, req.At)
+
+	out, created, err = s.attedance.UpsertCheckIn(ctx, a)
+	if err != nil {
+		return Attendance{}, false, err
+	}
+	span.SetAttributes(attribute.Bool("attendance.created", created), attribute.String("attendance.status", out.Status))
+	if created {
+		metrics.CheckIns.WithLabelValues("created").Inc()
+		if out.Status == StatusLate {
+			metrics.LateArrivals.Inc()
+		}
+	} else {
+		metrics.CheckIns.WithLabelValues("updated").Inc()
+	}
+
+	p := anomalySvc.Punch{
+		UserID:         out.UserID,
+		AttendanceID:   &out.ID,
+		WorkLocationID: a.WorkLocationID,
+		At:             s.clock.Now(),
+		Lat:            a.CheckInLat,
+		Lng:            a.CheckInLng,
+		IP:             a.CheckInIP,
+		MockLocation:   req.MockLocation,
+	}
+	if k != nil {
+		// koordinat kiosk tetap, bukan posisi karyawan; cukup periksa IP
+		p.Lat, p.Lng = nil, nil
+	}
+	if req.At != nil {
+		p.At, p.IP = *req.At, nil
+	}
+	s.inspect(ctx, p)
 	return out, created, nil
 }
 
-func (s *AttendanceService) CheckOut(ctx context.Context, req CheckOutReq) (Attendance, error) {
+// checkIP menerapkan allowlist IP lokasi wl pada punch online. Tanpa lokasi
+// aktif, punch ditolak kalau tenant punya lokasi aktif yang membatasi IP; kalau
+// tidak, REJECT dan FLAG bisa dilewati dengan tidak mengirim work_location_id
+// atau memakai lokasi yang sudah dinonaktifkan.
+func (s *AttendanceService) checkIP(ctx context.Context, wl *wlEntity.WorkLocation, ip string) error {
+	if wl != nil && wl.IsActive {
+		if wl.IPPolicy == wlEntity.IPPolicyReject && !wl.IPAllowed(ip) {
+			return ErrIPNotAllowed
+		}
+		return nil
+	}
+	all, err := s.location.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, l := range all {
+		if l.IsActive && l.RestrictsIP() {
+			return ErrLocationRequired
+		}
+	}
+	return nil
+}
+
+// inspect meneruskan punch ke deteksi anomali. Absensi tetap sah walau
+// analisis gagal; temuan masuk antrean review.
+func (s *AttendanceService) inspect(ctx context.Context, p anomalySvc.Punch) {
+	if s.anomaly == nil {
+		return
+	}
+	if _, err := s.anomaly.Inspect(ctx, p); err != nil {
+		slog.WarnContext(ctx, "inspeksi anomali gagal", "user_id", p.UserID, "err", err)
+	}
+}
+
+func (s *AttendanceService) CheckOut(ctx context.Context, req CheckOutReq) (out Attendance, err error) {
+	ctx, span := tracer.Start(ctx, "AttendanceService.CheckOut", trace.WithAttributes(
+		attribute.Int64("user.id", int64(req.UserId)),
+		attribute.Bool("attendance.offline", req.At != nil),
+	))
+	defer tracing.End(span, &err)
+
 	if req.UserId == 0 {
-		return Attendance{}, errors.New("unauthorized")
+		return Attendance{}, ErrUnauthorized
 	}
 
-	wd := s.workDateNow()
+	k, err := s.resolveKiosk(ctx, req.KioskID, req.KioskQR)
+	if err != nil {
+		return Attendance{}, err
+	}
+	var kioskID *uint
+	if k != nil {
+		kioskID = &k.ID
+	}
 
 	// Pastikan sudah ada record & belum checkout
-	cur, err := s.attedance.GetByUserAndDate(ctx, req.UserId, wd)
+	cur, wl, err := s.attendanceAt(ctx, req.UserId, req.At)
 	if err != nil {
-		fmt.Println("err get by user and date:", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Attendance{}, ErrNotCheckedIn
+		}
+		return Attendance{}, err
+	}
+	wd := cur.Date
+	if err := s.ensureNotLocked(ctx, wd); err != nil {
 		return Attendance{}, err
 	}
 	if cur.CheckOutAt != nil {
-		return Attendance{}, errors.New("sudah check-out")
+		return Attendance{}, ErrAlreadyCheckedOut
+	}
+	// allowlist lokasi check-in berlaku juga untuk check-out online
+	if req.At == nil {
+		if err := s.checkIP(ctx, wl, req.IP); err != nil {
+			return Attendance{}, err
+		}
 	}
 
 	// Proses checkout
@@ -111,40 +376,105 @@ func (s *AttendanceService) CheckOut(ctx context.Context, req CheckOutReq) (Atte
 	if req.IP != "" {
 		ip = &req.IP
 	}
-	out, err := s.attedance.CheckOut(ctx, req.UserId, wd, ip)
+	out, err = s.attedance.CheckOut(ctx, req.UserId, wd, req.At, ip, kioskID)
 	if err != nil {
-		fmt.Println("err checkout:", err)
-		// cek apakah err karena record tidak ditemukan
-		if errors.Is(err, gormErrRecordNotFound()) {
-			return Attendance{}, errors.New("belum check-in hari ini")
+		// tidak ada baris ter-update: check-out lain menang balapan sejak attendanceAt
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Attendance{}, ErrAlreadyCheckedOut
 		}
 		return Attendance{}, err
 	}
+	metrics.CheckOuts.Inc()
+
+	// Check-out tidak membawa koordinat, jadi yang perlu direview hanya IP di
+	// luar allowlist lokasi FLAG. Check-out lain tidak dicatat supaya riwayat
+	// punch untuk rule GPS tidak terisi punch tanpa koordinat.
+	if req.At == nil && ip != nil && wl != nil && wl.IPPolicy == wlEntity.IPPolicyFlag && !wl.IPAllowed(*ip) {
+		s.inspect(ctx, anomalySvc.Punch{
+			UserID:         out.UserID,
+			AttendanceID:   &out.ID,
+			WorkLocationID: out.WorkLocationID,
+			At:             s.clock.Now(),
+			IP:             ip,
+		})
+	}
 	return out, nil
 }
 
-// helper untuk menghindari import langsung gorm di signature
-func gormErrRecordNotFound() error {
-	type notFound interface{ Error() string }
-	// signature kadang berasal dari gorm.ErrRecordNotFound
-	var e notFound
-	_ = e
+// checkInStatus: LATE kalau waktu punch (at, atau sekarang) melewati jam
+// masuk shift + toleransi pada tanggal kerja wd.
+func (s *AttendanceService) checkInStatus(wd time.Time, at *time.Time) string {
+	t := s.clock.Now()
+	if at != nil {
+		t = *at
+	}
+	lateAfter := wd.Add(s.shift.Start + s.shift.LateGrace)
+	if t.After(lateAfter) {
+		return StatusLate
+	}
+	return StatusPresent
+}
+
+// resolveKiosk mencari kiosk dari id (punch lewat kiosk) atau dari QR yang di-scan karyawan.
+// Mengembalikan nil kalau absensi tidak melibatkan kiosk.
+func (s *AttendanceService) resolveKiosk(ctx context.Context, kioskID *uint, qr *string) (*kioskEntity.Kiosk, error) {
+	var id uint
+	switch {
+	case kioskID != nil:
+		id = *kioskID
+	case qr != nil && *qr != "":
+		_, span := tracer.Start(ctx, "AttendanceService.ParseKioskQRToken")
+		parsed, err := s.jwt.ParseKioskQRToken(*qr)
+		span.End()
+		if err != nil {
+			return nil, ErrKioskQRInvalid
+		}
+		id = parsed
+	default:
+		return nil, nil
+	}
+
+	k, err := s.kiosk.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrKioskUnknown
+		}
+		return nil, err
+	}
+	if !k.IsActive {
+		return nil, ErrKioskInactive
+	}
+	return &k, nil
+}
+
+// ensureNotLocked menolak perubahan absensi pada tanggal yang sudah diekspor ke payroll.
+func (s *AttendanceService) ensureNotLocked(ctx context.Context, date time.Time) error {
+	locked, err := s.attedance.IsDateLocked(ctx, date)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return ErrPeriodLocked
+	}
 	return nil
 }
 
-func (s *AttendanceService) GetToday(ctx context.Context, userID uint) (Attendance, error) {
+// GetToday: "hari ini" dihitung di timezone lokasi check-in; kalau belum ada
+// absensi, objek kosong bertanggal hari ini di timezone perusahaan.
+func (s *AttendanceService) GetToday(ctx context.Context, userID uint) (out Attendance, err error) {
+	ctx, span := tracer.Start(ctx, "AttendanceService.GetToday", trace.WithAttributes(attribute.Int64("user.id", int64(userID))))
+	defer tracing.End(span, &err)
+
 	if userID == 0 {
-		return Attendance{}, errors.New("unauthorized")
+		return Attendance{}, ErrUnauthorized
 	}
-	wd := s.workDateNow()
-
-	rec, err := s.attedance.GetByUserAndDate(ctx, userID, wd)
+	rec, _, err := s.attendanceAt(ctx, userID, nil)
 	if err != nil {
 		// Kalau belum ada record hari ini, balikan objek "kosong" (200 OK)
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return Attendance{
 				UserID: userID,
-				Date:   wd,
+				Date:   s.workDate(nil, s.loc),
 				// CheckInAt/CheckOutAt biarkan nil → FE akan tampil "-"
 			}, nil
 		}
@@ -152,3 +482,25 @@ func (s *AttendanceService) GetToday(ctx context.Context, userID uint) (Attendan
 	}
 	return rec, nil
 }
+
+// Recompute menghitung ulang total menit kerja untuk rentang tanggal (mis. setelah
+// koreksi data manual). Mengembalikan jumlah baris yang diperbarui.
+func (s *AttendanceService) Recompute(ctx context.Context, from, to time.Time) (n int64, err error) {
+	ctx, span := tracer.Start(ctx, "AttendanceService.Recompute")
+	defer tracing.End(span, &err)
+
+	if to.Before(from) {
+		return 0, apperror.Validation(i18n.DateRangeInvalid)
+	}
+	return s.attedance.RecomputeTotals(ctx, from, to)
+}
+
+// AutoCheckOut menutup absensi hari-hari sebelumnya yang belum check-out,
+// dengan jam check-out = tanggal kerja + at di timezone lokasinya. Dipanggil
+// dari scheduler.
+func (s *AttendanceService) AutoCheckOut(ctx context.Context, at time.Duration) (n int64, err error) {
+	ctx, span := tracer.Start(ctx, "AttendanceService.AutoCheckOut")
+	defer tracing.End(span, &err)
+
+	return s.attedance.AutoCheckOut(ctx, s.clock.Now(), at, s.loc.String())
+}
@@ -0,0 +1,426 @@
+package attedance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	_ "time/tzdata"
+
+	"mojo-autotech/clock"
+	"mojo-autotech/config"
+	"mojo-autotech/fake"
+	org "mojo-autotech/model/org_structure"
+	wlEntity "mojo-autotech/model/work_location"
+	anomalySvc "mojo-autotech/service/anomaly"
+	"mojo-autotech/tenant"
+)
+
+// nama IANA asli: AutoCheckOut meneruskan nama timezone ke SQL
+var wib, _ = time.LoadLocation("Asia/Jakarta")
+
+func at(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, wib)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// newTestService: timezone perusahaan WIB, shift 08:00 dengan toleransi 15
+// menit, tanpa kiosk dan deteksi anomali. locations diberi id mulai 1.
+func newTestService(now string, locations ...wlEntity.WorkLocation) (*AttendanceService, *fake.AttendanceRepository, *clock.Fake) {
+	clk := clock.NewFake(at(now))
+	repo := fake.NewAttendanceRepository(clk)
+	locRepo := fake.NewWorkLocationRepository()
+	for _, w := range locations {
+		w, _ = locRepo.Create(tenant.WithID(context.Background(), 1), w)
+		repo.SetTimezone(w.ID, w.Timezone)
+	}
+	shift := config.Shift{Start: 8 * time.Hour, LateGrace: 15 * time.Minute}
+	return NewAttendanceService(repo, nil, locRepo, nil, nil, wib, shift, clk), repo, clk
+}
+
+func TestCheckInCreatesThenUpdates(t *testing.T) {
+	svc, _, clk := newTestService("2026-03-02 07:55")
+	ctx := tenant.WithID(context.Background(), 1)
+
+	first, created, err := svc.CheckIn(ctx, CheckInReq{UserId: 7, Activity: "servis"})
+	if err != nil || !created {
+		t.Fatalf("check-in pertama: created=%v err=%v", created, err)
+	}
+	if first.Status != StatusPresent {
+		t.Fatalf("status = %s, mau %s", first.Status, StatusPresent)
+	}
+
+	clk.Advance(2 * time.Hour)
+	second, created, err := svc.CheckIn(ctx, CheckInReq{UserId: 7, Activity: "spooring"})
+	if err != nil || created {
+		t.Fatalf("check-in kedua: created=%v err=%v", created, err)
+	}
+	if second.Activity != "spooring" {
+		t.Fatalf("activity = %q, mau diperbarui", second.Activity)
+	}
+	if !second.CheckInAt.Equal(at("2026-03-02 07:55")) {
+		t.Fatalf("check_in_at = %v, tidak boleh maju", second.CheckInAt)
+	}
+}
+
+func TestCheckInLateness(t *testing.T) {
+	cases := []struct {
+		now  string
+		want string
+	}{
+		{"2026-03-02 08:15", StatusPresent}, // tepat batas toleransi
+		{"2026-03-02 08:16", StatusLate},
+	}
+	for _, tc := range cases {
+		t.Run(tc.now, func(t *testing.T) {
+			svc, _, _ := newTestService(tc.now)
+			out, _, err := svc.CheckIn(tenant.WithID(context.Background(), 1), CheckInReq{UserId: 1, Activity: "x"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.Status != tc.want {
+				t.Fatalf("status = %s, mau %s", out.Status, tc.want)
+			}
+		})
+	}
+}
+
+// Tanggal kerja mengikuti timezone kantor, bukan UTC: 23:59 dan 00:01 WIB
+// adalah dua hari kerja berbeda walau di UTC masih tanggal yang sama.
+func TestWorkDateMidnightBoundary(t *testing.T) {
+	svc, repo, clk := newTestService("2026-03-02 23:59")
+	ctx := tenant.WithID(context.Background(), 1)
+
+	if _, _, err := svc.CheckIn(ctx, CheckInReq{UserId: 3, Activity: "lembur"}); err != nil {
+		t.Fatal(err)
+	}
+	clk.Advance(2 * time.Minute)
+	_, created, err := svc.CheckIn(ctx, CheckInReq{UserId: 3, Activity: "pagi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created {
+		t.Fatal("check-in 00:01 harus membuat absensi hari baru")
+	}
+
+	dates := map[string]bool{}
+	for _, a := range repo.All() {
+		dates[a.Date.Format("2006-01-02")] = true
+	}
+	if !dates["2026-03-02"] || !dates["2026-03-03"] {
+		t.Fatalf("tanggal kerja = %v, mau 2026-03-02 dan 2026-03-03", dates)
+	}
+
+	today, err := svc.GetToday(ctx, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if today.Activity != "pagi" {
+		t.Fatalf("GetToday setelah tengah malam = %q, mau absensi hari baru", today.Activity)
+	}
+}
+
+// Punch offline dikirim setelah lewat tengah malam tetap masuk tanggal
+// saat punch terjadi.
+func TestOfflinePunchUsesPunchDate(t *testing.T) {
+	svc, repo, _ := newTestService("2026-03-03 00:30")
+	punch := at("2026-03-02 17:05")
+
+	if _, _, err := svc.CheckIn(tenant.WithID(context.Background(), 1), CheckInReq{UserId: 4, Activity: "x", At: &punch}); err != nil {
+		t.Fatal(err)
+	}
+	rows := repo.All()
+	if len(rows) != 1 || rows[0].Date.Format("2006-01-02") != "2026-03-02" {
+		t.Fatalf("absensi = %+v, mau tanggal 2026-03-02", rows)
+	}
+}
+
+func TestCheckOut(t *testing.T) {
+	svc, _, clk := newTestService("2026-03-02 08:00")
+	ctx := tenant.WithID(context.Background(), 1)
+
+	if _, err := svc.CheckOut(ctx, CheckOutReq{UserId: 5}); !errors.Is(err, ErrNotCheckedIn) {
+		t.Fatalf("check-out tanpa check-in: err = %v, mau ErrNotCheckedIn", err)
+	}
+
+	if _, _, err := svc.CheckIn(ctx, CheckInReq{UserId: 5, Activity: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	clk.Advance(8*time.Hour + 30*time.Minute)
+	out, err := svc.CheckOut(ctx, CheckOutReq{UserId: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.TotalMinutes != 510 {
+		t.Fatalf("total_minutes = %d, mau 510", out.TotalMinutes)
+	}
+
+	if _, err := svc.CheckOut(ctx, CheckOutReq{UserId: 5}); !errors.Is(err, ErrAlreadyCheckedOut) {
+		t.Fatalf("check-out kedua: err = %v, mau ErrAlreadyCheckedOut", err)
+	}
+}
+
+func TestLockedDateRejected(t *testing.T) {
+	svc, repo, _ := newTestService("2026-03-02 08:00")
+	repo.LockDate(at("2026-03-02 00:00"))
+
+	if _, _, err := svc.CheckIn(tenant.WithID(context.Background(), 1), CheckInReq{UserId: 1, Activity: "x"}); !errors.Is(err, ErrPeriodLocked) {
+		t.Fatalf("err = %v, mau ErrPeriodLocked", err)
+	}
+}
+
+func TestGetTodayWithoutRecord(t *testing.T) {
+	svc, _, _ := newTestService("2026-03-02 10:00")
+
+	out, err := svc.GetToday(tenant.WithID(context.Background(), 1), 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.ID != 0 || out.CheckInAt != nil || !out.Date.Equal(at("2026-03-02 00:00")) {
+		t.Fatalf("GetToday tanpa absensi = %+v, mau objek kosong hari ini", out)
+	}
+}
+
+func TestAutoCheckOutClosesPreviousDays(t *testing.T) {
+	svc, repo, clk := newTestService("2026-03-02 08:00")
+	ctx := tenant.WithID(context.Background(), 1)
+
+	if _, _, err := svc.CheckIn(ctx, CheckInReq{UserId: 2, Activity: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	clk.Set(at("2026-03-03 01:00"))
+	n, err := svc.AutoCheckOut(ctx, 17*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("ditutup %d, mau 1", n)
+	}
+	got := repo.All()[0]
+	if got.CheckOutAt == nil || !got.CheckOutAt.Equal(at("2026-03-02 17:00")) {
+		t.Fatalf("check_out_at = %v, mau 2026-03-02 17:00 WIB", got.CheckOutAt)
+	}
+}
+
+// Lokasi di WITA (UTC+8) memakai tanggal kerja dan jam masuk shift lokalnya,
+// bukan timezone perusahaan.
+func TestLocationTimezone(t *testing.T) {
+	makassar := wlEntity.WorkLocation{Name: "Makassar", Timezone: "Asia/Makassar", IsActive: true}
+	loc := uint(1)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	t.Run("tanggal kerja", func(t *testing.T) {
+		// 23:30 WIB = 00:30 WITA keesokan harinya
+		svc, repo, _ := newTestService("2026-03-02 23:30", makassar)
+		if _, _, err := svc.CheckIn(ctx, CheckInReq{UserId: 1, Activity: "x", WorkLocationID: &loc}); err != nil {
+			t.Fatal(err)
+		}
+		if got := repo.All()[0].Date.Format("2006-01-02"); got != "2026-03-03" {
+			t.Fatalf("tanggal kerja = %s, mau 2026-03-03 (WITA)", got)
+		}
+	})
+
+	t.Run("keterlambatan", func(t *testing.T) {
+		// 07:20 WIB = 08:20 WITA: tepat waktu di WIB, terlambat di WITA
+		svc, _, _ := newTestService("2026-03-02 07:20", makassar)
+		out, _, err := svc.CheckIn(ctx, CheckInReq{UserId: 1, Activity: "x", WorkLocationID: &loc})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.Status != StatusLate {
+			t.Fatalf("status = %s, mau %s", out.Status, StatusLate)
+		}
+	})
+
+	t.Run("check-out dan hari ini", func(t *testing.T) {
+		// 23:10-23:40 WIB = 00:10-00:40 WITA: tanggal kerja 2026-03-03, yang
+		// di WIB belum dimulai. Lokasi diambil dari absensi, bukan dari client.
+		svc, _, clk := newTestService("2026-03-02 23:10", makassar)
+		if _, _, err := svc.CheckIn(ctx, CheckInReq{UserId: 1, Activity: "x", WorkLocationID: &loc}); err != nil {
+			t.Fatal(err)
+		}
+		clk.Advance(30 * time.Minute)
+		today, err := svc.GetToday(ctx, 1)
+		if err != nil || today.ID == 0 || today.Date.Format("2006-01-02") != "2026-03-03" {
+			t.Fatalf("GetToday = %+v, %v; mau absensi 2026-03-03", today, err)
+		}
+		out, err := svc.CheckOut(ctx, CheckOutReq{UserId: 1})
+		if err != nil {
+			t.Fatalf("check-out: %v", err)
+		}
+		if out.Date.Format("2006-01-02") != "2026-03-03" {
+			t.Fatalf("check-out menutup %s, mau 2026-03-03", out.Date.Format("2006-01-02"))
+		}
+	})
+
+	t.Run("lokasi tidak ada", func(t *testing.T) {
+		svc, _, _ := newTestService("2026-03-02 08:00")
+		if _, _, err := svc.CheckIn(ctx, CheckInReq{UserId: 1, Activity: "x", WorkLocationID: &loc}); !errors.Is(err, ErrLocationUnknown) {
+			t.Fatalf("err = %v, mau ErrLocationUnknown", err)
+		}
+	})
+}
+
+// Punch check-out offline untuk kemarin yang baru tersinkron setelah check-in
+// hari ini menutup absensi kemarin, bukan hari ini.
+func TestOfflineCheckOutForPreviousDay(t *testing.T) {
+	svc, repo, clk := newTestService("2026-03-02 08:00")
+	ctx := tenant.WithID(context.Background(), 1)
+
+	if _, _, err := svc.CheckIn(ctx, CheckInReq{UserId: 1, Activity: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	clk.Set(at("2026-03-03 08:00"))
+	if _, _, err := svc.CheckIn(ctx, CheckInReq{UserId: 1, Activity: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	punch := at("2026-03-02 17:05")
+	out, err := svc.CheckOut(ctx, CheckOutReq{UserId: 1, At: &punch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Date.Format("2006-01-02") != "2026-03-02" {
+		t.Fatalf("check-out menutup %s, mau 2026-03-02", out.Date.Format("2006-01-02"))
+	}
+	for _, a := range repo.All() {
+		if a.Date.Format("2006-01-02") == "2026-03-03" && a.CheckOutAt != nil {
+			t.Fatal("absensi hari ini ikut ditutup")
+		}
+	}
+}
+
+// Auto check-out menunggu tanggal kerja lewat di timezone lokasi masing-masing.
+func TestAutoCheckOutPerLocation(t *testing.T) {
+	makassar := wlEntity.WorkLocation{Name: "Makassar", Timezone: "Asia/Makassar", IsActive: true}
+	loc := uint(1)
+	svc, repo, clk := newTestService("2026-03-02 08:00", makassar)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	if _, _, err := svc.CheckIn(ctx, CheckInReq{UserId: 1, Activity: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := svc.CheckIn(ctx, CheckInReq{UserId: 2, Activity: "x", WorkLocationID: &loc}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 23:30 WIB: sudah 2026-03-03 di WITA, masih 2026-03-02 di WIB
+	clk.Set(at("2026-03-02 23:30"))
+	n, err := svc.AutoCheckOut(ctx, 17*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("ditutup %d, mau 1 (hanya lokasi WITA)", n)
+	}
+	for _, a := range repo.All() {
+		switch a.UserID {
+		case 1:
+			if a.CheckOutAt != nil {
+				t.Fatal("absensi WIB ditutup sebelum hari kerjanya lewat")
+			}
+		case 2:
+			// 17:00 WITA = 16:00 WIB
+			if a.CheckOutAt == nil || !a.CheckOutAt.Equal(at("2026-03-02 16:00")) {
+				t.Fatalf("check_out_at WITA = %v, mau 16:00 WIB", a.CheckOutAt)
+			}
+		}
+	}
+}
+
+// inspections mencatat punch yang dikirim ke deteksi anomali.
+type inspections []anomalySvc.Punch
+
+func (i *inspections) Inspect(_ context.Context, p anomalySvc.Punch) ([]anomalySvc.Anomaly, error) {
+	*i = append(*i, p)
+	return nil, nil
+}
+
+func (*inspections) List(context.Context, string, org.Scope) ([]anomalySvc.Anomaly, error) {
+	return nil, nil
+}
+
+func (*inspections) Review(context.Context, uint, uint, org.Scope, anomalySvc.ReviewReq) (anomalySvc.Anomaly, error) {
+	return anomalySvc.Anomaly{}, nil
+}
+
+func TestIPPolicyReject(t *testing.T) {
+	workshop := wlEntity.WorkLocation{Name: "Workshop", Networks: "10.10.0.0/16", IPPolicy: wlEntity.IPPolicyReject, IsActive: true}
+	loc := uint(1)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	t.Run("tanpa lokasi", func(t *testing.T) {
+		svc, _, _ := newTestService("2026-03-02 08:00", workshop)
+		if _, _, err := svc.CheckIn(ctx, CheckInReq{UserId: 1, Activity: "x", IP: "203.0.113.9"}); !errors.Is(err, ErrLocationRequired) {
+			t.Fatalf("err = %v, mau ErrLocationRequired", err)
+		}
+		// punch offline tidak membawa IP saat punch
+		punch := at("2026-03-02 07:50")
+		if _, _, err := svc.CheckIn(ctx, CheckInReq{UserId: 1, Activity: "x", IP: "203.0.113.9", At: &punch}); err != nil {
+			t.Fatalf("punch offline: %v", err)
+		}
+	})
+
+	t.Run("check-in di luar allowlist", func(t *testing.T) {
+		svc, _, _ := newTestService("2026-03-02 08:00", workshop)
+		if _, _, err := svc.CheckIn(ctx, CheckInReq{UserId: 1, Activity: "x", WorkLocationID: &loc, IP: "203.0.113.9"}); !errors.Is(err, ErrIPNotAllowed) {
+			t.Fatalf("err = %v, mau ErrIPNotAllowed", err)
+		}
+	})
+
+	t.Run("check-out di luar allowlist", func(t *testing.T) {
+		svc, _, clk := newTestService("2026-03-02 08:00", workshop)
+		if _, _, err := svc.CheckIn(ctx, CheckInReq{UserId: 1, Activity: "x", WorkLocationID: &loc, IP: "10.10.1.5"}); err != nil {
+			t.Fatal(err)
+		}
+		clk.Advance(9 * time.Hour)
+		if _, err := svc.CheckOut(ctx, CheckOutReq{UserId: 1, IP: "203.0.113.9"}); !errors.Is(err, ErrIPNotAllowed) {
+			t.Fatalf("err = %v, mau ErrIPNotAllowed", err)
+		}
+		if _, err := svc.CheckOut(ctx, CheckOutReq{UserId: 1, IP: "10.10.1.5"}); err != nil {
+			t.Fatalf("check-out dari jaringan workshop: %v", err)
+		}
+	})
+
+	t.Run("id lokasi nonaktif", func(t *testing.T) {
+		off := workshop
+		off.IsActive, off.IPPolicy = false, wlEntity.IPPolicyOff
+		svc, _, _ := newTestService("2026-03-02 08:00", workshop, off)
+		offID := uint(2)
+		if _, _, err := svc.CheckIn(ctx, CheckInReq{UserId: 1, Activity: "x", WorkLocationID: &offID, IP: "203.0.113.9"}); !errors.Is(err, ErrLocationUnknown) {
+			t.Fatalf("err = %v, mau ErrLocationUnknown", err)
+		}
+	})
+
+	t.Run("lokasi nonaktif tidak dihitung", func(t *testing.T) {
+		off := workshop
+		off.IsActive = false
+		svc, _, _ := newTestService("2026-03-02 08:00", off)
+		if _, _, err := svc.CheckIn(ctx, CheckInReq{UserId: 1, Activity: "x", IP: "203.0.113.9"}); err != nil {
+			t.Fatalf("err = %v, mau diterima", err)
+		}
+	})
+}
+
+func TestIPPolicyFlagOnCheckOut(t *testing.T) {
+	workshop := wlEntity.WorkLocation{Name: "Workshop", Networks: "10.10.0.0/16", IPPolicy: wlEntity.IPPolicyFlag, IsActive: true}
+	loc := uint(1)
+	ctx := tenant.WithID(context.Background(), 1)
+	svc, _, clk := newTestService("2026-03-02 08:00", workshop)
+	var seen inspections
+	svc.anomaly = &seen
+
+	if _, _, err := svc.CheckIn(ctx, CheckInReq{UserId: 1, Activity: "x", WorkLocationID: &loc, IP: "10.10.1.5"}); err != nil {
+		t.Fatal(err)
+	}
+	clk.Advance(9 * time.Hour)
+	if _, err := svc.CheckOut(ctx, CheckOutReq{UserId: 1, IP: "203.0.113.9"}); err != nil {
+		t.Fatalf("FLAG tetap menerima check-out: %v", err)
+	}
+	if len(seen) != 2 || seen[1].IP == nil || *seen[1].IP != "203.0.113.9" || seen[1].WorkLocationID == nil {
+		t.Fatalf("punch ke deteksi anomali = %+v, mau check-in dan check-out", seen)
+	}
+}
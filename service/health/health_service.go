@@ -0,0 +1,130 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"mojo-autotech/version"
+)
+
+// Pinger dipenuhi *sql.DB.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// SchemaVersion dipenuhi *migration.Migrator.
+type SchemaVersion interface {
+	Current(ctx context.Context) (int64, error)
+	Latest() int64
+}
+
+// Liveness dipenuhi *scheduler.Scheduler.
+type Liveness interface {
+	Alive() bool
+}
+
+type Check struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+type Readiness struct {
+	Ready  bool    `json:"ready"`
+	Checks []Check `json:"checks"`
+}
+
+type VersionRes struct {
+	version.Info
+	SchemaVersion int64 `json:"schema_version"`
+	SchemaLatest  int64 `json:"schema_latest"`
+}
+
+type IHealthService interface {
+	Ready(ctx context.Context) Readiness
+	Version(ctx context.Context) VersionRes
+	SetDraining()
+}
+
+type HealthService struct {
+	db        Pinger
+	schema    SchemaVersion
+	scheduler Liveness
+	draining  atomic.Bool
+}
+
+func NewHealthService(db Pinger, schema SchemaVersion, scheduler Liveness) *HealthService {
+	return &HealthService{
+		db:        db,
+		schema:    schema,
+		scheduler: scheduler,
+	}
+}
+
+// checkTimeout: probe orchestrator biasanya timeout 1-5 detik
+const checkTimeout = 2 * time.Second
+
+// Ready menjalankan semua pemeriksaan. Selama graceful shutdown selalu gagal
+// supaya load balancer berhenti mengirim request baru.
+func (s *HealthService) Ready(ctx context.Context) Readiness {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	checks := []Check{
+		check("shutdown", func() error {
+			if s.draining.Load() {
+				return fmt.Errorf("server sedang shutdown")
+			}
+			return nil
+		}),
+		check("database", func() error { return s.db.PingContext(ctx) }),
+		check("migrations", func() error {
+			cur, err := s.schema.Current(ctx)
+			if err != nil {
+				return err
+			}
+			if latest := s.schema.Latest(); cur < latest {
+				return fmt.Errorf("skema di versi %d, binary butuh %d", cur, latest)
+			}
+			return nil
+		}),
+		check("scheduler", func() error {
+			if !s.scheduler.Alive() {
+				return fmt.Errorf("scheduler tidak berjalan")
+			}
+			return nil
+		}),
+	}
+
+	ready := true
+	for _, c := range checks {
+		ready = ready && c.OK
+	}
+	return Readiness{Ready: ready, Checks: checks}
+}
+
+func check(name string, fn func() error) Check {
+	if err := fn(); err != nil {
+		return Check{Name: name, Error: err.Error()}
+	}
+	return Check{Name: name, OK: true}
+}
+
+// Version: info build + versi skema. Versi skema 0 kalau database tidak bisa dibaca.
+func (s *HealthService) Version(ctx context.Context) VersionRes {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	cur, _ := s.schema.Current(ctx)
+	return VersionRes{
+		Info:          version.Get(),
+		SchemaVersion: cur,
+		SchemaLatest:  s.schema.Latest(),
+	}
+}
+
+func (s *HealthService) SetDraining() {
+	s.draining.Store(true)
+}
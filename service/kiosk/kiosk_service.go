@@ -0,0 +1,231 @@
+package kiosk
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/clock"
+	"mojo-autotech/config"
+	"mojo-autotech/i18n"
+	entity "mojo-autotech/model/kiosk"
+	attSvc "mojo-autotech/service/attedance"
+	"mojo-autotech/tenant"
+	"mojo-autotech/utils"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type (
+	Kiosk            = entity.Kiosk
+	RegisterKioskReq = entity.RegisterKioskReq
+	RegisterKioskRes = entity.RegisterKioskRes
+	SetBadgeReq      = entity.SetBadgeReq
+	PunchReq         = entity.PunchReq
+	QRRes            = entity.QRRes
+)
+
+// defaultKioskActivity dipakai kalau karyawan tidak mengisi activity di kiosk.
+const defaultKioskActivity = "Check-in via kiosk"
+
+type IKioskService interface {
+	Register(ctx context.Context, req RegisterKioskReq) (RegisterKioskRes, error)
+	List(ctx context.Context) ([]Kiosk, error)
+	SetBadge(ctx context.Context, req SetBadgeReq) error
+	Authenticate(ctx context.Context, deviceKey string) (Kiosk, error)
+	IssueQR(ctx context.Context, k Kiosk) (QRRes, error)
+	Punch(ctx context.Context, k Kiosk, req PunchReq, ip string) (attSvc.Attendance, bool, error)
+}
+
+var (
+	ErrBadCredentials  = apperror.Unauthorized(i18n.BadgeBadCredentials)
+	ErrAccountInactive = apperror.Forbidden(i18n.AccountInactive)
+	ErrBadgeLocked     = apperror.Locked(i18n.BadgeLocked)
+)
+
+type KioskService struct {
+	kiosk      entity.IKioskRepository
+	attendance attSvc.IAttendanceService
+	jwt        *utils.JWT
+	cfg        config.Kiosk
+	clock      clock.Clock
+}
+
+func NewKioskService(repo entity.IKioskRepository, attendance attSvc.IAttendanceService, jwt *utils.JWT, cfg config.Kiosk, clk clock.Clock) *KioskService {
+	return &KioskService{
+		kiosk:      repo,
+		attendance: attendance,
+		jwt:        jwt,
+		cfg:        cfg,
+		clock:      clk,
+	}
+}
+
+func (s *KioskService) Register(ctx context.Context, req RegisterKioskReq) (RegisterKioskRes, error) {
+	key, err := newDeviceKey()
+	if err != nil {
+		return RegisterKioskRes{}, err
+	}
+
+	k, err := s.kiosk.Create(ctx, Kiosk{
+		Code:           strings.TrimSpace(req.Code),
+		Name:           req.Name,
+		Location:       req.Location,
+		WorkLocationID: req.WorkLocationID,
+		Lat:            req.Lat,
+		Lng:            req.Lng,
+		KeyHash:        hashKey(key),
+		IsActive:       true,
+	})
+	if err != nil {
+		return RegisterKioskRes{}, err
+	}
+	return RegisterKioskRes{Kiosk: k, DeviceKey: key}, nil
+}
+
+func (s *KioskService) List(ctx context.Context) ([]Kiosk, error) {
+	return s.kiosk.List(ctx)
+}
+
+func (s *KioskService) SetBadge(ctx context.Context, req SetBadgeReq) error {
+	hash, err := utils.HashPassword(req.PIN)
+	if err != nil {
+		return err
+	}
+	if err := s.kiosk.SetBadge(ctx, req.UserID, strings.TrimSpace(req.BadgeID), hash); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound(i18n.UserNotFound)
+		}
+		return err
+	}
+	return nil
+}
+
+// Authenticate mencari kiosk dari device key (header X-Kiosk-Key). Request
+// kiosk berikutnya memakai tenant milik kiosk (Kiosk.TenantID).
+func (s *KioskService) Authenticate(ctx context.Context, deviceKey string) (Kiosk, error) {
+	if deviceKey == "" {
+		return Kiosk{}, apperror.Unauthorized(i18n.KioskKeyMissing)
+	}
+	k, err := s.kiosk.GetByKeyHash(ctx, hashKey(deviceKey))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			slog.WarnContext(ctx, "device key kiosk tidak dikenal")
+			return Kiosk{}, apperror.Unauthorized(i18n.KioskUnknown)
+		}
+		return Kiosk{}, err
+	}
+	if !k.IsActive {
+		return Kiosk{}, attSvc.ErrKioskInactive
+	}
+	if err := s.kiosk.Touch(tenant.WithID(ctx, k.TenantID), k.ID); err != nil {
+		slog.WarnContext(ctx, "update last_seen kiosk gagal", "kiosk_id", k.ID, "err", err)
+	}
+	return k, nil
+}
+
+// IssueQR membuat QR bertanda tangan yang berlaku singkat; kiosk menampilkannya
+// dan me-refresh sebelum kedaluwarsa.
+func (s *KioskService) IssueQR(ctx context.Context, k Kiosk) (QRRes, error) {
+	token, exp, err := s.jwt.GenerateKioskQRToken(k.ID, s.cfg.QRTTL)
+	if err != nil {
+		return QRRes{}, err
+	}
+	return QRRes{Token: token, ExpiresAt: exp}, nil
+}
+
+// Punch mencatat check-in/out atas nama karyawan yang menempelkan badge + PIN di kiosk.
+// PIN hanya 4-8 digit, jadi PIN salah dihitung per badge dan badge dikunci
+// seperti akun di Login; badge yang tidak dikenal dan PIN salah sama-sama
+// mendapat ErrBadCredentials.
+func (s *KioskService) Punch(ctx context.Context, k Kiosk, req PunchReq, ip string) (attSvc.Attendance, bool, error) {
+	badge, err := s.kiosk.GetBadge(ctx, strings.TrimSpace(req.BadgeID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			slog.InfoContext(ctx, "punch kiosk gagal", "kiosk_id", k.ID)
+			return attSvc.Attendance{}, false, ErrBadCredentials
+		}
+		return attSvc.Attendance{}, false, err
+	}
+	// PIN benar atau salah, badge yang masih terkunci tetap ditolak
+	if lockErr := s.lockedErr(badge.PinLockedUntil); lockErr != nil {
+		return attSvc.Attendance{}, false, lockErr
+	}
+	if badge.PinHash == "" || bcrypt.CompareHashAndPassword([]byte(badge.PinHash), []byte(req.PIN)) != nil {
+		return attSvc.Attendance{}, false, s.recordFailure(ctx, k, badge)
+	}
+	if err := s.kiosk.ResetFailedPIN(ctx, badge.UserID); err != nil {
+		return attSvc.Attendance{}, false, err
+	}
+	if !badge.IsActive {
+		return attSvc.Attendance{}, false, ErrAccountInactive
+	}
+
+	kioskID := k.ID
+	if req.Type == "OUT" {
+		out, err := s.attendance.CheckOut(ctx, attSvc.CheckOutReq{
+			UserId:  badge.UserID,
+			IP:      ip,
+			KioskID: &kioskID,
+		})
+		return out, false, err
+	}
+
+	activity := req.Activity
+	if activity == "" {
+		activity = defaultKioskActivity
+	}
+	return s.attendance.CheckIn(ctx, attSvc.CheckInReq{
+		UserId:   badge.UserID,
+		Activity: activity,
+		IP:       ip,
+		KioskID:  &kioskID,
+	})
+}
+
+// recordFailure mencatat PIN salah dan mengunci badge kalau batas tercapai.
+func (s *KioskService) recordFailure(ctx context.Context, k Kiosk, badge entity.Badge) error {
+	lockedUntil, err := s.kiosk.RecordFailedPIN(ctx, badge.UserID, s.cfg.PINMaxFailed, s.cfg.PINLockout)
+	if err != nil {
+		return err
+	}
+	if lockErr := s.lockedErr(lockedUntil); lockErr != nil {
+		slog.WarnContext(ctx, "badge dikunci", "user_id", badge.UserID, "kiosk_id", k.ID, "locked_until", lockedUntil)
+		return lockErr
+	}
+	slog.InfoContext(ctx, "punch kiosk gagal", "kiosk_id", k.ID)
+	return ErrBadCredentials
+}
+
+// lockedErr mengembalikan error 423 kalau lockedUntil masih di masa depan.
+func (s *KioskService) lockedErr(lockedUntil *time.Time) error {
+	if lockedUntil == nil {
+		return nil
+	}
+	left := lockedUntil.Sub(s.clock.Now())
+	if left <= 0 {
+		return nil
+	}
+	return ErrBadgeLocked.With(int(math.Ceil(left.Minutes())))
+}
+
+func newDeviceKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,86 @@
+package kiosk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/clock"
+	"mojo-autotech/config"
+	"mojo-autotech/fake"
+	entity "mojo-autotech/model/kiosk"
+	attSvc "mojo-autotech/service/attedance"
+	"mojo-autotech/tenant"
+)
+
+// newTestService: badge "B-001" milik user 7 dengan PIN 1234, dikunci 15
+// menit setelah 3 PIN salah, dan satu kiosk terdaftar di tenant 1.
+func newTestService(t *testing.T) (*KioskService, *fake.KioskRepository, *clock.Fake, Kiosk) {
+	t.Helper()
+	clk := clock.NewFake(time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC))
+	repo := fake.NewKioskRepository(clk)
+	repo.AddBadge(entity.Badge{UserID: 7, Username: "budi", IsActive: true}, "B-001", "1234")
+
+	wib := time.FixedZone("WIB", 7*60*60)
+	shift := config.Shift{Start: 8 * time.Hour, LateGrace: 15 * time.Minute}
+	attendance := attSvc.NewAttendanceService(fake.NewAttendanceRepository(clk), repo, fake.NewWorkLocationRepository(), nil, nil, wib, shift, clk)
+	cfg := config.Kiosk{PINMaxFailed: 3, PINLockout: 15 * time.Minute}
+	svc := NewKioskService(repo, attendance, nil, cfg, clk)
+
+	res, err := svc.Register(tenant.WithID(context.Background(), 1), RegisterKioskReq{Code: "K1", Name: "Bay 1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc, repo, clk, res.Kiosk
+}
+
+func TestPunchUnknownBadgeLooksLikeWrongPIN(t *testing.T) {
+	svc, _, _, k := newTestService(t)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	_, _, errUnknown := svc.Punch(ctx, k, PunchReq{BadgeID: "B-999", PIN: "1234", Type: "IN"}, "10.0.0.1")
+	_, _, errWrong := svc.Punch(ctx, k, PunchReq{BadgeID: "B-001", PIN: "9999", Type: "IN"}, "10.0.0.1")
+	if !errors.Is(errUnknown, ErrBadCredentials) || !errors.Is(errWrong, ErrBadCredentials) {
+		t.Fatalf("err = %v / %v, keduanya mau ErrBadCredentials", errUnknown, errWrong)
+	}
+}
+
+func TestPunchLockoutAndExpiry(t *testing.T) {
+	svc, _, clk, k := newTestService(t)
+	ctx := tenant.WithID(context.Background(), 1)
+	wrong := PunchReq{BadgeID: "B-001", PIN: "0000", Type: "IN"}
+	right := PunchReq{BadgeID: "B-001", PIN: "1234", Type: "IN"}
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := svc.Punch(ctx, k, wrong, "10.0.0.1"); !errors.Is(err, ErrBadCredentials) {
+			t.Fatalf("percobaan ke-%d: err = %v", i+1, err)
+		}
+	}
+	if _, _, err := svc.Punch(ctx, k, wrong, "10.0.0.1"); apperror.CodeOf(err) != apperror.CodeLocked {
+		t.Fatalf("percobaan ke-3: err = %v, mau badge dikunci", err)
+	}
+
+	// PIN benar pun ditolak selama masa kunci
+	clk.Advance(14 * time.Minute)
+	if _, _, err := svc.Punch(ctx, k, right, "10.0.0.1"); apperror.CodeOf(err) != apperror.CodeLocked {
+		t.Fatalf("punch saat terkunci: err = %v, mau LOCKED", err)
+	}
+
+	clk.Advance(time.Minute)
+	att, created, err := svc.Punch(ctx, k, right, "10.0.0.1")
+	if err != nil {
+		t.Fatalf("punch setelah masa kunci: %v", err)
+	}
+	if !created || att.CheckInKioskID == nil || *att.CheckInKioskID != k.ID {
+		t.Fatalf("check-in kiosk = %+v (created %v)", att, created)
+	}
+
+	// punch sukses mereset hitungan: dua PIN salah berikutnya belum mengunci
+	for i := 0; i < 2; i++ {
+		if _, _, err := svc.Punch(ctx, k, wrong, "10.0.0.1"); !errors.Is(err, ErrBadCredentials) {
+			t.Fatalf("setelah reset, percobaan ke-%d: err = %v", i+1, err)
+		}
+	}
+}
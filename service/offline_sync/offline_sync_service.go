@@ -0,0 +1,279 @@
+package offline_sync
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/clock"
+	"mojo-autotech/config"
+	"mojo-autotech/i18n"
+	entity "mojo-autotech/model/offline_sync"
+	org "mojo-autotech/model/org_structure"
+	attSvc "mojo-autotech/service/attedance"
+
+	"gorm.io/gorm"
+)
+
+type (
+	Attendance        = attSvc.Attendance
+	Punch             = entity.Punch
+	PunchReq          = entity.PunchReq
+	PunchResult       = entity.PunchResult
+	SyncReq           = entity.SyncReq
+	SyncRes           = entity.SyncRes
+	RegisterDeviceReq = entity.RegisterDeviceReq
+	RegisterDeviceRes = entity.RegisterDeviceRes
+	ReviewReq         = entity.ReviewReq
+)
+
+type IOfflineSyncService interface {
+	RegisterDevice(ctx context.Context, userID uint, req RegisterDeviceReq) (RegisterDeviceRes, error)
+	Sync(ctx context.Context, userID uint, ip string, req SyncReq) (SyncRes, error)
+	// ListFlagged dan Review hanya untuk punch milik user di scope reviewer.
+	ListFlagged(ctx context.Context, scope org.Scope) ([]Punch, error)
+	Review(ctx context.Context, id uint, reviewerID uint, scope org.Scope, req ReviewReq) (Punch, error)
+}
+
+type OfflineSyncService struct {
+	sync       entity.IOfflineSyncRepository
+	attendance attSvc.IAttendanceService
+	cfg        config.Sync
+	clock      clock.Clock
+}
+
+func NewOfflineSyncService(repo entity.IOfflineSyncRepository, attendance attSvc.IAttendanceService, cfg config.Sync, clk clock.Clock) *OfflineSyncService {
+	return &OfflineSyncService{
+		sync:       repo,
+		attendance: attendance,
+		cfg:        cfg,
+		clock:      clk,
+	}
+}
+
+func (s *OfflineSyncService) RegisterDevice(ctx context.Context, userID uint, req RegisterDeviceReq) (RegisterDeviceRes, error) {
+	if userID == 0 {
+		return RegisterDeviceRes{}, attSvc.ErrUnauthorized
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return RegisterDeviceRes{}, err
+	}
+	secret := hex.EncodeToString(b)
+
+	d, err := s.sync.UpsertDevice(ctx, userID, req.DeviceID, secret)
+	if err != nil {
+		return RegisterDeviceRes{}, err
+	}
+	return RegisterDeviceRes{DeviceID: d.DeviceID, Secret: secret}, nil
+}
+
+// Sync menerima batch punch offline. Tiap punch diproses terpisah; satu punch
+// gagal tidak membatalkan punch lain di batch yang sama.
+func (s *OfflineSyncService) Sync(ctx context.Context, userID uint, ip string, req SyncReq) (SyncRes, error) {
+	if userID == 0 {
+		return SyncRes{}, attSvc.ErrUnauthorized
+	}
+	dev, err := s.sync.GetDevice(ctx, userID, req.DeviceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return SyncRes{}, apperror.Forbidden(i18n.DeviceNotRegistered)
+		}
+		return SyncRes{}, err
+	}
+
+	receivedAt := s.clock.Now()
+	res := SyncRes{Results: make([]PunchResult, 0, len(req.Punches))}
+	for _, p := range req.Punches {
+		r, err := s.syncOne(ctx, userID, ip, dev.Secret, req, p, receivedAt)
+		if err != nil {
+			return SyncRes{}, err
+		}
+		res.Results = append(res.Results, r)
+	}
+	return res, nil
+}
+
+func (s *OfflineSyncService) syncOne(ctx context.Context, userID uint, ip, secret string, req SyncReq, p PunchReq, receivedAt time.Time) (PunchResult, error) {
+	// Signature salah tidak disimpan: punch_id-nya belum terbukti dari perangkat,
+	// dan kiriman ulang yang benar tidak boleh mendapat REJECTED ini terus.
+	if !hmac.Equal([]byte(sign(secret, p)), []byte(p.Signature)) {
+		slog.WarnContext(ctx, "signature punch offline tidak valid", "punch_id", p.PunchID, "device_id", req.DeviceID)
+		return PunchResult{PunchID: p.PunchID, Status: entity.PunchRejected, Reason: "signature tidak valid"}, nil
+	}
+
+	// Kiriman ulang: kembalikan hasil sebelumnya apa adanya
+	if prev, err := s.sync.GetPunch(ctx, userID, p.PunchID); err == nil {
+		return resultOf(prev, true), nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return PunchResult{}, err
+	}
+
+	row := Punch{
+		UserID:      userID,
+		PunchID:     p.PunchID,
+		DeviceID:    req.DeviceID,
+		Type:        p.Type,
+		DeviceTime:  p.DeviceTime,
+		MonotonicMs: p.MonotonicMs,
+		Lat:         p.Lat,
+		Lng:         p.Lng,
+		Activity:    p.Activity,
+		ReceivedAt:  receivedAt,
+	}
+	row.Status, row.Reason = s.assess(&row, req.SentMonotonicMs, p, receivedAt)
+
+	saved, inserted, err := s.sync.InsertPunch(ctx, row)
+	if err != nil {
+		return PunchResult{}, err
+	}
+	if !inserted {
+		// balapan dengan request lain yang membawa punch_id sama
+		prev, err := s.sync.GetPunch(ctx, userID, p.PunchID)
+		if err != nil {
+			return PunchResult{}, err
+		}
+		return resultOf(prev, true), nil
+	}
+	if saved.Status != entity.PunchApplied {
+		slog.InfoContext(ctx, "punch offline tidak langsung diterapkan",
+			"punch_id", saved.PunchID, "status", saved.Status, "reason", saved.Reason)
+		return resultOf(saved, false), nil
+	}
+
+	if err := s.apply(ctx, &saved, ip, nil); err != nil {
+		return PunchResult{}, err
+	}
+	return resultOf(saved, false), nil
+}
+
+// assess mengisi estimasi waktu & skew lalu menentukan apakah punch (yang
+// signature-nya sudah valid) bisa langsung diterapkan.
+func (s *OfflineSyncService) assess(row *Punch, sentMonotonicMs int64, p PunchReq, receivedAt time.Time) (string, string) {
+	// Monotonic tidak terpengaruh jam dinding yang diubah user; selisihnya terhadap
+	// saat kirim memberi umur punch menurut perangkat.
+	elapsed := time.Duration(sentMonotonicMs-p.MonotonicMs) * time.Millisecond
+	if elapsed < 0 {
+		// perangkat restart di antara punch dan sync, monotonic tidak bisa dipakai
+		return entity.PunchFlagged, "monotonic clock tidak konsisten (perangkat restart?)"
+	}
+	est := receivedAt.Add(-elapsed)
+	row.EstimatedAt = &est
+	skew := p.DeviceTime.Sub(est)
+	row.SkewSeconds = int64(skew / time.Second)
+
+	if elapsed > s.cfg.MaxAge {
+		return entity.PunchFlagged, fmt.Sprintf("punch lebih tua dari %s", s.cfg.MaxAge)
+	}
+	if skew > s.cfg.SkewTolerance || skew < -s.cfg.SkewTolerance {
+		return entity.PunchFlagged, fmt.Sprintf("selisih jam perangkat %s di luar toleransi %s", skew.Round(time.Second), s.cfg.SkewTolerance)
+	}
+	return entity.PunchApplied, ""
+}
+
+// apply menerapkan punch ke attendances memakai waktu perangkat.
+func (s *OfflineSyncService) apply(ctx context.Context, p *Punch, ip string, reviewer *uint) error {
+	at := p.DeviceTime
+
+	var (
+		out Attendance
+		err error
+	)
+	if p.Type == "OUT" {
+		out, err = s.attendance.CheckOut(ctx, attSvc.CheckOutReq{UserId: p.UserID, IP: ip, At: &at})
+	} else {
+		activity := p.Activity
+		if activity == "" {
+			activity = "Check-in offline"
+		}
+		out, _, err = s.attendance.CheckIn(ctx, attSvc.CheckInReq{
+			UserId: p.UserID, Activity: activity, Lat: p.Lat, Lng: p.Lng, IP: ip, At: &at,
+		})
+	}
+
+	if err != nil {
+		// error infrastruktur dikembalikan supaya client mengirim ulang batch-nya
+		if apperror.CodeOf(err) == apperror.CodeInternal {
+			return err
+		}
+		// error bisnis (sudah check-out, periode terkunci, ...) dicatat sebagai REJECTED
+		p.Status, p.Reason = entity.PunchRejected, err.Error()
+		return s.sync.SetResult(ctx, p.ID, p.Status, p.Reason, nil, reviewer)
+	}
+	p.Status, p.AttendanceID = entity.PunchApplied, &out.ID
+	return s.sync.SetResult(ctx, p.ID, p.Status, p.Reason, p.AttendanceID, reviewer)
+}
+
+func (s *OfflineSyncService) ListFlagged(ctx context.Context, scope org.Scope) ([]Punch, error) {
+	return s.sync.ListByStatus(ctx, entity.PunchFlagged, scope)
+}
+
+// Review: admin atau atasan menyetujui (punch diterapkan dengan waktu
+// perangkat) atau menolak punch FLAGGED.
+func (s *OfflineSyncService) Review(ctx context.Context, id uint, reviewerID uint, scope org.Scope, req ReviewReq) (Punch, error) {
+	p, err := s.sync.GetPunchByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Punch{}, apperror.NotFound(i18n.PunchNotFound)
+		}
+		return Punch{}, err
+	}
+	if !scope.Allows(p.UserID) {
+		return Punch{}, apperror.Forbidden(i18n.OutsideReports)
+	}
+	if p.Status != entity.PunchFlagged {
+		return Punch{}, apperror.Conflict(i18n.PunchNotPending)
+	}
+
+	if !req.Approve {
+		p.Status, p.Reason = entity.PunchRejected, req.Note
+		if err := s.sync.SetResult(ctx, p.ID, p.Status, p.Reason, nil, &reviewerID); err != nil {
+			return Punch{}, err
+		}
+		return p, nil
+	}
+
+	p.Reason = req.Note
+	if err := s.apply(ctx, &p, "", &reviewerID); err != nil {
+		return Punch{}, err
+	}
+	return p, nil
+}
+
+func resultOf(p Punch, duplicate bool) PunchResult {
+	return PunchResult{
+		PunchID:      p.PunchID,
+		Status:       p.Status,
+		Reason:       p.Reason,
+		AttendanceID: p.AttendanceID,
+		Duplicate:    duplicate,
+	}
+}
+
+func sign(secret string, p PunchReq) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(canonical(p)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func canonical(p PunchReq) string {
+	return p.PunchID + "|" + p.Type + "|" +
+		strconv.FormatInt(p.DeviceTime.UnixMilli(), 10) + "|" +
+		strconv.FormatInt(p.MonotonicMs, 10) + "|" +
+		coord(p.Lat) + "|" + coord(p.Lng)
+}
+
+func coord(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', 6, 64)
+}
@@ -0,0 +1,57 @@
+package offline_sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mojo-autotech/clock"
+	"mojo-autotech/config"
+	"mojo-autotech/fake"
+	entity "mojo-autotech/model/offline_sync"
+	attSvc "mojo-autotech/service/attedance"
+	"mojo-autotech/tenant"
+)
+
+// TestSyncBadSignatureNotStored: punch dengan signature salah tidak menempati
+// punch_id, jadi kiriman ulang yang ditandatangani benar tetap diterapkan.
+func TestSyncBadSignatureNotStored(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 3, 2, 1, 5, 0, 0, time.UTC))
+	repo := fake.NewOfflineSyncRepository(clk)
+	shift := config.Shift{Start: 8 * time.Hour, LateGrace: 15 * time.Minute}
+	attendance := attSvc.NewAttendanceService(fake.NewAttendanceRepository(clk), nil, fake.NewWorkLocationRepository(),
+		nil, nil, time.FixedZone("WIB", 7*60*60), shift, clk)
+	svc := NewOfflineSyncService(repo, attendance, config.Sync{MaxAge: 72 * time.Hour, SkewTolerance: 5 * time.Minute}, clk)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	dev, err := svc.RegisterDevice(ctx, 7, RegisterDeviceReq{DeviceID: "hp-budi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := PunchReq{PunchID: "p-1", Type: "IN", DeviceTime: clk.Now().Add(-5 * time.Minute), MonotonicMs: 1_000}
+	req := SyncReq{DeviceID: dev.DeviceID, SentMonotonicMs: 301_000, Punches: []PunchReq{p}}
+
+	p.Signature = sign("secret-palsu", p)
+	req.Punches[0] = p
+	res, err := svc.Sync(ctx, 7, "", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := res.Results[0]; r.Status != entity.PunchRejected || r.Duplicate {
+		t.Fatalf("signature salah: %+v, mau REJECTED", r)
+	}
+	if n := repo.Len(); n != 0 {
+		t.Fatalf("punch dengan signature salah tersimpan (%d baris)", n)
+	}
+
+	p.Signature = sign(dev.Secret, p)
+	req.Punches[0] = p
+	res, err = svc.Sync(ctx, 7, "", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := res.Results[0]; r.Status != entity.PunchApplied || r.Duplicate || r.AttendanceID == nil {
+		t.Fatalf("kiriman ulang bertanda tangan benar: %+v, mau APPLIED", r)
+	}
+}
@@ -0,0 +1,292 @@
+package org_structure
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/clock"
+	"mojo-autotech/i18n"
+	entity "mojo-autotech/model/org_structure"
+
+	"gorm.io/gorm"
+)
+
+type (
+	Department    = entity.Department
+	Team          = entity.Team
+	ReportingLine = entity.ReportingLine
+	Member        = entity.Member
+	Scope         = entity.Scope
+	DepartmentReq = entity.DepartmentReq
+	TeamReq       = entity.TeamReq
+	AssignReq     = entity.AssignReq
+	RoleReq       = entity.RoleReq
+)
+
+type IOrgService interface {
+	CreateDepartment(ctx context.Context, req DepartmentReq) (Department, error)
+	UpdateDepartment(ctx context.Context, id uint, req DepartmentReq) (Department, error)
+	ListDepartments(ctx context.Context) ([]Department, error)
+
+	CreateTeam(ctx context.Context, req TeamReq) (Team, error)
+	UpdateTeam(ctx context.Context, id uint, req TeamReq) (Team, error)
+	ListTeams(ctx context.Context, departmentID uint) ([]Team, error)
+	ListMembers(ctx context.Context, teamID uint) ([]Member, error)
+
+	// Assign memindahkan user ke tim dan/atau atasan baru; penempatan lama
+	// ditutup dan tetap ada di History.
+	Assign(ctx context.Context, userID, changedBy uint, req AssignReq) (ReportingLine, error)
+	History(ctx context.Context, userID uint) ([]ReportingLine, error)
+	// SetRole berlaku untuk token berikutnya; token yang sudah terbit tetap
+	// membawa role lama sampai kedaluwarsa.
+	SetRole(ctx context.Context, userID uint, req RoleReq) (Member, error)
+
+	Reports(ctx context.Context, managerID uint) ([]Member, error)
+	// Scope menentukan data siapa yang boleh dilihat dan di-approve user
+	// dengan role tersebut. Role selain ADMIN/MANAGER tidak melihat apa pun.
+	Scope(ctx context.Context, userID uint, role string) (Scope, error)
+}
+
+type OrgService struct {
+	org   entity.IOrgRepository
+	clock clock.Clock
+}
+
+func NewOrgService(repo entity.IOrgRepository, clk clock.Clock) *OrgService {
+	return &OrgService{
+		org:   repo,
+		clock: clk,
+	}
+}
+
+func (s *OrgService) CreateDepartment(ctx context.Context, req DepartmentReq) (Department, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := s.checkDepartmentName(ctx, 0, name); err != nil {
+		return Department{}, err
+	}
+	return s.org.CreateDepartment(ctx, Department{Name: name})
+}
+
+func (s *OrgService) UpdateDepartment(ctx context.Context, id uint, req DepartmentReq) (Department, error) {
+	d, err := s.org.GetDepartment(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Department{}, apperror.NotFound(i18n.DepartmentNotFound)
+		}
+		return Department{}, err
+	}
+	d.Name = strings.TrimSpace(req.Name)
+	if err := s.checkDepartmentName(ctx, id, d.Name); err != nil {
+		return Department{}, err
+	}
+	if _, err := s.org.UpdateDepartment(ctx, d); err != nil {
+		return Department{}, err
+	}
+	return s.org.GetDepartment(ctx, id)
+}
+
+func (s *OrgService) ListDepartments(ctx context.Context) ([]Department, error) {
+	return s.org.ListDepartments(ctx)
+}
+
+func (s *OrgService) CreateTeam(ctx context.Context, req TeamReq) (Team, error) {
+	t := Team{DepartmentID: req.DepartmentID, Name: strings.TrimSpace(req.Name), ManagerID: req.ManagerID}
+	if err := s.checkTeam(ctx, 0, t); err != nil {
+		return Team{}, err
+	}
+	return s.org.CreateTeam(ctx, t)
+}
+
+// UpdateTeam: mengganti manager tim tidak memindahkan atasan anggota yang
+// sudah ada; manager tim hanya default untuk penempatan berikutnya.
+func (s *OrgService) UpdateTeam(ctx context.Context, id uint, req TeamReq) (Team, error) {
+	t, err := s.org.GetTeam(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Team{}, apperror.NotFound(i18n.TeamNotFound)
+		}
+		return Team{}, err
+	}
+	t.DepartmentID, t.Name, t.ManagerID = req.DepartmentID, strings.TrimSpace(req.Name), req.ManagerID
+	if err := s.checkTeam(ctx, id, t); err != nil {
+		return Team{}, err
+	}
+	if _, err := s.org.UpdateTeam(ctx, t); err != nil {
+		return Team{}, err
+	}
+	return s.org.GetTeam(ctx, id)
+}
+
+func (s *OrgService) ListTeams(ctx context.Context, departmentID uint) ([]Team, error) {
+	return s.org.ListTeams(ctx, departmentID)
+}
+
+func (s *OrgService) ListMembers(ctx context.Context, teamID uint) ([]Member, error) {
+	if _, err := s.org.GetTeam(ctx, teamID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound(i18n.TeamNotFound)
+		}
+		return nil, err
+	}
+	return s.org.ListMembers(ctx, teamID)
+}
+
+func (s *OrgService) Assign(ctx context.Context, userID, changedBy uint, req AssignReq) (ReportingLine, error) {
+	if _, err := s.org.GetMember(ctx, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ReportingLine{}, apperror.NotFound(i18n.UserNotFound)
+		}
+		return ReportingLine{}, err
+	}
+
+	manager := req.ManagerID
+	if req.TeamID != nil {
+		t, err := s.org.GetTeam(ctx, *req.TeamID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ReportingLine{}, apperror.Validation(i18n.TeamNotFound)
+			}
+			return ReportingLine{}, err
+		}
+		// manager tim tidak menjadi atasan dirinya sendiri
+		if manager == nil && t.ManagerID != nil && *t.ManagerID != userID {
+			manager = t.ManagerID
+		}
+	}
+	if manager != nil {
+		if err := s.checkManager(ctx, userID, *manager); err != nil {
+			return ReportingLine{}, err
+		}
+	}
+
+	line := ReportingLine{
+		UserID:    userID,
+		TeamID:    req.TeamID,
+		ManagerID: manager,
+		ValidFrom: s.clock.Now(),
+		Note:      strings.TrimSpace(req.Note),
+	}
+	if changedBy != 0 {
+		line.ChangedBy = &changedBy
+	}
+	out, err := s.org.Assign(ctx, line)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ReportingLine{}, apperror.NotFound(i18n.UserNotFound)
+		}
+		return ReportingLine{}, err
+	}
+	return out, nil
+}
+
+func (s *OrgService) History(ctx context.Context, userID uint) ([]ReportingLine, error) {
+	if _, err := s.org.GetMember(ctx, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound(i18n.UserNotFound)
+		}
+		return nil, err
+	}
+	return s.org.History(ctx, userID)
+}
+
+func (s *OrgService) SetRole(ctx context.Context, userID uint, req RoleReq) (Member, error) {
+	if err := s.org.SetRole(ctx, userID, req.Role); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Member{}, apperror.NotFound(i18n.UserNotFound)
+		}
+		return Member{}, err
+	}
+	return s.org.GetMember(ctx, userID)
+}
+
+func (s *OrgService) Reports(ctx context.Context, managerID uint) ([]Member, error) {
+	return s.org.Reports(ctx, managerID)
+}
+
+func (s *OrgService) Scope(ctx context.Context, userID uint, role string) (Scope, error) {
+	switch role {
+	case "ADMIN":
+		return Scope{All: true}, nil
+	case "MANAGER":
+		reports, err := s.org.Reports(ctx, userID)
+		if err != nil {
+			return Scope{}, err
+		}
+		ids := make([]uint, 0, len(reports))
+		for _, m := range reports {
+			ids = append(ids, m.ID)
+		}
+		return Scope{UserIDs: ids}, nil
+	}
+	return Scope{}, nil
+}
+
+// checkManager: atasan harus user aktif di tenant yang sama dan bukan user
+// itu sendiri atau bawahannya, supaya garis pelaporan tidak membentuk siklus.
+func (s *OrgService) checkManager(ctx context.Context, userID, managerID uint) error {
+	if managerID == userID {
+		return apperror.Validation(i18n.ManagerCycle)
+	}
+	m, err := s.org.GetMember(ctx, managerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.Validation(i18n.ManagerNotFound)
+		}
+		return err
+	}
+	if !m.IsActive {
+		return apperror.Validation(i18n.ManagerNotFound)
+	}
+	reports, err := s.org.Reports(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(reports, func(r Member) bool { return r.ID == managerID }) {
+		return apperror.Validation(i18n.ManagerCycle)
+	}
+	return nil
+}
+
+func (s *OrgService) checkDepartmentName(ctx context.Context, id uint, name string) error {
+	all, err := s.org.ListDepartments(ctx)
+	if err != nil {
+		return err
+	}
+	for _, d := range all {
+		if d.ID != id && strings.EqualFold(d.Name, name) {
+			return apperror.Conflict(i18n.DepartmentExists)
+		}
+	}
+	return nil
+}
+
+func (s *OrgService) checkTeam(ctx context.Context, id uint, t Team) error {
+	if _, err := s.org.GetDepartment(ctx, t.DepartmentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.Validation(i18n.DepartmentNotFound)
+		}
+		return err
+	}
+	if t.ManagerID != nil {
+		m, err := s.org.GetMember(ctx, *t.ManagerID)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !m.IsActive) {
+			return apperror.Validation(i18n.ManagerNotFound)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	all, err := s.org.ListTeams(ctx, t.DepartmentID)
+	if err != nil {
+		return err
+	}
+	for _, cur := range all {
+		if cur.ID != id && strings.EqualFold(cur.Name, t.Name) {
+			return apperror.Conflict(i18n.TeamExists)
+		}
+	}
+	return nil
+}
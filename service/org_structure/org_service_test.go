@@ -0,0 +1,159 @@
+package org_structure
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/clock"
+	"mojo-autotech/fake"
+	"mojo-autotech/tenant"
+)
+
+// newTestService: tenant 1 berisi kepala bengkel (1), dua manager (2, 3) dan
+// tiga teknisi (4, 5, 6) tanpa tim dan atasan; tenant 2 berisi user 9.
+func newTestService() (*OrgService, *fake.OrgRepository, *clock.Fake, context.Context) {
+	clk := clock.NewFake(time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC))
+	repo := fake.NewOrgRepository()
+	for id, role := range map[uint]string{1: "ADMIN", 2: "MANAGER", 3: "MANAGER", 4: "EMPLOYEE", 5: "EMPLOYEE", 6: "EMPLOYEE"} {
+		repo.AddMember(1, Member{ID: id, Role: role})
+	}
+	repo.AddMember(2, Member{ID: 9, Role: "EMPLOYEE"})
+	return NewOrgService(repo, clk), repo, clk, tenant.WithID(context.Background(), 1)
+}
+
+func ptr(v uint) *uint { return &v }
+
+func mustAssign(t *testing.T, svc *OrgService, ctx context.Context, userID uint, req AssignReq) ReportingLine {
+	t.Helper()
+	out, err := svc.Assign(ctx, userID, 1, req)
+	if err != nil {
+		t.Fatalf("assign user %d: %v", userID, err)
+	}
+	return out
+}
+
+func TestAssignUsesTeamManagerAndKeepsHistory(t *testing.T) {
+	svc, _, clk, ctx := newTestService()
+	dept, err := svc.CreateDepartment(ctx, DepartmentReq{Name: "Bengkel"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mesin, err := svc.CreateTeam(ctx, TeamReq{DepartmentID: dept.ID, Name: "Mesin", ManagerID: ptr(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bodi, err := svc.CreateTeam(ctx, TeamReq{DepartmentID: dept.ID, Name: "Body & Cat", ManagerID: ptr(3)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := mustAssign(t, svc, ctx, 4, AssignReq{TeamID: &mesin.ID})
+	if first.ManagerID == nil || *first.ManagerID != 2 {
+		t.Fatalf("atasan = %v, mau manager tim (2)", first.ManagerID)
+	}
+
+	clk.Advance(24 * time.Hour)
+	mustAssign(t, svc, ctx, 4, AssignReq{TeamID: &bodi.ID, Note: "pindah tim"})
+
+	history, err := svc.History(ctx, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("riwayat = %d baris, mau 2", len(history))
+	}
+	cur, old := history[0], history[1]
+	if cur.ValidTo != nil || *cur.ManagerID != 3 || *cur.TeamID != bodi.ID {
+		t.Fatalf("penempatan berlaku = %+v", cur)
+	}
+	if old.ValidTo == nil || !old.ValidTo.Equal(cur.ValidFrom) {
+		t.Fatalf("penempatan lama harus ditutup saat yang baru mulai: %+v", old)
+	}
+
+	members, err := svc.ListMembers(ctx, mesin.ID)
+	if err != nil || len(members) != 0 {
+		t.Fatalf("anggota tim lama = %v, %v; mau kosong", members, err)
+	}
+}
+
+func TestAssignRejectsCycle(t *testing.T) {
+	svc, _, _, ctx := newTestService()
+	mustAssign(t, svc, ctx, 3, AssignReq{ManagerID: ptr(2)})
+	mustAssign(t, svc, ctx, 5, AssignReq{ManagerID: ptr(3)})
+
+	for name, c := range map[string]struct{ user, manager uint }{
+		"diri sendiri":           {2, 2},
+		"bawahan langsung":       {2, 3},
+		"bawahan tidak langsung": {2, 5},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.Assign(ctx, c.user, 1, AssignReq{ManagerID: &c.manager})
+			if apperror.CodeOf(err) != apperror.CodeValidation {
+				t.Fatalf("err = %v, mau VALIDATION", err)
+			}
+		})
+	}
+}
+
+func TestAssignIsScopedPerTenant(t *testing.T) {
+	svc, _, _, ctx := newTestService()
+
+	// atasan dari tenant lain dianggap tidak ada
+	if _, err := svc.Assign(ctx, 4, 1, AssignReq{ManagerID: ptr(9)}); apperror.CodeOf(err) != apperror.CodeValidation {
+		t.Fatalf("atasan tenant lain: err = %v, mau VALIDATION", err)
+	}
+	if _, err := svc.Assign(ctx, 9, 1, AssignReq{ManagerID: ptr(2)}); apperror.CodeOf(err) != apperror.CodeNotFound {
+		t.Fatalf("user tenant lain: err = %v, mau NOT_FOUND", err)
+	}
+}
+
+func TestScope(t *testing.T) {
+	svc, _, _, ctx := newTestService()
+	mustAssign(t, svc, ctx, 3, AssignReq{ManagerID: ptr(2)})
+	mustAssign(t, svc, ctx, 4, AssignReq{ManagerID: ptr(2)})
+	mustAssign(t, svc, ctx, 5, AssignReq{ManagerID: ptr(3)})
+
+	admin, err := svc.Scope(ctx, 1, "ADMIN")
+	if err != nil || !admin.All {
+		t.Fatalf("scope ADMIN = %+v, %v; mau semua", admin, err)
+	}
+
+	manager, err := svc.Scope(ctx, 2, "MANAGER")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(manager.UserIDs)
+	if manager.All || !slices.Equal(manager.UserIDs, []uint{3, 4, 5}) {
+		t.Fatalf("scope manager 2 = %+v, mau bawahan 3, 4, 5", manager)
+	}
+	if manager.Allows(2) || manager.Allows(6) {
+		t.Fatal("manager tidak boleh mereview dirinya sendiri atau karyawan di luar timnya")
+	}
+
+	employee, err := svc.Scope(ctx, 4, "EMPLOYEE")
+	if err != nil || employee.All || len(employee.UserIDs) != 0 {
+		t.Fatalf("scope EMPLOYEE = %+v, %v; mau kosong", employee, err)
+	}
+}
+
+func TestTeamNameUniquePerDepartment(t *testing.T) {
+	svc, _, _, ctx := newTestService()
+	bengkel, _ := svc.CreateDepartment(ctx, DepartmentReq{Name: "Bengkel"})
+	fo, _ := svc.CreateDepartment(ctx, DepartmentReq{Name: "Front Office"})
+
+	if _, err := svc.CreateTeam(ctx, TeamReq{DepartmentID: bengkel.ID, Name: "Shift Pagi"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateTeam(ctx, TeamReq{DepartmentID: bengkel.ID, Name: "shift pagi"}); apperror.CodeOf(err) != apperror.CodeConflict {
+		t.Fatalf("nama kembar: err = %v, mau CONFLICT", err)
+	}
+	if _, err := svc.CreateTeam(ctx, TeamReq{DepartmentID: fo.ID, Name: "Shift Pagi"}); err != nil {
+		t.Fatalf("nama sama di departemen lain harus boleh: %v", err)
+	}
+	if _, err := svc.CreateDepartment(ctx, DepartmentReq{Name: "bengkel"}); apperror.CodeOf(err) != apperror.CodeConflict {
+		t.Fatalf("departemen kembar: err = %v, mau CONFLICT", err)
+	}
+}
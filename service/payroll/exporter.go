@@ -0,0 +1,115 @@
+package payroll
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// SchemaVersion ditulis ke setiap file export supaya sistem payroll bisa
+// mendeteksi perubahan kolom.
+const SchemaVersion = "mojo.payroll.v1"
+
+// Exporter mengubah rekap absensi satu periode menjadi file untuk sistem payroll.
+// Implementasi baru cukup didaftarkan lewat RegisterExporter.
+type Exporter interface {
+	Format() string
+	ContentType() string
+	FileExt() string
+	Export(w io.Writer, p Period, rows []Summary) error
+}
+
+var (
+	exportersMu sync.RWMutex
+	exporters   = map[string]Exporter{}
+)
+
+func RegisterExporter(e Exporter) {
+	exportersMu.Lock()
+	defer exportersMu.Unlock()
+	exporters[e.Format()] = e
+}
+
+func exporterFor(format string) (Exporter, bool) {
+	exportersMu.RLock()
+	defer exportersMu.RUnlock()
+	e, ok := exporters[format]
+	return e, ok
+}
+
+func init() {
+	RegisterExporter(CSVExporter{})
+	RegisterExporter(JSONExporter{})
+}
+
+var csvHeader = []string{
+	"schema", "period_start", "period_end",
+	"employee_id", "username", "full_name",
+	"worked_days", "total_minutes", "regular_minutes",
+	"overtime_first_hour_minutes", "overtime_next_hours_minutes",
+	"unpaid_leave_days",
+}
+
+// CSVExporter: satu baris per karyawan, kolom sesuai csvHeader.
+type CSVExporter struct{}
+
+func (CSVExporter) Format() string      { return "csv" }
+func (CSVExporter) ContentType() string { return "text/csv; charset=utf-8" }
+func (CSVExporter) FileExt() string     { return "csv" }
+
+func (CSVExporter) Export(w io.Writer, p Period, rows []Summary) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	start, end := p.PeriodStart.Format("2006-01-02"), p.PeriodEnd.Format("2006-01-02")
+	for _, r := range rows {
+		rec := []string{
+			SchemaVersion, start, end,
+			strconv.FormatUint(uint64(r.EmployeeID), 10), r.Username, r.FullName,
+			strconv.Itoa(r.WorkedDays), strconv.Itoa(r.TotalMinutes), strconv.Itoa(r.RegularMinutes),
+			strconv.Itoa(r.OvertimeFirstHourMinutes), strconv.Itoa(r.OvertimeNextHoursMinutes),
+			strconv.Itoa(r.UnpaidLeaveDays),
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// JSONExporter: satu dokumen berisi periode dan daftar karyawan.
+type JSONExporter struct{}
+
+func (JSONExporter) Format() string      { return "json" }
+func (JSONExporter) ContentType() string { return "application/json" }
+func (JSONExporter) FileExt() string     { return "json" }
+
+func (JSONExporter) Export(w io.Writer, p Period, rows []Summary) error {
+	if rows == nil {
+		rows = []Summary{}
+	}
+	doc := struct {
+		Schema      string    `json:"schema"`
+		PeriodID    uint      `json:"period_id"`
+		PeriodStart string    `json:"period_start"`
+		PeriodEnd   string    `json:"period_end"`
+		Employees   []Summary `json:"employees"`
+	}{
+		Schema:      SchemaVersion,
+		PeriodID:    p.ID,
+		PeriodStart: p.PeriodStart.Format("2006-01-02"),
+		PeriodEnd:   p.PeriodEnd.Format("2006-01-02"),
+		Employees:   rows,
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encode json: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package payroll
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+	entity "mojo-autotech/model/payroll"
+
+	"gorm.io/gorm"
+)
+
+type (
+	Period          = entity.Period
+	Summary         = entity.Summary
+	CreatePeriodReq = entity.CreatePeriodReq
+)
+
+const (
+	// StandardWorkMinutes: jam kerja normal per hari (8 jam), sisanya lembur.
+	StandardWorkMinutes = 8 * 60
+	// FirstOvertimeMinutes: lembur jam pertama dibayar dengan tarif berbeda dari jam berikutnya.
+	FirstOvertimeMinutes = 60
+)
+
+// ErrPeriodLocked: periode sudah ditutup; pesannya sama dengan service absensi.
+var ErrPeriodLocked = apperror.Locked(i18n.PeriodLocked)
+
+// ExportFile adalah hasil export yang siap dikirim sebagai attachment.
+type ExportFile struct {
+	Filename    string
+	ContentType string
+	Checksum    string
+	Body        []byte
+}
+
+type IPayrollService interface {
+	CreatePeriod(ctx context.Context, req CreatePeriodReq) (Period, error)
+	ListPeriods(ctx context.Context) ([]Period, error)
+	Summaries(ctx context.Context, periodID uint) (Period, []Summary, error)
+	Export(ctx context.Context, periodID uint, format string) (ExportFile, error)
+	Close(ctx context.Context, periodID uint, format string, adminID uint) (Period, ExportFile, error)
+}
+
+type PayrollService struct {
+	payroll entity.IPayrollRepository
+}
+
+func NewPayrollService(repo entity.IPayrollRepository) *PayrollService {
+	return &PayrollService{
+		payroll: repo,
+	}
+}
+
+func (s *PayrollService) CreatePeriod(ctx context.Context, req CreatePeriodReq) (Period, error) {
+	start, err := time.Parse("2006-01-02", req.PeriodStart)
+	if err != nil {
+		return Period{}, apperror.Validation(i18n.PeriodStartInvalid)
+	}
+	end, err := time.Parse("2006-01-02", req.PeriodEnd)
+	if err != nil {
+		return Period{}, apperror.Validation(i18n.PeriodEndInvalid)
+	}
+	if end.Before(start) {
+		return Period{}, apperror.Validation(i18n.PeriodRangeInvalid)
+	}
+
+	n, err := s.payroll.CountOverlapping(ctx, start, end)
+	if err != nil {
+		return Period{}, err
+	}
+	if n > 0 {
+		return Period{}, apperror.Conflict(i18n.PeriodOverlap)
+	}
+
+	return s.payroll.CreatePeriod(ctx, Period{
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Status:      entity.StatusOpen,
+	})
+}
+
+func (s *PayrollService) ListPeriods(ctx context.Context) ([]Period, error) {
+	return s.payroll.ListPeriods(ctx)
+}
+
+func (s *PayrollService) Summaries(ctx context.Context, periodID uint) (Period, []Summary, error) {
+	p, err := s.getPeriod(ctx, periodID)
+	if err != nil {
+		return Period{}, nil, err
+	}
+	rows, err := s.payroll.Summaries(ctx, p.PeriodStart, p.PeriodEnd, StandardWorkMinutes, FirstOvertimeMinutes)
+	if err != nil {
+		return Period{}, nil, err
+	}
+	return p, rows, nil
+}
+
+// Export membuat file tanpa mengunci periode (preview).
+func (s *PayrollService) Export(ctx context.Context, periodID uint, format string) (ExportFile, error) {
+	p, rows, err := s.Summaries(ctx, periodID)
+	if err != nil {
+		return ExportFile{}, err
+	}
+	return render(p, rows, format)
+}
+
+// Close membuat file export lalu mengunci periode, sehingga absensi di dalamnya
+// tidak bisa diubah lagi tanpa jejak. Checksum file disimpan di periode.
+func (s *PayrollService) Close(ctx context.Context, periodID uint, format string, adminID uint) (Period, ExportFile, error) {
+	p, rows, err := s.Summaries(ctx, periodID)
+	if err != nil {
+		return Period{}, ExportFile{}, err
+	}
+	if p.Status == entity.StatusLocked {
+		return Period{}, ExportFile{}, ErrPeriodLocked
+	}
+
+	file, err := render(p, rows, format)
+	if err != nil {
+		return Period{}, ExportFile{}, err
+	}
+
+	locked, err := s.payroll.LockPeriod(ctx, p.ID, adminID, format, file.Checksum)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// ada request lain yang mengunci lebih dulu
+			return Period{}, ExportFile{}, ErrPeriodLocked
+		}
+		return Period{}, ExportFile{}, err
+	}
+	return locked, file, nil
+}
+
+func (s *PayrollService) getPeriod(ctx context.Context, id uint) (Period, error) {
+	p, err := s.payroll.GetPeriod(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Period{}, apperror.NotFound(i18n.PeriodNotFound)
+		}
+		return Period{}, err
+	}
+	return p, nil
+}
+
+func render(p Period, rows []Summary, format string) (ExportFile, error) {
+	if format == "" {
+		format = "csv"
+	}
+	exp, ok := exporterFor(format)
+	if !ok {
+		return ExportFile{}, apperror.Validation(i18n.ExportFormatUnsupported).With(format)
+	}
+
+	var buf bytes.Buffer
+	if err := exp.Export(&buf, p, rows); err != nil {
+		return ExportFile{}, err
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return ExportFile{
+		Filename: fmt.Sprintf("payroll_%s_%s.%s",
+			p.PeriodStart.Format("20060102"), p.PeriodEnd.Format("20060102"), exp.FileExt()),
+		ContentType: exp.ContentType(),
+		Checksum:    hex.EncodeToString(sum[:]),
+		Body:        buf.Bytes(),
+	}, nil
+}
@@ -0,0 +1,95 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+	entity "mojo-autotech/model/tenant"
+
+	"gorm.io/gorm"
+)
+
+type (
+	Tenant          = entity.Tenant
+	CreateTenantReq = entity.CreateTenantReq
+)
+
+// ErrUnknown: kode tenant tidak ada atau tenant dinonaktifkan.
+var ErrUnknown = apperror.Validation(i18n.TenantUnknown)
+
+var codePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+type ITenantService interface {
+	// Resolve mencari tenant aktif dari kode yang dikirim client; kode kosong
+	// berarti tenant default dari config.
+	Resolve(ctx context.Context, code string) (Tenant, error)
+	Create(ctx context.Context, req CreateTenantReq) (Tenant, error)
+	List(ctx context.Context) ([]Tenant, error)
+	// Active dipakai job latar belakang yang berjalan per tenant.
+	Active(ctx context.Context) ([]Tenant, error)
+}
+
+type TenantService struct {
+	tenant      entity.ITenantRepository
+	defaultCode string
+}
+
+func NewTenantService(repo entity.ITenantRepository, defaultCode string) *TenantService {
+	return &TenantService{
+		tenant:      repo,
+		defaultCode: defaultCode,
+	}
+}
+
+func (s *TenantService) Resolve(ctx context.Context, code string) (Tenant, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		code = s.defaultCode
+	}
+	t, err := s.tenant.GetByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Tenant{}, ErrUnknown
+		}
+		return Tenant{}, err
+	}
+	if !t.IsActive {
+		return Tenant{}, ErrUnknown
+	}
+	return t, nil
+}
+
+func (s *TenantService) Create(ctx context.Context, req CreateTenantReq) (Tenant, error) {
+	code := strings.ToLower(strings.TrimSpace(req.Code))
+	if len(code) > 40 || !codePattern.MatchString(code) {
+		return Tenant{}, apperror.Validation(i18n.TenantCodeInvalid)
+	}
+	if _, err := s.tenant.GetByCode(ctx, code); err == nil {
+		return Tenant{}, apperror.Conflict(i18n.TenantExists)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return Tenant{}, err
+	}
+	return s.tenant.Create(ctx, Tenant{Code: code, Name: strings.TrimSpace(req.Name), IsActive: true})
+}
+
+func (s *TenantService) List(ctx context.Context) ([]Tenant, error) {
+	return s.tenant.List(ctx)
+}
+
+func (s *TenantService) Active(ctx context.Context) ([]Tenant, error) {
+	all, err := s.tenant.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := all[:0]
+	for _, t := range all {
+		if t.IsActive {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
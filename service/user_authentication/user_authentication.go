@@ -3,12 +3,23 @@ package user_authentication
 import (
 	"context"
 	"errors"
-
-	"mojo-autotech/constant"
+	"log/slog"
+	"math"
+	"time"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/clock"
+	"mojo-autotech/config"
+	"mojo-autotech/i18n"
+	"mojo-autotech/metrics"
+	"mojo-autotech/tenant"
 	"mojo-autotech/utils"
 
 	"mojo-autotech/model/user_authentication"
 	entity "mojo-autotech/model/user_authentication"
+	tenantSvc "mojo-autotech/service/tenant"
+
+	"gorm.io/gorm"
 )
 
 type (
@@ -18,42 +29,99 @@ type (
 	User        = entity.User
 )
 
+var (
+	ErrBadCredentials = apperror.Unauthorized(i18n.BadCredentials)
+	ErrAccountLocked  = apperror.Locked(i18n.AccountLocked)
+)
+
+// Login memilih tenant dari req.Tenant; CreateAccount, ResetPassword dan
+// Deactivate memakai tenant di ctx (dari token admin atau flag --tenant CLI).
 type IAuthService interface {
 	Login(ctx context.Context, req LoginReq) (LoginRes, error)
 	CreateAccount(ctx context.Context, req RegisterReq) (res User, err error)
+	ResetPassword(ctx context.Context, username, password string) error
+	Deactivate(ctx context.Context, username string) error
 }
 
 type AuthService struct {
 	user_authentication user_authentication.IAuthRepository
+	tenants             tenantSvc.ITenantService
+	jwt                 *utils.JWT
+	cfg                 config.JWT
+	login               config.Login
+	clock               clock.Clock
 }
 
-func NewAuthService() *AuthService {
+func NewAuthService(repo user_authentication.IAuthRepository, tenants tenantSvc.ITenantService, jwt *utils.JWT, cfg config.JWT, login config.Login, clk clock.Clock) *AuthService {
 	return &AuthService{
-		user_authentication: user_authentication.NewAuthRepository(),
+		user_authentication: repo,
+		tenants:             tenants,
+		jwt:                 jwt,
+		cfg:                 cfg,
+		login:               login,
+		clock:               clk,
 	}
 }
 
 func (s *AuthService) Login(ctx context.Context, req LoginReq) (LoginRes, error) {
-	user, err := s.user_authentication.Login(ctx, req)
+	t, err := s.tenants.Resolve(ctx, req.Tenant)
 	if err != nil {
+		if errors.Is(err, tenantSvc.ErrUnknown) {
+			// tenant salah diperlakukan seperti password salah
+			slog.InfoContext(ctx, "login gagal, tenant tidak dikenal", "tenant", req.Tenant)
+			metrics.LoginFailures.WithLabelValues("bad_credentials").Inc()
+			return LoginRes{}, ErrBadCredentials
+		}
+		return LoginRes{}, err
+	}
+	ctx = tenant.WithID(ctx, t.ID)
+
+	user, err := s.user_authentication.Login(ctx, req)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// username tidak ada dan password salah dibuat sama supaya username tidak bisa ditebak
+		slog.InfoContext(ctx, "login gagal", "username", req.Username)
+		metrics.LoginFailures.WithLabelValues("bad_credentials").Inc()
+		return LoginRes{}, ErrBadCredentials
+	case errors.Is(err, entity.ErrPasswordMismatch):
+		if lockErr := s.lockedErr(user.LockedUntil); lockErr != nil {
+			metrics.LoginFailures.WithLabelValues("locked").Inc()
+			return LoginRes{}, lockErr
+		}
+		return LoginRes{}, s.recordFailure(ctx, user, req.Username)
+	case err != nil:
 		return LoginRes{}, err
 	}
 
+	// password benar tapi masih dalam masa kunci tetap ditolak
+	if lockErr := s.lockedErr(user.LockedUntil); lockErr != nil {
+		metrics.LoginFailures.WithLabelValues("locked").Inc()
+		return LoginRes{}, lockErr
+	}
+
 	// Status aktif?
 	if !user.IsActive {
-		return LoginRes{}, errors.New("akun tidak aktif")
+		slog.InfoContext(ctx, "login akun nonaktif", "username", req.Username)
+		metrics.LoginFailures.WithLabelValues("inactive").Inc()
+		return LoginRes{}, apperror.Forbidden(i18n.AccountInactive)
+	}
+
+	if err := s.user_authentication.RecordLogin(ctx, user.ID); err != nil {
+		return LoginRes{}, err
 	}
 
-	accessToken, expiresIn, err := utils.GenerateAccessToken(user.ID, user.Role, constant.AccessTTL)
+	accessToken, expiresIn, err := s.jwt.GenerateAccessToken(user.ID, t.ID, user.Role, s.cfg.AccessTTL)
 	if err != nil {
 		return LoginRes{}, err
 	}
-	refreshToken, err := utils.GenerateRefreshToken(user.ID, constant.RefreshTTL)
+	refreshToken, err := s.jwt.GenerateRefreshToken(user.ID, t.ID, s.cfg.RefreshTTL)
 	if err != nil {
 		return LoginRes{}, err
 	}
 
+	slog.InfoContext(ctx, "login berhasil", "login_user_id", user.ID)
 	return LoginRes{
+		TenantID:     t.ID,
 		Username:     user.Username,
 		Role:         user.Role,
 		Email:        user.Email,
@@ -64,11 +132,46 @@ func (s *AuthService) Login(ctx context.Context, req LoginReq) (LoginRes, error)
 	}, nil
 }
 
+// recordFailure mencatat password salah dan mengunci akun kalau batas tercapai.
+func (s *AuthService) recordFailure(ctx context.Context, user User, username string) error {
+	lockedUntil, err := s.user_authentication.RecordFailedLogin(ctx, user.ID, s.login.MaxFailed, s.login.Lockout)
+	if err != nil {
+		return err
+	}
+	metrics.LoginFailures.WithLabelValues("bad_credentials").Inc()
+	if lockErr := s.lockedErr(lockedUntil); lockErr != nil {
+		slog.WarnContext(ctx, "akun dikunci", "username", username, "locked_until", lockedUntil)
+		metrics.Lockouts.Inc()
+		return lockErr
+	}
+	slog.InfoContext(ctx, "login gagal", "username", username)
+	return ErrBadCredentials
+}
+
+// lockedErr mengembalikan error 423 kalau lockedUntil masih di masa depan.
+func (s *AuthService) lockedErr(lockedUntil *time.Time) error {
+	if lockedUntil == nil {
+		return nil
+	}
+	left := lockedUntil.Sub(s.clock.Now())
+	if left <= 0 {
+		return nil
+	}
+	return ErrAccountLocked.With(int(math.Ceil(left.Minutes())))
+}
+
 func (s *AuthService) CreateAccount(ctx context.Context, req RegisterReq) (User, error) {
 
 	if err := utils.ValidateCreateAccount(req); err != nil {
 		return User{}, err
 	}
+	n, err := s.user_authentication.CountDuplicate(ctx, req.Username, req.Email)
+	if err != nil {
+		return User{}, err
+	}
+	if n > 0 {
+		return User{}, apperror.Conflict(i18n.AccountExists)
+	}
 
 	hash, err := utils.HashPassword(req.Password)
 	if err != nil {
@@ -86,3 +189,30 @@ func (s *AuthService) CreateAccount(ctx context.Context, req RegisterReq) (User,
 	}
 	return created, nil
 }
+
+func (s *AuthService) ResetPassword(ctx context.Context, username, password string) error {
+	if len(password) < 8 {
+		return apperror.Validation(i18n.PasswordTooShort)
+	}
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := s.user_authentication.UpdatePassword(ctx, username, hash); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound(i18n.UsernameNotFound)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *AuthService) Deactivate(ctx context.Context, username string) error {
+	if err := s.user_authentication.SetActive(ctx, username, false); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound(i18n.UsernameNotFound)
+		}
+		return err
+	}
+	return nil
+}
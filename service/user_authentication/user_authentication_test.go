@@ -0,0 +1,147 @@
+package user_authentication
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/clock"
+	"mojo-autotech/config"
+	"mojo-autotech/fake"
+	tenantSvc "mojo-autotech/service/tenant"
+	"mojo-autotech/tenant"
+	"mojo-autotech/utils"
+)
+
+func newTestService() (*AuthService, *fake.AuthRepository, *clock.Fake) {
+	clk := clock.NewFake(time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC))
+	repo := fake.NewAuthRepository(clk)
+	jwtCfg := config.JWT{AccessTTL: 15 * time.Minute, RefreshTTL: time.Hour}
+	login := config.Login{MaxFailed: 3, Lockout: 15 * time.Minute}
+	tenants := tenantSvc.NewTenantService(fake.NewTenantRepository(clk), "default")
+	if _, err := tenants.Create(context.Background(), tenantSvc.CreateTenantReq{Code: "bengkel-b", Name: "Bengkel B"}); err != nil {
+		panic(err)
+	}
+	return NewAuthService(repo, tenants, utils.NewJWT("test-secret", "test"), jwtCfg, login, clk), repo, clk
+}
+
+func TestLoginSuccess(t *testing.T) {
+	svc, repo, _ := newTestService()
+	repo.AddUser(User{Username: "budi", Email: "budi@mojo.id", Role: "EMPLOYEE", IsActive: true}, "rahasia123")
+
+	// username tidak peka huruf besar/kecil
+	res, err := svc.Login(context.Background(), LoginReq{Username: "Budi", Password: "rahasia123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.AccessToken == "" || res.TokenType != "Bearer" {
+		t.Fatalf("response login = %+v", res)
+	}
+	if u, _ := repo.User("budi"); u.LastLoginAt == nil {
+		t.Fatal("last_login_at tidak dicatat")
+	}
+}
+
+func TestLoginUnknownUserLooksLikeWrongPassword(t *testing.T) {
+	svc, repo, _ := newTestService()
+	repo.AddUser(User{Username: "budi", IsActive: true}, "rahasia123")
+
+	_, errUnknown := svc.Login(context.Background(), LoginReq{Username: "siapa", Password: "x"})
+	_, errWrong := svc.Login(context.Background(), LoginReq{Username: "budi", Password: "x"})
+	if !errors.Is(errUnknown, ErrBadCredentials) || !errors.Is(errWrong, ErrBadCredentials) {
+		t.Fatalf("err = %v / %v, keduanya mau ErrBadCredentials", errUnknown, errWrong)
+	}
+}
+
+func TestLoginIsScopedPerTenant(t *testing.T) {
+	svc, repo, _ := newTestService()
+	repo.AddUser(User{Username: "budi", Role: "EMPLOYEE", IsActive: true}, "rahasia123")
+	repo.AddUser(User{TenantID: 2, Username: "budi", Role: "EMPLOYEE", IsActive: true}, "lain12345")
+
+	res, err := svc.Login(context.Background(), LoginReq{Tenant: "Bengkel-B", Username: "budi", Password: "lain12345"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TenantID != 2 {
+		t.Fatalf("tenant_id = %d, mau 2", res.TenantID)
+	}
+	// password tenant lain tidak berlaku, tenant tak dikenal seperti password salah
+	if _, err := svc.Login(context.Background(), LoginReq{Username: "budi", Password: "lain12345"}); !errors.Is(err, ErrBadCredentials) {
+		t.Fatalf("login tenant default: err = %v, mau ErrBadCredentials", err)
+	}
+	if _, err := svc.Login(context.Background(), LoginReq{Tenant: "siapa", Username: "budi", Password: "rahasia123"}); !errors.Is(err, ErrBadCredentials) {
+		t.Fatalf("tenant tak dikenal: err = %v, mau ErrBadCredentials", err)
+	}
+}
+
+func TestLoginLockoutAndExpiry(t *testing.T) {
+	svc, repo, clk := newTestService()
+	repo.AddUser(User{Username: "budi", IsActive: true}, "rahasia123")
+	ctx := context.Background()
+	wrong := LoginReq{Username: "budi", Password: "salah"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.Login(ctx, wrong); !errors.Is(err, ErrBadCredentials) {
+			t.Fatalf("percobaan ke-%d: err = %v", i+1, err)
+		}
+	}
+	_, err := svc.Login(ctx, wrong)
+	if apperror.CodeOf(err) != apperror.CodeLocked {
+		t.Fatalf("percobaan ke-3: err = %v, mau akun dikunci", err)
+	}
+
+	// password benar pun ditolak selama masa kunci
+	clk.Advance(14 * time.Minute)
+	if _, err := svc.Login(ctx, LoginReq{Username: "budi", Password: "rahasia123"}); apperror.CodeOf(err) != apperror.CodeLocked {
+		t.Fatalf("login saat terkunci: err = %v, mau LOCKED", err)
+	}
+
+	clk.Advance(time.Minute)
+	if _, err := svc.Login(ctx, LoginReq{Username: "budi", Password: "rahasia123"}); err != nil {
+		t.Fatalf("login setelah masa kunci: %v", err)
+	}
+	if u, _ := repo.User("budi"); u.LockedUntil != nil || u.FailedLogin != 0 {
+		t.Fatalf("login sukses harus membuka kunci: %+v", u)
+	}
+}
+
+func TestLoginInactive(t *testing.T) {
+	svc, repo, _ := newTestService()
+	repo.AddUser(User{Username: "budi", IsActive: false}, "rahasia123")
+
+	_, err := svc.Login(context.Background(), LoginReq{Username: "budi", Password: "rahasia123"})
+	if apperror.CodeOf(err) != apperror.CodeForbidden {
+		t.Fatalf("err = %v, mau FORBIDDEN", err)
+	}
+}
+
+func TestCreateAccountDuplicate(t *testing.T) {
+	svc, repo, _ := newTestService()
+	repo.AddUser(User{Username: "budi", Email: "budi@mojo.id", IsActive: true}, "rahasia123")
+
+	_, err := svc.CreateAccount(tenant.WithID(context.Background(), 1), RegisterReq{
+		UserId: 2, Username: "BUDI", Email: "lain@mojo.id", FullName: "Budi", Password: "rahasia123",
+	})
+	if apperror.CodeOf(err) != apperror.CodeConflict {
+		t.Fatalf("err = %v, mau CONFLICT", err)
+	}
+}
+
+func TestCreateAccountHashesPassword(t *testing.T) {
+	svc, repo, _ := newTestService()
+
+	if _, err := svc.CreateAccount(tenant.WithID(context.Background(), 1), RegisterReq{
+		UserId: 2, Username: "sari", Email: "sari@mojo.id", FullName: "Sari", Password: "rahasia123",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	u, ok := repo.User("sari")
+	if !ok || u.PasswordHash == "rahasia123" {
+		t.Fatalf("password harus disimpan sebagai hash: %+v", u)
+	}
+	if _, err := svc.Login(context.Background(), LoginReq{Username: "sari", Password: "rahasia123"}); err != nil {
+		t.Fatalf("login akun baru: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package work_location
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
+	entity "mojo-autotech/model/work_location"
+
+	"gorm.io/gorm"
+)
+
+type (
+	WorkLocation    = entity.WorkLocation
+	WorkLocationReq = entity.WorkLocationReq
+	IPPolicyReq     = entity.IPPolicyReq
+)
+
+type IWorkLocationService interface {
+	Create(ctx context.Context, req WorkLocationReq) (WorkLocation, error)
+	Update(ctx context.Context, id uint, req WorkLocationReq) (WorkLocation, error)
+	SetIPPolicy(ctx context.Context, id uint, req IPPolicyReq) (WorkLocation, error)
+	List(ctx context.Context) ([]WorkLocation, error)
+}
+
+type WorkLocationService struct {
+	location entity.IWorkLocationRepository
+}
+
+func NewWorkLocationService(repo entity.IWorkLocationRepository) *WorkLocationService {
+	return &WorkLocationService{
+		location: repo,
+	}
+}
+
+func (s *WorkLocationService) Create(ctx context.Context, req WorkLocationReq) (WorkLocation, error) {
+	w := WorkLocation{IsActive: true, IPPolicy: entity.IPPolicyOff}
+	if err := apply(&w, req); err != nil {
+		return WorkLocation{}, err
+	}
+	return s.location.Create(ctx, w)
+}
+
+func (s *WorkLocationService) Update(ctx context.Context, id uint, req WorkLocationReq) (WorkLocation, error) {
+	w, err := s.location.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return WorkLocation{}, apperror.NotFound(i18n.LocationNotFound)
+		}
+		return WorkLocation{}, err
+	}
+	if err := apply(&w, req); err != nil {
+		return WorkLocation{}, err
+	}
+	if _, err := s.location.Update(ctx, w); err != nil {
+		return WorkLocation{}, err
+	}
+	return s.location.GetByID(ctx, id)
+}
+
+// SetIPPolicy mengganti allowlist jaringan dan policy tanpa menyentuh data lokasi lain.
+func (s *WorkLocationService) SetIPPolicy(ctx context.Context, id uint, req IPPolicyReq) (WorkLocation, error) {
+	w, err := s.location.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return WorkLocation{}, apperror.NotFound(i18n.LocationNotFound)
+		}
+		return WorkLocation{}, err
+	}
+	if req.Policy != entity.IPPolicyOff && len(req.Networks) == 0 {
+		return WorkLocation{}, apperror.Validation(i18n.NetworksRequired)
+	}
+	w.Networks = strings.Join(req.Networks, ",")
+	w.IPPolicy = req.Policy
+	if _, err := s.location.Update(ctx, w); err != nil {
+		return WorkLocation{}, err
+	}
+	return s.location.GetByID(ctx, id)
+}
+
+func (s *WorkLocationService) List(ctx context.Context) ([]WorkLocation, error) {
+	return s.location.List(ctx)
+}
+
+// apply menyalin req ke w. Networks yang tidak dikirim (null) mempertahankan
+// allowlist lama; policy selain OFF tanpa jaringan ditolak seperti di SetIPPolicy.
+func apply(w *WorkLocation, req WorkLocationReq) error {
+	w.Name = req.Name
+	w.Address = req.Address
+	w.Lat, w.Lng = req.Lat, req.Lng
+	if req.RadiusM > 0 {
+		w.RadiusM = req.RadiusM
+	} else if w.RadiusM == 0 {
+		w.RadiusM = 200
+	}
+	if req.Networks != nil {
+		w.Networks = strings.Join(req.Networks, ",")
+	}
+	if req.IPPolicy != "" {
+		w.IPPolicy = req.IPPolicy
+	}
+	if w.IPPolicy != "" && w.IPPolicy != entity.IPPolicyOff && !w.HasNetworks() {
+		return apperror.Validation(i18n.NetworksRequired)
+	}
+	w.Timezone = req.Timezone
+	if req.IsActive != nil {
+		w.IsActive = *req.IsActive
+	}
+	return nil
+}
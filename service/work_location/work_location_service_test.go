@@ -0,0 +1,44 @@
+package work_location
+
+import (
+	"context"
+	"testing"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/fake"
+	entity "mojo-autotech/model/work_location"
+	"mojo-autotech/tenant"
+)
+
+func TestIPPolicyNeedsNetworks(t *testing.T) {
+	svc := NewWorkLocationService(fake.NewWorkLocationRepository())
+	ctx := tenant.WithID(context.Background(), 1)
+
+	_, err := svc.Create(ctx, WorkLocationReq{Name: "Workshop", IPPolicy: entity.IPPolicyReject})
+	if apperror.CodeOf(err) != apperror.CodeValidation {
+		t.Fatalf("REJECT tanpa networks: err = %v, mau validasi", err)
+	}
+
+	w, err := svc.Create(ctx, WorkLocationReq{Name: "Workshop", Networks: []string{"10.10.0.0/16"}, IPPolicy: entity.IPPolicyReject})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// PUT tanpa networks mempertahankan allowlist
+	w, err = svc.Update(ctx, w.ID, WorkLocationReq{Name: "Workshop Utama"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Networks != "10.10.0.0/16" || w.IPPolicy != entity.IPPolicyReject {
+		t.Fatalf("setelah update tanpa networks: %+v", w)
+	}
+
+	// array kosong menghapus allowlist, jadi ditolak selama policy masih REJECT
+	if _, err := svc.Update(ctx, w.ID, WorkLocationReq{Name: "Workshop Utama", Networks: []string{}}); apperror.CodeOf(err) != apperror.CodeValidation {
+		t.Fatalf("hapus networks dengan policy REJECT: err = %v, mau validasi", err)
+	}
+	w, err = svc.Update(ctx, w.ID, WorkLocationReq{Name: "Workshop Utama", Networks: []string{}, IPPolicy: entity.IPPolicyOff})
+	if err != nil || w.Networks != "" {
+		t.Fatalf("hapus networks dengan policy OFF: %+v err=%v", w, err)
+	}
+}
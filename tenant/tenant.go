@@ -0,0 +1,36 @@
+// Package tenant membawa ID perusahaan (tenant) lewat context. Auth mengisi
+// ID dari token, kiosk dari device key, job latar belakang per tenant.
+// Repository membaca ID dari sini dan menolak query tanpa tenant, jadi lupa
+// mengisi context berakhir dengan error, bukan data perusahaan lain.
+package tenant
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrMissing dikembalikan repository kalau context tidak membawa tenant.
+var ErrMissing = errors.New("tenant tidak ada di context")
+
+type ctxKey struct{}
+
+// WithID mengembalikan context turunan dengan tenant id.
+func WithID(ctx context.Context, id uint) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext mengembalikan tenant id di ctx; ok=false kalau tidak ada.
+func FromContext(ctx context.Context) (uint, bool) {
+	id, ok := ctx.Value(ctxKey{}).(uint)
+	return id, ok && id != 0
+}
+
+// ID seperti FromContext tetapi mengembalikan ErrMissing; dipakai di awal
+// setiap method repository.
+func ID(ctx context.Context) (uint, error) {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return 0, ErrMissing
+	}
+	return id, nil
+}
@@ -0,0 +1,112 @@
+// Package tracing menyiapkan OpenTelemetry: TracerProvider global dengan
+// exporter OTLP/HTTP atau stdout, propagasi W3C trace-context, dan helper
+// untuk menutup span dengan status sesuai error aplikasi.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	"mojo-autotech/apperror"
+	"mojo-autotech/config"
+	"mojo-autotech/version"
+)
+
+// ServiceName dipakai sebagai service.name dan nama instrumentasi HTTP.
+const ServiceName = "mojo-autotech"
+
+// Setup memasang TracerProvider dan propagator global. Propagator selalu
+// dipasang supaya traceparent dari upstream tetap diteruskan walau exporter
+// "none". Fungsi yang dikembalikan mengirim sisa span lalu menutup exporter.
+func Setup(ctx context.Context, cfg *config.Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+	if !cfg.Tracing.Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exp, err := newExporter(ctx, cfg.Tracing)
+	if err != nil {
+		return nil, fmt.Errorf("membuat exporter %s: %w", cfg.Tracing.Exporter, err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(ServiceName),
+		semconv.ServiceVersion(version.Get().Commit),
+		semconv.DeploymentEnvironmentName(cfg.Env),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(res),
+		// trace dari upstream mengikuti keputusan sampling parent
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.Tracing.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, cfg config.Tracing) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case "stdout":
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case "otlp":
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	}
+	return nil, fmt.Errorf("exporter %q tidak dikenal", cfg.Exporter)
+}
+
+// Tracer mengambil tracer dari provider global; aman dipanggil sebelum Setup
+// karena provider global meneruskan ke provider yang dipasang belakangan.
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(ServiceName + "/" + name)
+}
+
+// End menutup span dan mencatat err. Dipakai dengan defer dan named return:
+//
+//	ctx, span := tracer.Start(ctx, "AttendanceService.CheckIn")
+//	defer tracing.End(span, &err)
+//
+// Hanya error INTERNAL yang membuat span berstatus Error; error bisnis
+// (validasi, conflict, not found) cukup dicatat kodenya supaya dashboard
+// error rate tidak penuh oleh kesalahan input karyawan.
+func End(span trace.Span, err *error) {
+	defer span.End()
+	if err == nil || *err == nil {
+		return
+	}
+	if errors.Is(*err, gorm.ErrRecordNotFound) {
+		span.SetAttributes(attribute.Bool("db.not_found", true))
+		return
+	}
+	code := apperror.CodeOf(*err)
+	span.SetAttributes(attribute.String("app.error_code", string(code)))
+	if code == apperror.CodeInternal {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+}
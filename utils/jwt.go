@@ -4,66 +4,78 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
 type AccessClaims struct {
-	UID    uint   `json:"uid"`
-	Role   string `json:"role"`
-	UserId uint   `json:"user_id"`
+	UID      uint   `json:"uid"`
+	TenantID uint   `json:"tid"`
+	Role     string `json:"role"`
+	UserId   uint   `json:"user_id"`
 	jwt.RegisteredClaims
 }
 
 type RefreshClaims struct {
-	UID    uint `json:"uid"`
-	UserId uint `json:"user_id"`
+	UID      uint `json:"uid"`
+	TenantID uint `json:"tid"`
+	UserId   uint `json:"user_id"`
 	// jti disimpan di RegisteredClaims.ID
 	jwt.RegisteredClaims
 }
 
-func jwtSecret() ([]byte, error) {
-	secret := os.Getenv("AUTH_JWT_SECRET")
-	if secret == "" {
-		return nil, errors.New("AUTH_JWT_SECRET belum di-set")
-	}
-	return []byte(secret), nil
+// KioskQRClaims adalah isi QR code yang ditampilkan kiosk. Umurnya pendek
+// sehingga foto QR tidak bisa dipakai ulang dari luar lokasi.
+type KioskQRClaims struct {
+	KioskID uint `json:"kid"`
+	jwt.RegisteredClaims
 }
 
-func GenerateAccessToken(uid uint, role string, ttl time.Duration) (token string, expiresIn int64, err error) {
-	secret, err := jwtSecret()
-	if err != nil {
-		return "", 0, err
+const kioskQRSubject = "kiosk_qr"
+
+// JWT menandatangani dan memverifikasi token HS256 dengan secret dari config.
+type JWT struct {
+	secret []byte
+	issuer string
+}
+
+func NewJWT(secret, issuer string) *JWT {
+	return &JWT{secret: []byte(secret), issuer: issuer}
+}
+
+func (j *JWT) keyFunc(t *jwt.Token) (interface{}, error) {
+	if t.Method.Alg() != jwt.SigningMethodHS256.Alg() {
+		return nil, errors.New("unexpected signing method")
 	}
+	return j.secret, nil
+}
+
+func (j *JWT) GenerateAccessToken(uid, tenantID uint, role string, ttl time.Duration) (token string, expiresIn int64, err error) {
 	now := time.Now()
 	exp := now.Add(ttl)
 
 	claims := AccessClaims{
-		UID:    uid,
-		Role:   role,
-		UserId: uid,
+		UID:      uid,
+		TenantID: tenantID,
+		Role:     role,
+		UserId:   uid,
 		RegisteredClaims: jwt.RegisteredClaims{
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(exp),
-			// Opsional: isi Issuer/Audience jika perlu
+			Issuer:    j.issuer,
 		},
 	}
 	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signed, err := t.SignedString(secret)
+	signed, err := t.SignedString(j.secret)
 	if err != nil {
 		return "", 0, err
 	}
 	return signed, int64(time.Until(exp).Seconds()), nil
 }
 
-func GenerateRefreshToken(uid uint, ttl time.Duration) (string, error) {
-	secret, err := jwtSecret()
-	if err != nil {
-		return "", err
-	}
+func (j *JWT) GenerateRefreshToken(uid, tenantID uint, ttl time.Duration) (string, error) {
 	now := time.Now()
 	exp := now.Add(ttl)
 
@@ -73,8 +85,9 @@ func GenerateRefreshToken(uid uint, ttl time.Duration) (string, error) {
 	}
 
 	claims := RefreshClaims{
-		UID:    uid,
-		UserId: uid,
+		UID:      uid,
+		TenantID: tenantID,
+		UserId:   uid,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject:   "refresh",
 			ID:        jti,
@@ -85,7 +98,55 @@ func GenerateRefreshToken(uid uint, ttl time.Duration) (string, error) {
 	}
 
 	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return t.SignedString(secret)
+	return t.SignedString(j.secret)
+}
+
+func (j *JWT) GenerateKioskQRToken(kioskID uint, ttl time.Duration) (token string, expiresAt time.Time, err error) {
+	now := time.Now()
+	exp := now.Add(ttl)
+
+	claims := KioskQRClaims{
+		KioskID: kioskID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   kioskQRSubject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(exp),
+		},
+	}
+	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := t.SignedString(j.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, exp, nil
+}
+
+// ParseKioskQRToken memvalidasi tanda tangan & masa berlaku QR, lalu mengembalikan id kiosk.
+func (j *JWT) ParseKioskQRToken(token string) (uint, error) {
+	parsed, err := jwt.ParseWithClaims(token, &KioskQRClaims{}, j.keyFunc)
+	if err != nil || !parsed.Valid {
+		return 0, errors.New("QR kiosk tidak valid atau kedaluwarsa")
+	}
+	claims, ok := parsed.Claims.(*KioskQRClaims)
+	if !ok || claims.Subject != kioskQRSubject || claims.KioskID == 0 {
+		return 0, errors.New("QR kiosk tidak valid atau kedaluwarsa")
+	}
+	return claims.KioskID, nil
+}
+
+// ParseAccessToken memvalidasi access token dari header Authorization. Token
+// tanpa tenant (dibuat sebelum multi-tenant) ditolak supaya client login ulang.
+func (j *JWT) ParseAccessToken(token string) (*AccessClaims, error) {
+	parsed, err := jwt.ParseWithClaims(token, &AccessClaims{}, j.keyFunc)
+	if err != nil || !parsed.Valid {
+		return nil, errors.New("invalid token")
+	}
+	claims, ok := parsed.Claims.(*AccessClaims)
+	if !ok || claims.TenantID == 0 {
+		return nil, errors.New("invalid claims")
+	}
+	return claims, nil
 }
 
 func randomJTI(n int) (string, error) {
@@ -1,26 +1,27 @@
 package utils
 
 import (
-	"errors"
 	"net/mail"
 
+	"mojo-autotech/apperror"
+	"mojo-autotech/i18n"
 	"mojo-autotech/model/user_authentication"
 )
 
 func ValidateCreateAccount(req user_authentication.RegisterReq) error {
 
 	if req.Username == "" {
-		return errors.New("username is empty")
+		return apperror.Validation(i18n.UsernameEmpty)
 	}
 	if req.Email == "" {
-		return errors.New("email is empty")
+		return apperror.Validation(i18n.EmailEmpty)
 	}
 
 	if _, err := mail.ParseAddress(req.Email); err != nil {
-		return errors.New("email is not valid")
+		return apperror.Validation(i18n.EmailInvalid)
 	}
 	if req.Password == "" {
-		return errors.New("password is empty")
+		return apperror.Validation(i18n.PasswordEmpty)
 	}
 	return nil
 }
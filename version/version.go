@@ -0,0 +1,48 @@
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Diisi saat build:
+//
+//	go build -ldflags "-X mojo-autotech/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X mojo-autotech/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Kalau kosong, diambil dari info VCS yang disematkan go build.
+var (
+	Commit    = ""
+	BuildTime = ""
+)
+
+type Info struct {
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+	Modified  bool   `json:"modified,omitempty"` // build dari working tree yang belum di-commit
+}
+
+func Get() Info {
+	info := Info{Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = s.Value
+				}
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	return info
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"gorm.io/gorm"
+
+	"mojo-autotech/clock"
+	"mojo-autotech/config"
+	"mojo-autotech/scheduler"
+	"mojo-autotech/tenant"
+	"mojo-autotech/utils"
+
+	anomalyRepo "mojo-autotech/model/anomaly"
+	attRepo "mojo-autotech/model/attedance"
+	idemRepo "mojo-autotech/model/idempotency"
+	kioskRepo "mojo-autotech/model/kiosk"
+	syncRepo "mojo-autotech/model/offline_sync"
+	orgRepo "mojo-autotech/model/org_structure"
+	payRepo "mojo-autotech/model/payroll"
+	tenantRepo "mojo-autotech/model/tenant"
+	authRepo "mojo-autotech/model/user_authentication"
+	wlRepo "mojo-autotech/model/work_location"
+
+	anomalySvc "mojo-autotech/service/anomaly"
+	attSvc "mojo-autotech/service/attedance"
+	kioskSvc "mojo-autotech/service/kiosk"
+	syncSvc "mojo-autotech/service/offline_sync"
+	orgSvc "mojo-autotech/service/org_structure"
+	paySvc "mojo-autotech/service/payroll"
+	tenantSvc "mojo-autotech/service/tenant"
+	authSvc "mojo-autotech/service/user_authentication"
+	wlSvc "mojo-autotech/service/work_location"
+)
+
+// services berisi semua service aplikasi yang berbagi satu pool *gorm.DB.
+type services struct {
+	tenants      tenantSvc.ITenantService
+	auth         authSvc.IAuthService
+	attendance   attSvc.IAttendanceService
+	payroll      paySvc.IPayrollService
+	kiosk        kioskSvc.IKioskService
+	offlineSync  syncSvc.IOfflineSyncService
+	workLocation wlSvc.IWorkLocationService
+	anomaly      anomalySvc.IAnomalyService
+	org          orgSvc.IOrgService
+
+	// idempotency dipakai langsung oleh middleware, tidak lewat service
+	idempotency idemRepo.IIdempotencyRepository
+	jwt         *utils.JWT
+}
+
+func newServices(db *gorm.DB, cfg *config.Config) *services {
+	var (
+		authR     = authRepo.NewAuthRepository(db, clock.System)
+		attR      = attRepo.NewAttendanceRepository(db, clock.System)
+		payR      = payRepo.NewPayrollRepository(db, clock.System)
+		kioskR    = kioskRepo.NewKioskRepository(db, clock.System)
+		syncR     = syncRepo.NewOfflineSyncRepository(db, clock.System)
+		locationR = wlRepo.NewWorkLocationRepository(db)
+		anomalyR  = anomalyRepo.NewAnomalyRepository(db, clock.System)
+		tenantR   = tenantRepo.NewTenantRepository(db)
+		orgR      = orgRepo.NewOrgRepository(db, clock.System)
+	)
+
+	jwt := utils.NewJWT(cfg.JWT.Secret.Value(), cfg.JWT.Issuer)
+	tenants := tenantSvc.NewTenantService(tenantR, cfg.Tenant.Default)
+
+	// Antrean review tetap bisa dibuka walau deteksi untuk punch baru dimatikan
+	anomaly := anomalySvc.NewAnomalyService(anomalyR, locationR)
+	var inspector anomalySvc.IAnomalyService
+	if cfg.Features.AnomalyDetection {
+		inspector = anomaly
+	}
+	attendance := attSvc.NewAttendanceService(attR, kioskR, locationR, inspector, jwt, cfg.Location(), cfg.Shift, clock.System)
+
+	return &services{
+		tenants:      tenants,
+		auth:         authSvc.NewAuthService(authR, tenants, jwt, cfg.JWT, cfg.Login, clock.System),
+		attendance:   attendance,
+		payroll:      paySvc.NewPayrollService(payR),
+		kiosk:        kioskSvc.NewKioskService(kioskR, attendance, jwt, cfg.Kiosk, clock.System),
+		offlineSync:  syncSvc.NewOfflineSyncService(syncR, attendance, cfg.Sync, clock.System),
+		workLocation: wlSvc.NewWorkLocationService(locationR),
+		anomaly:      anomaly,
+		org:          orgSvc.NewOrgService(orgR, clock.System),
+
+		idempotency: idemRepo.NewIdempotencyRepository(db, clock.System),
+		jwt:         jwt,
+	}
+}
+
+// newScheduler mendaftarkan job latar belakang sesuai feature toggle.
+func newScheduler(svc *services, cfg *config.Config) *scheduler.Scheduler {
+	s := scheduler.New()
+	if cfg.Features.AutoCheckout {
+		at := cfg.Schedule.AutoCheckoutAt
+		s.Add(scheduler.Job{
+			Name:     "auto-checkout",
+			Interval: cfg.Schedule.AutoCheckoutInterval,
+			Run: func(ctx context.Context) error {
+				return forEachTenant(ctx, svc, func(ctx context.Context) error {
+					n, err := svc.attendance.AutoCheckOut(ctx, at)
+					if n > 0 {
+						slog.InfoContext(ctx, "auto-checkout selesai", "job", "auto-checkout", "closed", n)
+					}
+					return err
+				})
+			},
+		})
+	}
+	if cfg.Idempotency.Enabled {
+		s.Add(scheduler.Job{
+			Name:     "idempotency-cleanup",
+			Interval: cfg.Idempotency.CleanupInterval,
+			Run: func(ctx context.Context) error {
+				n, err := svc.idempotency.DeleteExpired(ctx)
+				if n > 0 {
+					slog.InfoContext(ctx, "idempotency key kedaluwarsa dihapus", "job", "idempotency-cleanup", "deleted", n)
+				}
+				return err
+			},
+		})
+	}
+	return s
+}
+
+// forEachTenant menjalankan fn sekali per tenant aktif dengan context tenant
+// tersebut. Tenant yang gagal tidak menghentikan tenant lain.
+func forEachTenant(ctx context.Context, svc *services, fn func(ctx context.Context) error) error {
+	tenants, err := svc.tenants.Active(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, t := range tenants {
+		if err := fn(tenant.WithID(ctx, t.ID)); err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", t.Code, err))
+		}
+	}
+	return errors.Join(errs...)
+}